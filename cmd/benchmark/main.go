@@ -1,35 +1,800 @@
 package main
 
 import (
+	"cmp"
 	"flag"
 	"fmt"
 	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/mysql"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/sqlite"
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
 	"github.com/moguls753/uuid-benchmark/internal/container"
 	"github.com/moguls753/uuid-benchmark/internal/display"
 	"github.com/moguls753/uuid-benchmark/internal/export"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
 	"github.com/moguls753/uuid-benchmark/internal/runner"
+	"github.com/moguls753/uuid-benchmark/internal/server"
+	"github.com/moguls753/uuid-benchmark/internal/sysinfo"
 )
 
-var allKeyTypes = []string{"bigserial", "uuidv4", "uuidv7", "ulid", "ulid_monotonic", "uuidv1"}
+// cacheCompetitionSharedBuffers is the buffer pool size used by the cache-competition
+// scenario, small enough that heap and index pages must compete for it
+const cacheCompetitionSharedBuffers = "16MB"
+
+// workingSetSharedBuffers is the buffer pool size used by the working-set-sweep
+// scenario, so that several multiples of it load quickly
+const workingSetSharedBuffers = "16MB"
+
+// reindexCycles is the number of insert bursts, each followed by a reindex, in the
+// reindex-maintenance scenario
+const reindexCycles = 5
+
+// updateChurnRounds is the number of update rounds, each followed by a bloat sample, in
+// the update-churn scenario
+const updateChurnRounds = 10
+
+// upsertConflictRatio is the percentage of upserts on existing ids in the
+// upsert-performance scenario, from -conflict-ratio
+var upsertConflictRatio = 50
+
+// rangeScanSize is the number of rows each range-scan scan reads, from -range-size
+var rangeScanSize = 100
+
+// portableScenarios are the scenarios -db mysql and -db sqlite can run
+var portableScenarios = []string{"insert-performance", "read-after-fragmentation", "update-performance"}
+
+// postgresOnlyFlags configure pgbench, PostgreSQL or the table layout, which the MySQL
+// and SQLite benchmarkers do not implement
+var postgresOnlyFlags = []string{
+	"serve", "export-figures", "fail-on-missing-extension", "via-pgbouncer", "pg-tuning", "wal-compression",
+	"pgbench-warmup", "rate", "latency-limit", "query-mode", "compare-query-modes", "pgbench-log-dir", "duration",
+	"insert-mode", "preload", "remeasure", "record", "replay", "measure-bloat", "autovacuum", "verify-data",
+	"include-ddl-timing", "extra-indexes", "id-column", "data-type", "data-null",
+	"timestamp-skew", "timestamp-skew-rate", "uuidv8-time-bits", "insert-order",
+	"size-sampling", "size-sampling-output", "pg-host", "pg-port", "pg-user", "pg-password", "pg-database", "pg-sslmode", "pg-container",
+}
+
+// containerOnlyFlags restart, reset or front the benchmark's own PostgreSQL container,
+// which an external server (-pg-host) is not
+var containerOnlyFlags = []string{"via-pgbouncer", "pg-tuning", "wal-compression", "keep-container"}
+
+// containerOnlyScenarios shrink shared_buffers by restarting the container
+var containerOnlyScenarios = []string{"cache-competition", "working-set-sweep"}
+
+// figureScales are the dataset sizes, as fractions of -num-records, of the
+// fragmentation-vs-scale figure written by -export-figures
+var figureScales = []float64{0.1, 0.25, 0.5, 1}
+
+// connectionScalingClients are the client counts of the connection-scaling scenario,
+// kept below the server's default max_connections of 100
+var connectionScalingClients = []int{1, 2, 4, 8, 16, 32, 64}
+
+// commitOverheadBatchSizes are the batch sizes swept by the commit-overhead scenario
+var commitOverheadBatchSizes = []int{1, 10, 100, 1000}
+
+// knownKeyTypes are all supported key types, in default run order
+var knownKeyTypes = []string{"bigserial", "uuidv4", "uuidv7", "ulid", "ulid_monotonic", "uuidv1", "uuidv6", "uuidv8", "ksuid", "snowflake"}
+
+// parseKeyTypes parses -key-types, e.g. "uuidv4,uuidv7", keeping the given order
+func parseKeyTypes(spec string) ([]string, error) {
+	var keyTypes []string
+	for _, entry := range strings.Split(spec, ",") {
+		keyType := strings.ToLower(strings.TrimSpace(entry))
+		if !slices.Contains(knownKeyTypes, keyType) {
+			return nil, fmt.Errorf("unknown key type %q (valid: %s)", keyType, strings.Join(knownKeyTypes, ", "))
+		}
+		if slices.Contains(keyTypes, keyType) {
+			return nil, fmt.Errorf("key type %s listed twice", keyType)
+		}
+		keyTypes = append(keyTypes, keyType)
+	}
+	return keyTypes, nil
+}
+
+// Initial dataset sizes loaded before each mixed workload
+var (
+	insertHeavyDataset = 100000
+	readHeavyDataset   = 1000000
+	balancedDataset    = 500000
+)
+
+// datasetPreset holds the scale parameters selected with -preset
+type datasetPreset struct {
+	numRecords         int
+	numOps             int
+	connections        int
+	numRuns            int
+	insertHeavyDataset int
+	readHeavyDataset   int
+	balancedDataset    int
+}
+
+var datasetPresets = map[string]datasetPreset{
+	// Smoke test: a full -scenario all sweep finishes in a few minutes
+	"small": {
+		numRecords:         10000,
+		numOps:             2000,
+		connections:        1,
+		numRuns:            1,
+		insertHeavyDataset: 10000,
+		readHeavyDataset:   20000,
+		balancedDataset:    10000,
+	},
+	// Quick comparison on a laptop with basic statistics
+	"medium": {
+		numRecords:         100000,
+		numOps:             10000,
+		connections:        4,
+		numRuns:            3,
+		insertHeavyDataset: 100000,
+		readHeavyDataset:   500000,
+		balancedDataset:    250000,
+	},
+	// Serious measurement: indexes well beyond shared_buffers, 5 runs for significance tests
+	"large": {
+		numRecords:         1000000,
+		numOps:             100000,
+		connections:        10,
+		numRuns:            5,
+		insertHeavyDataset: 1000000,
+		readHeavyDataset:   5000000,
+		balancedDataset:    2000000,
+	},
+}
+
+// baselineKeyType is the key type every other one is compared against, from -baseline
+var baselineKeyType = "bigserial"
+
+// referenceBaselineSuffix labels the -compare-baseline-file reference in comparisons,
+// e.g. "bigserial_ref"
+const referenceBaselineSuffix = "_ref"
+
+// referenceBaseline holds the baseline key type's stats loaded from
+// -compare-baseline-file; nil means compare against the current run's baseline
+var referenceBaseline map[string]statistics.Stats
+
+// comparisonBaseline returns the baseline key and the results to compare against: the
+// current run's -baseline key type, or the reference run added under its name plus
+// referenceBaselineSuffix
+func comparisonBaseline(results map[string]map[string]statistics.Stats) (string, map[string]map[string]statistics.Stats) {
+	if referenceBaseline == nil {
+		return baselineKeyType, results
+	}
+
+	merged := make(map[string]map[string]statistics.Stats, len(results)+1)
+	for keyType, stats := range results {
+		merged[keyType] = stats
+	}
+	key := baselineKeyType + referenceBaselineSuffix
+	merged[key] = referenceBaseline
+
+	return key, merged
+}
+
+// scenarioBatchSizes holds per-scenario -scenario-batch-size overrides of -batch-size
+var scenarioBatchSizes = map[string]int{}
+
+// batchedScenarios are the scenarios whose workload uses the batch size
+var batchedScenarios = []string{"insert-performance", "jsonb-gin", "reindex-maintenance", "insert-order"}
+
+// parseScenarioBatchSizes parses "insert-performance=1000,jsonb-gin=100"
+func parseScenarioBatchSizes(spec string) (map[string]int, error) {
+	sizes := make(map[string]int)
+	if spec == "" {
+		return sizes, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected scenario=size, got %q", entry)
+		}
+		if !slices.Contains(batchedScenarios, name) {
+			return nil, fmt.Errorf("scenario %s does not use a batch size (valid: %s)", name, strings.Join(batchedScenarios, ", "))
+		}
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid batch size for %s: %q", name, value)
+		}
+		sizes[name] = size
+	}
+
+	return sizes, nil
+}
+
+// scoreWeights is the efficiency score basket, from -score-weights
+var scoreWeights = display.DefaultScoreWeights
+
+// parseScoreWeights parses -score-weights, e.g. "throughput=2,page_splits=1"
+func parseScoreWeights(spec string) (map[string]float64, error) {
+	if spec == "" {
+		return display.DefaultScoreWeights, nil
+	}
+
+	weights := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected metric=weight, got %q", entry)
+		}
+		if _, known := metric.Lookup(name); !known {
+			return nil, fmt.Errorf("unknown metric %q (see -list-metrics)", name)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight for %s: %q", name, value)
+		}
+		weights[name] = weight
+	}
+
+	return weights, nil
+}
+
+// parsePercentiles parses -percentiles, e.g. "50,90,99,99.9", into ascending percentiles
+func parsePercentiles(spec string) ([]float64, error) {
+	var percentiles []float64
+	for _, entry := range strings.Split(spec, ",") {
+		percentile, err := strconv.ParseFloat(strings.TrimSpace(entry), 64)
+		if err != nil || percentile <= 0 || percentile >= 100 {
+			return nil, fmt.Errorf("expected percentiles between 0 and 100, got %q", entry)
+		}
+		if !slices.Contains(percentiles, percentile) {
+			percentiles = append(percentiles, percentile)
+		}
+	}
+	sort.Float64s(percentiles)
+	return percentiles, nil
+}
+
+// batchSizeFor returns the batch size for a scenario, honoring -scenario-batch-size
+func batchSizeFor(scenario string, batchSize int) int {
+	if size, ok := scenarioBatchSizes[scenario]; ok {
+		return size
+	}
+	return batchSize
+}
 
 func main() {
-	scenario := flag.String("scenario", "insert-performance", "Scenario to run (insert-performance, read-after-fragmentation, update-performance, mixed-insert-heavy, mixed-read-heavy, mixed-balanced, all)")
+	scenario := flag.String("scenario", "insert-performance", "Scenario to run (insert-performance, read-after-fragmentation, update-performance, mixed-insert-heavy, mixed-read-heavy, mixed-balanced, jsonb-gin, commit-overhead, insert-returning, upsert-performance, range-scan, reindex-maintenance, update-churn, connection-scaling, insert-order, cache-competition, working-set-sweep, all)")
 	numRecords := flag.Int("num-records", 100000, "Number of records for insert operations")
 	numOps := flag.Int("num-ops", 10000, "Number of operations for read/update/mixed scenarios")
 	connections := flag.Int("connections", 1, "Number of concurrent connections")
 	batchSize := flag.Int("batch-size", 100, "Batch size for inserts/updates")
+	scenarioBatchSize := flag.String("scenario-batch-size", "", "Per-scenario batch size overrides, e.g. insert-performance=1000,jsonb-gin=100 (applies to -scenario all too)")
 	numRuns := flag.Int("num-runs", 1, "Number of runs per UUID type (for statistical analysis)")
+	trimOutliersFlag := flag.Bool("trim-outliers", false, "Exclude runs outside 1.5×IQR of a metric from its median, mean, stddev and CI (multi-run mode; raw values are still exported)")
+	warmupRuns := flag.Int("warmup-runs", 0, "Discarded insert-performance runs per UUID type before the measured -num-runs, on identically started containers (multi-run mode)")
 	output := flag.String("output", "", "Output CSV file for statistical results (only in multi-run mode)")
+	compareBaselineFile := flag.String("compare-baseline-file", "", "JSON summary (from -output) whose -baseline key type stats are the fixed baseline for comparisons (multi-run mode)")
+	gnuplot := flag.String("gnuplot", "", "Write <base>.dat and a <base>.gp gnuplot script charting throughput, page splits, fragmentation and index size (only in multi-run mode)")
+	jsonOutputFlag := flag.String("json-output", "", "JSON file the raw results of every scenario are written to after it completes, keyed by scenario then key type (durations in nanoseconds)")
+	markdownOutput := flag.String("markdown-output", "", "Markdown file the comparison tables are written to as GitHub-flavored tables, with the console's rows and units, once every scenario has run")
+	benchstatOutput := flag.String("benchstat-output", "", "Write insert-performance runs in Go benchmark format for benchstat: p50 latency as ns/op and throughput as rec/s (only in multi-run mode)")
+	resultsDB := flag.String("results-db", "", "SQLite file to append statistical results to (only in multi-run mode); created if absent")
+	pgTuning := flag.String("pg-tuning", "", "JSON file of PostgreSQL settings applied via ALTER SYSTEM after each container start")
+	pgbenchWarmup := flag.Int("pgbench-warmup", 0, "Throwaway pgbench transactions per connection before each measured run (warmup inserts add rows)")
+	rate := flag.Float64("rate", 0, "Throttle pgbench to this many transactions/sec across all connections (pgbench -R); 0 = unthrottled")
+	duration := flag.Int("duration", 0, "Run the measured insert-performance inserts, read-after-fragmentation reads and update-performance updates for this many seconds (pgbench -T) instead of -num-records/-num-ops; 0 = count bound")
+	seed := flag.Int64("seed", 0, "Seed pgbench's random() (--random-seed) so every key type reads, updates and upserts the same row positions in the same order; 0 = unseeded")
+	latencyLimit := flag.Float64("latency-limit", 0, "Count transactions slower than this many ms (pgbench --latency-limit); with -rate, late-starting ones are skipped")
+	queryMode := flag.String("query-mode", "simple", "pgbench query protocol (-M): simple, extended or prepared")
+	compareQueryModes := flag.Bool("compare-query-modes", false, "Repeat read-after-fragmentation reads under simple and prepared protocols to separate parse/plan cost")
+	pgbenchLogDir := flag.String("pgbench-log-dir", "", "Write raw stdout/stderr of every pgbench invocation to timestamped files in this directory")
+	includeDDLTiming := flag.Bool("include-ddl-timing", false, "Time and report the setup phase (extension creation, drop/create table) per key type")
+	extraIndexes := flag.Int("extra-indexes", 0, "Secondary indexes (each including id) to create alongside the primary key")
+	preset := flag.String("preset", "", "Dataset preset (small, medium, large); explicitly set flags take precedence")
+	verifyData := flag.Bool("verify-data", false, "After each load, check sampled rows for the expected data/UUID version and sampled BIGSERIAL ids for presence")
+	timestampSkew := flag.Float64("timestamp-skew", 0, "Maximum backward clock jump in ms injected into uuidv7 generation (0 = off)")
+	timestampSkewRate := flag.Float64("timestamp-skew-rate", 0.01, "Fraction of uuidv7 ids whose timestamp jumps back (with -timestamp-skew)")
+	uuidv8TimeBits := flag.Int("uuidv8-time-bits", pgbench.MaxUUIDv8TimeBits, "High bits of uuidv8 ids holding the millisecond timestamp (0..48); fewer bits coarsen it, e.g. 38 for about one second, and leave the rest random")
+	insertMode := flag.String("insert-mode", "pgbench", "How insert-performance loads rows: pgbench (-batch-size single-row INSERTs per transaction), batch (one multi-row INSERT per transaction) or copy (one COPY FROM STDIN, single connection)")
+	conflictRatio := flag.Int("conflict-ratio", upsertConflictRatio, "Percentage of upsert-performance upserts whose id already exists and takes the ON CONFLICT DO UPDATE path (0..100)")
+	rangeSize := flag.Int("range-size", rangeScanSize, "Rows each range-scan scan reads in key order from a random start id")
+	insertOrder := flag.String("insert-order", "forward", "Timestamp progression of generated uuidv7 ids: forward (the clock), reverse (backfill newest first) or random")
+	strict := flag.Bool("strict", false, "Abort instead of warning when I/O stats, page splits or buffer hit ratios cannot be collected, or a result holds an impossible value")
+	idColumn := flag.String("id-column", "id", "Name of the primary key column (a lowercase SQL identifier), to match a production schema's naming")
+	dataType := flag.String("data-type", "text", "Type of the data column: text, varchar(n), int, or none for an id-only table")
+	payloadBytes := flag.Int("payload-bytes", 0, "Pad each row's data value to this many bytes (e.g. 2048), making heap size realistic next to the index; 0 = the short unpadded value")
+	dataNull := flag.Bool("data-null", true, "Allow NULLs in the data column (false adds NOT NULL)")
+	serve := flag.String("serve", "", "Run as an HTTP service on this address (e.g. :8080) instead of running a scenario")
+	keyTypesSpec := flag.String("key-types", "", "Comma-separated key types to run (e.g. uuidv4,uuidv7); default all")
+	baseline := flag.String("baseline", "bigserial", "Key type the statistical comparisons, efficiency score and exports compare against; defaults to the first -key-types entry when bigserial is not selected")
+	metrics := flag.String("metrics", "", "Comma-separated metrics to display and export (e.g. throughput,page_splits,p99_latency_us); default all")
+	measureBloat := flag.Bool("measure-bloat", false, "Report dead tuple and free space from pgstattuple after each workload, and VACUUM after update-performance to show index size before/after (full table scans, slow on large tables)")
+	keepContainer := flag.Bool("keep-container", false, "Start PostgreSQL once and reset it between key types (drop bench_* tables, evict shared buffers, reset stats) instead of recreating the container; faster, but the OS page cache stays warm")
+	autovacuum := flag.String("autovacuum", "on", "Autovacuum on the benchmark table: on (realistic total cost) or off (workload's direct cost only)")
+	walCompression := flag.String("wal-compression", "", "Set wal_compression (off, on, pglz, lz4, zstd) via ALTER SYSTEM after each container start; empty keeps the server default")
+	failOnMissingExtension := flag.Bool("fail-on-missing-extension", false, "Before any workload, start the container once and check that every key type's extensions and functions exist, exiting with a list of what is missing")
+	viaPgBouncer := flag.Bool("via-pgbouncer", false, "Route pgbench and the benchmark's connection through a PgBouncer container (transaction pooling) in front of PostgreSQL")
+	pgHost := flag.String("pg-host", postgres.DefaultConfig.Host, "PostgreSQL host; any server but localhost:5432 is external: not started or reset by the benchmark, without container I/O, CPU and memory metrics, and reached by pgbench from -pg-container")
+	pgPort := flag.String("pg-port", postgres.DefaultConfig.Port, "PostgreSQL port")
+	pgUser := flag.String("pg-user", postgres.DefaultConfig.User, "PostgreSQL user, needing CREATE on the database and permission to create the extensions")
+	pgPassword := flag.String("pg-password", postgres.DefaultConfig.Password, "PostgreSQL password")
+	pgDatabase := flag.String("pg-database", postgres.DefaultConfig.DBName, "PostgreSQL database the bench_* tables are created in")
+	pgSSLMode := flag.String("pg-sslmode", postgres.DefaultConfig.SSLMode, "PostgreSQL sslmode: disable, require, verify-ca or verify-full")
+	pgContainer := flag.String("pg-container", postgres.DefaultConfig.Container, "Running container pgbench is executed in; for the local server also the one whose cgroup supplies I/O, CPU and memory stats")
+	compareBatchVsSingle := flag.Bool("compare-batch-vs-single", false, "Run insert-performance at batch size 1 and at -batch-size per key type and print one table of the throughput and page-split differences")
+	exportFigures := flag.String("export-figures", "", "Run the scenarios behind the standard figures (page splits, fragmentation vs scale, buffer hit ratios, write amplification) and write each as a gnuplot .dat/.gp pair into this directory")
+	remeasure := flag.Int("remeasure", 1, "Measure each loaded insert-performance table this many times and report the measurement-only CV next to the across-run CV (1 = off)")
+	sizeSampling := flag.Int("size-sampling", 0, "Sample the table and index size every this many milliseconds during insert-performance's inserts and write the series to -size-sampling-output (0 = off)")
+	sizeSamplingOutput := flag.String("size-sampling-output", "size_samples.csv", "CSV file the -size-sampling series are written to")
+	preload := flag.Int("preload", 0, "Rows loaded (unmeasured) before insert-performance's measured inserts, for steady-state inserts into a large existing index")
+	record := flag.String("record", "", "Write every inserted id per key type, in insert order, to this operation log (insert-performance, single run)")
+	replay := flag.String("replay", "", "Insert the ids from an operation log written by -record instead of generating them (insert-performance)")
+	bootstrapIterations := flag.Int("bootstrap-iterations", 10000, "Bootstrap resamples behind the 95% CI of each comparison's median difference (multi-run mode)")
+	scoreWeightsSpec := flag.String("score-weights", "", "Efficiency score weights as metric=weight pairs (default throughput=2,page_splits=1,fragmentation=1,index_size_mb=1,p99_latency_us=1; multi-run mode)")
+	workingSetFractions := flag.String("working-set-fractions", "0.5,1.0,2.0,4.0", "Dataset sizes (table + indexes) as multiples of shared_buffers for -scenario working-set-sweep")
+	sortBy := flag.String("sort-by", "", "Order comparison table columns by this metric, best first (see -list-metrics); default the key type order")
+	percentilesSpec := flag.String("percentiles", "50,95,99", "Comma-separated latency percentiles to report, computed from pgbench's per-transaction log (e.g. 50,90,99,99.9)")
+	color := flag.Bool("color", true, "Color each comparison table row's best value green and worst red (disabled when stdout is not a terminal or NO_COLOR is set)")
+	db := flag.String("db", "postgres", "Database to benchmark: postgres, mysql (InnoDB container) or sqlite (in-process file, no container); mysql and sqlite run insert-performance, read-after-fragmentation and update-performance with bigserial, uuidv4, uuidv7 and uuidv1")
+	listMetrics := flag.Bool("list-metrics", false, "Print every metric name with its unit, better direction and description, then exit")
 	flag.Parse()
 
+	display.SetColor(*color)
+	if *bootstrapIterations < 1 {
+		log.Fatalf("Invalid -bootstrap-iterations: %d (must be at least 1)", *bootstrapIterations)
+	}
+	display.SetBootstrapIterations(*bootstrapIterations)
+
+	percentiles, err := parsePercentiles(*percentilesSpec)
+	if err != nil {
+		log.Fatalf("Invalid -percentiles: %v", err)
+	}
+	benchmark.SetPercentiles(percentiles)
+	metric.SetPercentiles(percentiles)
+
+	if *listMetrics {
+		metric.List()
+		return
+	}
+
+	if err := metric.SetFocus(*metrics); err != nil {
+		log.Fatalf("Invalid -metrics: %v", err)
+	}
+
+	if *sortBy != "" {
+		if _, ok := metric.Lookup(*sortBy); !ok {
+			log.Fatalf("Invalid -sort-by: unknown metric %q (see -list-metrics)", *sortBy)
+		}
+		display.SetSortBy(*sortBy)
+	}
+
+	if *preset != "" {
+		p, ok := datasetPresets[*preset]
+		if !ok {
+			log.Fatalf("Invalid preset: %s (valid: small, medium, large)", *preset)
+		}
+
+		explicit := make(map[string]bool)
+		flag.Visit(func(f *flag.Flag) {
+			explicit[f.Name] = true
+		})
+
+		if !explicit["num-records"] {
+			*numRecords = p.numRecords
+		}
+		if !explicit["num-ops"] {
+			*numOps = p.numOps
+		}
+		if !explicit["connections"] {
+			*connections = p.connections
+		}
+		if !explicit["num-runs"] {
+			*numRuns = p.numRuns
+		}
+		insertHeavyDataset = p.insertHeavyDataset
+		readHeavyDataset = p.readHeavyDataset
+		balancedDataset = p.balancedDataset
+	}
+
+	scoreWeights, err = parseScoreWeights(*scoreWeightsSpec)
+	if err != nil {
+		log.Fatalf("Invalid -score-weights: %v", err)
+	}
+
+	trimOutliers = *trimOutliersFlag
+
+	if *conflictRatio < 0 || *conflictRatio > 100 {
+		log.Fatalf("Invalid -conflict-ratio: %d (must be 0..100)", *conflictRatio)
+	}
+	upsertConflictRatio = *conflictRatio
+
+	if *rangeSize < 1 {
+		log.Fatalf("Invalid -range-size: %d (must be at least 1)", *rangeSize)
+	}
+	rangeScanSize = *rangeSize
+
+	dbKeyTypes := map[string][]string{"postgres": knownKeyTypes, "mysql": mysql.KeyTypes, "sqlite": sqlite.KeyTypes}
+	if _, ok := dbKeyTypes[*db]; !ok {
+		log.Fatalf("Invalid -db: %s (valid: postgres, mysql, sqlite)", *db)
+	}
+	// The key types every scenario runs, all the database supports unless narrowed
+	keyTypes := dbKeyTypes[*db]
+
+	if *keyTypesSpec != "" {
+		keyTypes, err = parseKeyTypes(*keyTypesSpec)
+		if err != nil {
+			log.Fatalf("Invalid -key-types: %v", err)
+		}
+	}
+
+	if *db != "postgres" {
+		if !slices.Contains(portableScenarios, *scenario) {
+			log.Fatalf("Invalid -db: %s only runs %s", *db, strings.Join(portableScenarios, ", "))
+		}
+		for _, keyType := range keyTypes {
+			if !slices.Contains(dbKeyTypes[*db], keyType) {
+				log.Fatalf("Invalid -key-types: %s is not supported with -db %s (supported: %s)", keyType, *db, strings.Join(dbKeyTypes[*db], ", "))
+			}
+		}
+		var unsupported []string
+		flag.Visit(func(f *flag.Flag) {
+			if slices.Contains(postgresOnlyFlags, f.Name) {
+				unsupported = append(unsupported, "-"+f.Name)
+			}
+		})
+		if len(unsupported) > 0 {
+			log.Fatalf("Invalid -db: %s cannot be combined with %s", *db, strings.Join(unsupported, ", "))
+		}
+	}
+	if _, err := strconv.Atoi(*pgPort); err != nil {
+		log.Fatalf("Invalid -pg-port: %s", *pgPort)
+	}
+	if !slices.Contains([]string{"disable", "require", "verify-ca", "verify-full"}, *pgSSLMode) {
+		log.Fatalf("Invalid -pg-sslmode: %s (valid: disable, require, verify-ca, verify-full)", *pgSSLMode)
+	}
+	postgres.Connection = postgres.Config{
+		Host:      *pgHost,
+		Port:      *pgPort,
+		User:      *pgUser,
+		Password:  *pgPassword,
+		DBName:    *pgDatabase,
+		SSLMode:   *pgSSLMode,
+		Container: *pgContainer,
+	}
+	if postgres.Connection.External() {
+		var unsupported []string
+		flag.Visit(func(f *flag.Flag) {
+			if slices.Contains(containerOnlyFlags, f.Name) {
+				unsupported = append(unsupported, "-"+f.Name)
+			}
+		})
+		if len(unsupported) > 0 {
+			log.Fatalf("Invalid -pg-host: an external server cannot be combined with %s", strings.Join(unsupported, ", "))
+		}
+		if slices.Contains(containerOnlyScenarios, *scenario) {
+			log.Fatalf("Invalid -pg-host: %s restarts the container, an external server cannot run it", *scenario)
+		}
+	}
+	if *db == "sqlite" && *connections > 1 {
+		log.Fatalf("Invalid -connections: SQLite has a single writer, -db sqlite needs 1")
+	}
+
+	baselineSet := false
+	flag.Visit(func(f *flag.Flag) {
+		baselineSet = baselineSet || f.Name == "baseline"
+	})
+	baselineKeyType = strings.ToLower(*baseline)
+	if !slices.Contains(keyTypes, baselineKeyType) {
+		if baselineSet {
+			log.Fatalf("Invalid -baseline: %s is not among the selected key types %v", *baseline, keyTypes)
+		}
+		baselineKeyType = keyTypes[0]
+	}
+
+	if *warmupRuns < 0 {
+		log.Fatalf("Invalid -warmup-runs: %d (must not be negative)", *warmupRuns)
+	}
+	if *warmupRuns > 0 && *numRuns == 1 {
+		fmt.Printf("Warning: -warmup-runs only applies in multi-run mode (-num-runs > 1), ignoring it\n")
+	}
+
+	scenarioBatchSizes, err = parseScenarioBatchSizes(*scenarioBatchSize)
+	if err != nil {
+		log.Fatalf("Invalid -scenario-batch-size: %v", err)
+	}
+
+	if *compareBaselineFile != "" {
+		doc, err := export.LoadStatsJSON(*compareBaselineFile)
+		if err != nil {
+			log.Fatalf("Invalid -compare-baseline-file: %v", err)
+		}
+		if _, ok := doc.Results[baselineKeyType]; !ok {
+			log.Fatalf("Invalid -compare-baseline-file: %s has no %s results", *compareBaselineFile, baselineKeyType)
+		}
+		if doc.Scenario != "insert-performance" {
+			fmt.Printf("Warning: baseline file is from scenario %s, comparisons apply to insert-performance\n", doc.Scenario)
+		}
+		referenceBaseline = doc.Results[baselineKeyType]
+	}
+
+	if *extraIndexes < 0 {
+		log.Fatalf("Invalid -extra-indexes: must not be negative")
+	}
+
+	if err := pgbench.ValidateIDColumn(*idColumn); err != nil {
+		log.Fatalf("Invalid -id-column: %v", err)
+	}
+
+	if err := pgbench.ValidateDataType(*dataType); err != nil {
+		log.Fatalf("Invalid -data-type: %v", err)
+	}
+	if *dataType == "none" && *extraIndexes > 0 {
+		log.Fatalf("Invalid -data-type: none leaves no data/created_at columns for -extra-indexes")
+	}
+	if *payloadBytes != 0 {
+		if *payloadBytes < pgbench.MinPayloadBytes {
+			log.Fatalf("Invalid -payload-bytes: must be 0 or at least %d", pgbench.MinPayloadBytes)
+		}
+		if *dataType != "text" {
+			log.Fatalf("Invalid -payload-bytes: only supported with -data-type text")
+		}
+	}
+
+	if *rate < 0 || *latencyLimit < 0 {
+		log.Fatalf("Invalid -rate/-latency-limit: must not be negative")
+	}
+	if *seed < 0 {
+		log.Fatalf("Invalid -seed: %d (must not be negative)", *seed)
+	}
+
+	if *timestampSkew < 0 || *timestampSkewRate < 0 || *timestampSkewRate > 1 {
+		log.Fatalf("Invalid -timestamp-skew/-timestamp-skew-rate: skew must not be negative and rate must be within 0..1")
+	}
+
+	if err := pgbench.ValidateUUIDv8TimeBits(*uuidv8TimeBits); err != nil {
+		log.Fatalf("Invalid -uuidv8-time-bits: %v", err)
+	}
+
+	if err := pgbench.ValidateInsertOrder(*insertOrder); err != nil {
+		log.Fatalf("Invalid -insert-order: %v", err)
+	}
+	if *insertOrder != "forward" && *timestampSkew > 0 {
+		log.Fatalf("Invalid -insert-order: cannot be combined with -timestamp-skew")
+	}
+	if *insertOrder != "forward" && *scenario != "insert-order" && !slices.Contains(keyTypes, "uuidv7") {
+		log.Fatalf("Invalid -insert-order: only uuidv7 ids follow it, and -key-types selects no uuidv7")
+	}
+	if *scenario == "insert-order" && *timestampSkew > 0 {
+		log.Fatalf("Invalid -timestamp-skew: the insert-order scenario runs reverse and random orders, which cannot be combined with it")
+	}
+
+	if err := pgbench.ValidateInsertMode(*insertMode); err != nil {
+		log.Fatalf("Invalid -insert-mode: %v", err)
+	}
+	if *duration < 0 {
+		log.Fatalf("Invalid -duration: %d (must not be negative)", *duration)
+	}
+	if *duration > 0 && *replay != "" {
+		log.Fatalf("Invalid -duration: cannot be combined with -replay, which inserts a fixed list of ids")
+	}
+	if *duration > 0 && *insertMode == "copy" {
+		fmt.Println("Warning: -insert-mode copy loads a fixed -num-records, -duration does not bound it")
+	}
+
+	if *insertMode == "copy" {
+		switch {
+		case *connections > 1:
+			log.Fatalf("Invalid -insert-mode: copy streams from a single connection, -connections must be 1")
+		case *replay != "":
+			log.Fatalf("Invalid -insert-mode: copy cannot be combined with -replay (ids come from a column default, which cannot read replay_ops)")
+		case *dataType == "none":
+			log.Fatalf("Invalid -insert-mode: copy needs a data column to stream, not -data-type none")
+		}
+	}
+
+	if !slices.Contains([]string{"simple", "extended", "prepared"}, *queryMode) {
+		log.Fatalf("Invalid -query-mode: %s (valid: simple, extended, prepared)", *queryMode)
+	}
+
+	if *preload < 0 {
+		log.Fatalf("Invalid -preload: must not be negative")
+	}
+
+	if *autovacuum != "on" && *autovacuum != "off" {
+		log.Fatalf("Invalid -autovacuum: %s (valid: on, off)", *autovacuum)
+	}
+
+	if (*record != "" || *replay != "") && *scenario != "insert-performance" {
+		log.Fatalf("Invalid -record/-replay: only supported by -scenario insert-performance (reads and updates pick rows with pgbench's random())")
+	}
+
+	if *compareBatchVsSingle {
+		if *scenario != "insert-performance" {
+			log.Fatalf("Invalid -compare-batch-vs-single: only supported by -scenario insert-performance")
+		}
+		if batchSizeFor("insert-performance", *batchSize) <= 1 {
+			log.Fatalf("Invalid -compare-batch-vs-single: the batched run needs -batch-size above 1")
+		}
+		if *numRuns > 1 {
+			log.Fatalf("Invalid -compare-batch-vs-single: only supported with -num-runs 1")
+		}
+	}
+
+	if *remeasure < 1 {
+		log.Fatalf("Invalid -remeasure: must be at least 1")
+	}
+	if *remeasure > 1 && *scenario != "insert-performance" {
+		log.Fatalf("Invalid -remeasure: only supported by -scenario insert-performance")
+	}
+	if *scenario == "reindex-maintenance" && *numRecords < reindexCycles {
+		log.Fatalf("Invalid -num-records: reindex-maintenance inserts in %d bursts and needs at least %d", reindexCycles, reindexCycles)
+	}
+	if *exportFigures != "" {
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "scenario" {
+				log.Fatalf("Invalid -export-figures: runs its own fixed scenarios, cannot be combined with -scenario")
+			}
+		})
+	}
+	if *sizeSampling < 0 {
+		log.Fatalf("Invalid -size-sampling: must not be negative")
+	}
+	if *sizeSampling > 0 && (*scenario != "insert-performance" || *numRuns > 1 || *compareBatchVsSingle) {
+		log.Fatalf("Invalid -size-sampling: only supported by a single run of -scenario insert-performance")
+	}
+	if *record != "" && *numRuns > 1 {
+		log.Fatalf("Invalid -record: requires a single run")
+	}
+	if *record != "" && *record == *replay {
+		log.Fatalf("Invalid -record: must differ from the -replay log")
+	}
+	if *record != "" {
+		if err := os.WriteFile(*record, []byte("# uuid-benchmark operation log\n"), 0644); err != nil {
+			log.Fatalf("Invalid -record: %v", err)
+		}
+		runner.Options.RecordPath = *record
+	}
+	if *replay != "" {
+		ops, err := postgres.ReadOpLog(*replay)
+		if err != nil {
+			log.Fatalf("Invalid -replay: %v", err)
+		}
+		runner.Options.ReplayOps = ops
+	}
+
+	runner.Options.PgbenchWarmup = *pgbenchWarmup
+	runner.Options.Rate = *rate
+	runner.Options.LatencyLimit = *latencyLimit
+	runner.Options.PgbenchLogDir = *pgbenchLogDir
+	runner.Options.QueryMode = *queryMode
+	runner.Options.Seed = *seed
+	runner.Options.Duration = *duration
+	runner.Options.ViaPgBouncer = *viaPgBouncer
+	runner.Options.CompareQueryModes = *compareQueryModes
+	runner.Options.TimestampSkew = pgbench.TimestampSkew{MaxMs: *timestampSkew, Rate: *timestampSkewRate}
+	runner.Options.InsertOrder = *insertOrder
+	runner.Options.InsertMode = *insertMode
+	runner.Options.UUIDv8TimeBits = *uuidv8TimeBits
+	runner.Options.IncludeDDLTiming = *includeDDLTiming
+	runner.Options.ExtraIndexes = *extraIndexes
+	runner.Options.Preload = *preload
+	runner.Options.Remeasure = *remeasure
+	runner.Options.SizeSampling = *sizeSampling
+	sizeSamplesOutput = *sizeSamplingOutput
+	runner.Options.VerifyData = *verifyData
+	runner.Options.Strict = *strict
+	runner.Options.DisableAutovacuum = *autovacuum == "off"
+	runner.Options.MeasureBloat = *measureBloat
+	runner.Options.IDColumn = *idColumn
+	runner.Options.DataColumn = pgbench.DataColumn{Type: *dataType, NotNull: !*dataNull, PayloadBytes: *payloadBytes}
+	benchmark.SetPayloadBytes(*payloadBytes)
+
+	var tuning map[string]string
+	if *pgTuning != "" {
+		var err error
+		tuning, err = postgres.LoadTuning(*pgTuning)
+		if err != nil {
+			log.Fatalf("Invalid -pg-tuning: %v", err)
+		}
+	}
+	if *walCompression != "" {
+		if !slices.Contains([]string{"off", "on", "pglz", "lz4", "zstd"}, *walCompression) {
+			log.Fatalf("Invalid -wal-compression: %s (valid: off, on, pglz, lz4, zstd)", *walCompression)
+		}
+		if tuning == nil {
+			tuning = make(map[string]string)
+		}
+		tuning["wal_compression"] = *walCompression
+	}
+	runSettings["autovacuum"] = *autovacuum
+	runSettings["insert_mode"] = *insertMode
+	switch *db {
+	case "mysql":
+		runSettings["insert_mode"] = "batch"
+	case "sqlite":
+		runSettings["insert_mode"] = "sqlite"
+	}
+	runSettings["bound"] = "count"
+	if *duration > 0 {
+		runSettings["bound"] = fmt.Sprintf("%ds", *duration)
+	}
+	runSettings["seed"] = "unseeded"
+	if *seed != 0 {
+		runSettings["seed"] = strconv.FormatInt(*seed, 10)
+	}
+	runSettings["wal_compression"] = cmp.Or(tuning["wal_compression"], "default")
+	jsonOutput = *jsonOutputFlag
+	if *markdownOutput != "" {
+		display.RecordTables()
+	}
+	runSettings["container"] = "fresh"
+	if *keepContainer {
+		runSettings["container"] = "kept"
+		container.SetKeep(true)
+		defer container.Release()
+	}
+	runSettings["database"] = *db
+	// The scenarios start container.PostgresConfig; the runner routes them to the database
+	runner.Database = *db
+	switch *db {
+	case "mysql":
+		container.PostgresConfig = container.MySQLConfig
+	case "sqlite":
+		container.PostgresConfig = container.SQLiteConfig
+	}
+	runSettings["connection"] = "direct"
+	if *viaPgBouncer {
+		runSettings["connection"] = "pgbouncer"
+		container.PostgresConfig.ComposeFile = "docker/docker-compose.pgbouncer.yml"
+		container.PostgresConfig.WaitForReady = postgres.WaitForPgBouncer
+	}
+
+	runSettings["server"] = "container"
+	if postgres.Connection.External() {
+		runSettings["server"] = "external"
+		// Nothing to start or stop; CreateTable drops and recreates each key type's table
+		container.PostgresConfig = container.Config{Name: "PostgreSQL"}
+	}
+
+	if len(tuning) > 0 {
+		container.PostgresConfig.AfterStart = func() error {
+			return postgres.ApplyTuning(tuning)
+		}
+	}
+
+	if *serve != "" {
+		srv := server.New(keyTypes, serverScenarios(), server.Config{
+			NumRecords:  *numRecords,
+			NumOps:      *numOps,
+			Connections: *connections,
+			BatchSize:   *batchSize,
+		})
+		err := srv.ListenAndServe(*serve)
+		// log.Fatal skips the deferred Release, so remove a kept container first
+		container.Release()
+		log.Fatal(err)
+	}
+
 	fmt.Println("UUID Benchmark - PostgreSQL")
 	fmt.Println(strings.Repeat("=", 70))
 	fmt.Printf("Scenario:     %s\n", *scenario)
+	switch *db {
+	case "mysql":
+		fmt.Println("Database:     MySQL (InnoDB, docker/docker-compose.mysql.yml)")
+	case "sqlite":
+		fmt.Println("Database:     SQLite (in-process, temporary file per key type)")
+	}
+	if postgres.Connection.External() {
+		fmt.Printf("Server:       %s:%s/%s (external, pgbench in %s)\n", *pgHost, *pgPort, *pgDatabase, *pgContainer)
+		fmt.Println("Warning: external server, container I/O, CPU and memory metrics are not collected")
+	}
+	if *preset != "" {
+		fmt.Printf("Preset:       %s\n", *preset)
+	}
 	fmt.Printf("Records:      %d\n", *numRecords)
 	if *connections > 1 {
 		fmt.Printf("Connections:  %d\n", *connections)
@@ -37,48 +802,258 @@ func main() {
 	if *batchSize > 1 {
 		fmt.Printf("Batch Size:   %d\n", *batchSize)
 	}
+	for _, name := range batchedScenarios {
+		if size, ok := scenarioBatchSizes[name]; ok {
+			fmt.Printf("  %-26s = %d\n", name, size)
+		}
+	}
 	if *numRuns > 1 {
 		fmt.Printf("Runs:         %d (statistical mode)\n", *numRuns)
+		if *warmupRuns > 0 {
+			fmt.Printf("Warmup Runs:  %d per UUID type, discarded\n", *warmupRuns)
+		}
+		if *trimOutliersFlag {
+			fmt.Println("Outliers:     trimmed (1.5×IQR)")
+		}
+	}
+	if *pgbenchWarmup > 0 {
+		fmt.Printf("Warmup:       %d pgbench transactions per connection\n", *pgbenchWarmup)
+	}
+	if *rate > 0 {
+		fmt.Printf("Rate Limit:   %.0f tps\n", *rate)
+	}
+	if *latencyLimit > 0 {
+		fmt.Printf("Latency Cap:  %.1f ms\n", *latencyLimit)
+	}
+	if *seed != 0 {
+		fmt.Printf("Random Seed:  %d\n", *seed)
+	}
+	if *duration > 0 {
+		fmt.Printf("Duration:     %ds per measured insert/read/update run\n", *duration)
+	}
+	if *queryMode != "simple" {
+		fmt.Printf("Query Mode:   %s\n", *queryMode)
+	}
+	if *viaPgBouncer {
+		fmt.Println("Connection:   via PgBouncer (transaction pooling, docker/pgbouncer/pgbouncer.ini)")
+	}
+	if *pgbenchLogDir != "" {
+		fmt.Printf("pgbench Logs: %s\n", *pgbenchLogDir)
+	}
+	if *jsonOutputFlag != "" {
+		fmt.Printf("JSON Output:  %s\n", *jsonOutputFlag)
+	}
+	if *markdownOutput != "" {
+		fmt.Printf("Markdown:     %s\n", *markdownOutput)
+	}
+	if *compareBaselineFile != "" {
+		fmt.Printf("Baseline:     %s (%s reference)\n", *compareBaselineFile, strings.ToUpper(baselineKeyType))
+	} else if baselineKeyType != "bigserial" {
+		fmt.Printf("Baseline:     %s\n", strings.ToUpper(baselineKeyType))
+	}
+	if runner.Options.TimestampSkew.Enabled() {
+		fmt.Printf("Clock Skew:   up to %.0f ms back for %.1f%% of uuidv7 ids\n", *timestampSkew, *timestampSkewRate*100)
+	}
+	if *uuidv8TimeBits != pgbench.MaxUUIDv8TimeBits {
+		fmt.Printf("UUIDv8 Time:  %d timestamp bits (%d ms buckets)\n", *uuidv8TimeBits, int64(1)<<(pgbench.MaxUUIDv8TimeBits-*uuidv8TimeBits))
+	}
+	if *insertMode != "pgbench" {
+		fmt.Printf("Insert Mode:  %s\n", *insertMode)
+	}
+	if *insertOrder != "forward" {
+		fmt.Printf("Insert Order: %s (uuidv7 timestamps)\n", *insertOrder)
+	}
+	if *idColumn != "id" {
+		fmt.Printf("ID Column:    %s\n", *idColumn)
+	}
+	if *dataType != "text" || !*dataNull {
+		nullability := ""
+		if !*dataNull {
+			nullability = " NOT NULL"
+		}
+		fmt.Printf("Data Column:  %s%s\n", *dataType, nullability)
+	}
+	if *payloadBytes > 0 {
+		fmt.Printf("Payload:      %d bytes per row\n", *payloadBytes)
+	}
+	if *extraIndexes > 0 {
+		fmt.Printf("Extra Indexes: %d\n", *extraIndexes)
+	}
+	if *preload > 0 {
+		fmt.Printf("Preload:      %d rows before measured inserts\n", *preload)
+	}
+	if *sizeSampling > 0 {
+		fmt.Printf("Size Samples: every %dms -> %s\n", *sizeSampling, *sizeSamplingOutput)
+	}
+	if *autovacuum == "off" {
+		fmt.Printf("Autovacuum:   off\n")
+	}
+	if *keepContainer {
+		fmt.Println("Container:    kept, reset between key types")
+	}
+	if *record != "" {
+		fmt.Printf("Recording:    %s\n", *record)
+	}
+	if *replay != "" {
+		fmt.Printf("Replaying:    %s\n", *replay)
+	}
+	if *exportFigures != "" {
+		fmt.Printf("Figures:      %s (insert-performance at %v of the records, read-after-fragmentation)\n", *exportFigures, figureScales)
+	}
+	fmt.Printf("Testing:      %v\n", keyTypes)
+	if *metrics != "" {
+		fmt.Printf("Metrics:      %s\n", *metrics)
+	}
+	if len(tuning) > 0 {
+		fmt.Printf("PG Tuning:    %s\n", cmp.Or(*pgTuning, "-wal-compression"))
+		names := make([]string, 0, len(tuning))
+		for name := range tuning {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %-26s = %s\n", name, tuning[name])
+		}
 	}
-	fmt.Printf("Testing:      %v\n", allKeyTypes)
 	fmt.Println(strings.Repeat("=", 70))
 	fmt.Println()
 
+	if *failOnMissingExtension {
+		preflight(keyTypes)
+	}
+
+	if *exportFigures != "" {
+		runExportFigures(keyTypes, *exportFigures, *numRecords, *numOps, batchSizeFor("insert-performance", *batchSize), *connections)
+		return
+	}
+
 	switch *scenario {
 	case "insert-performance":
-		runInsertPerformance(*numRecords, *batchSize, *connections, *numRuns, *output)
+		if *compareBatchVsSingle {
+			runBatchVsSingle(keyTypes, *numRecords, batchSizeFor("insert-performance", *batchSize), *connections)
+			break
+		}
+		runInsertPerformance(keyTypes, *numRecords, batchSizeFor("insert-performance", *batchSize), *connections, *numRuns, *warmupRuns, *output, *resultsDB, *gnuplot, *benchstatOutput)
 
 	case "read-after-fragmentation":
-		runReadAfterFragmentation(*numRecords, *numOps, *numRuns)
+		runReadAfterFragmentation(keyTypes, *numRecords, *numOps, *numRuns)
 
 	case "update-performance":
-		runUpdatePerformance(*numRecords, *numOps, *batchSize, *numRuns)
+		runUpdatePerformance(keyTypes, *numRecords, *numOps, *batchSize, *numRuns)
 
 	case "mixed-insert-heavy":
-		runMixedWorkloadInsertHeavy(*numOps, *connections, *batchSize, *numRuns)
+		runMixedWorkloadInsertHeavy(keyTypes, *numOps, *connections, *batchSize, *numRuns)
 
 	case "mixed-read-heavy":
-		runMixedWorkloadReadHeavy(*numOps, *connections, *numRuns)
+		runMixedWorkloadReadHeavy(keyTypes, *numOps, *connections, *numRuns)
 
 	case "mixed-balanced":
-		runMixedWorkloadBalanced(*numOps, *connections, *numRuns)
+		runMixedWorkloadBalanced(keyTypes, *numOps, *connections, *numRuns)
+
+	case "jsonb-gin":
+		runJSONBGin(keyTypes, *numRecords, batchSizeFor("jsonb-gin", *batchSize))
+
+	case "commit-overhead":
+		runCommitOverhead(keyTypes, *numRecords)
+
+	case "insert-returning":
+		runInsertReturning(keyTypes, *numRecords, *connections)
+
+	case "upsert-performance":
+		runUpsertPerformance(keyTypes, *numRecords, *numOps)
+
+	case "range-scan":
+		runRangeScan(keyTypes, *numRecords, *numOps)
+
+	case "cache-competition":
+		runCacheCompetition(keyTypes, *numRecords, *numOps, tuning)
+
+	case "working-set-sweep":
+		fractions, err := parseWorkingSetFractions(*workingSetFractions)
+		if err != nil {
+			log.Fatalf("Invalid -working-set-fractions: %v", err)
+		}
+		runWorkingSetSweep(keyTypes, fractions, *numOps, tuning)
+
+	case "reindex-maintenance":
+		runReindexMaintenance(keyTypes, *numRecords, batchSizeFor("reindex-maintenance", *batchSize))
+
+	case "update-churn":
+		runUpdateChurn(keyTypes, *numRecords, *numOps)
+
+	case "connection-scaling":
+		runConnectionScaling(*numOps)
+
+	case "insert-order":
+		runInsertOrder(*numRecords, batchSizeFor("insert-order", *batchSize), *connections)
 
 	case "all":
-		runAllScenarios(*numRecords, *numOps, *connections, *batchSize, *numRuns, *output)
+		runAllScenarios(keyTypes, *numRecords, *numOps, *connections, *batchSize, *numRuns, *output)
 
 	default:
 		log.Fatalf("Invalid scenario: %s", *scenario)
 	}
 
+	if *markdownOutput != "" {
+		if err := export.TablesToMarkdown(display.Tables(), *markdownOutput); err != nil {
+			log.Printf("Warning: Failed to export Markdown tables: %v", err)
+		} else {
+			fmt.Printf("✓ Comparison tables (Markdown): %s\n", *markdownOutput)
+		}
+	}
+
 	fmt.Println()
 	fmt.Println("All scenarios completed successfully!")
 }
 
-func runInsertPerformance(numRecords, batchSize, connections, numRuns int, outputFile string) {
+// preflight starts the container once to check every key type's server requirements,
+// exiting before any workload if something is missing
+func preflight(keyTypes []string) {
+	fmt.Println("Preflight: checking extensions and functions...")
+	container.Start(container.PostgresConfig)
+	err := postgres.Preflight(keyTypes)
+	container.Stop(container.PostgresConfig.ComposeFile)
+	if err != nil {
+		log.Fatalf("Preflight failed, no workload was run: %v", err)
+	}
+	fmt.Println("Preflight passed")
+	fmt.Println()
+}
+
+// jsonOutput is the -json-output path, empty = disabled
+var jsonOutput string
+
+// sizeSamplesOutput is the -size-sampling-output path, written when -size-sampling is set
+var sizeSamplesOutput string
+
+// jsonResults holds every completed scenario's results for -json-output, keyed by
+// scenario and then key type
+var jsonResults = make(map[string]any)
+
+// recordResults adds a scenario's results to the -json-output document and rewrites it,
+// so the scenarios completed before a failing one are kept
+func recordResults(scenario string, results any) {
+	if jsonOutput == "" {
+		return
+	}
+
+	jsonResults[scenario] = results
+	if err := export.ResultsToJSON(jsonResults, jsonOutput); err != nil {
+		log.Printf("Warning: Failed to export results JSON: %v", err)
+		return
+	}
+	fmt.Printf("✓ Results (JSON): %s\n", jsonOutput)
+}
+
+// runSettings are the run settings recorded in the JSON summary, so summaries taken
+// under different conditions are not compared unknowingly
+var runSettings = map[string]string{}
+
+func runInsertPerformance(keyTypes []string, numRecords, batchSize, connections, numRuns, warmupRuns int, outputFile, resultsDB, gnuplot, benchstatFile string) {
 	if numRuns == 1 {
 		results := make(map[string]*benchmark.InsertPerformanceResult)
 
-		for _, keyType := range allKeyTypes {
+		for _, keyType := range keyTypes {
 			fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 			fmt.Println(strings.Repeat("-", 70))
 
@@ -94,14 +1069,50 @@ func runInsertPerformance(numRecords, batchSize, connections, numRuns int, outpu
 			container.Stop(container.PostgresConfig.ComposeFile)
 		}
 
-		display.InsertPerformance(results, allKeyTypes, connections, batchSize)
+		display.InsertPerformance(results, keyTypes, connections, batchSize)
+		recordResults("insert-performance", results)
+
+		if runner.Options.SizeSampling > 0 {
+			if err := export.SizeSamplesToCSV(results, keyTypes, sizeSamplesOutput); err != nil {
+				log.Printf("Warning: Failed to export size samples: %v", err)
+			} else {
+				fmt.Printf("✓ Size samples (CSV): %s\n", sizeSamplesOutput)
+			}
+		}
+
+		if runner.Options.Remeasure > 1 {
+			measurementCV := make(map[string]map[string]float64)
+			for keyType, result := range results {
+				measurementCV[keyType] = result.MeasurementCV
+			}
+			display.MeasurementStability(measurementCV, nil, keyTypes)
+		}
 	} else {
 		statsResults := make(map[string]map[string]statistics.Stats)
+		measurementCV := make(map[string]map[string]float64)
+		allRuns := make(map[string][]*benchmark.InsertPerformanceResult)
 
-		for _, keyType := range allKeyTypes {
+		for _, keyType := range keyTypes {
 			fmt.Printf("\nTesting %s (%d runs)\n", strings.ToUpper(keyType), numRuns)
 			fmt.Println(strings.Repeat("-", 70))
 
+			// Warmup runs go through the same container cycle and are discarded, so the
+			// measured runs do not include the cold first one
+			for i := 0; i < warmupRuns; i++ {
+				fmt.Printf("  Warmup %d/%d... ", i+1, warmupRuns)
+
+				container.Start(container.PostgresConfig)
+
+				if _, err := runner.InsertPerformance(keyType, numRecords, batchSize, connections); err != nil {
+					container.Stop(container.PostgresConfig.ComposeFile)
+					log.Fatalf("Warmup run %d failed for %s: %v", i+1, keyType, err)
+				}
+
+				container.Stop(container.PostgresConfig.ComposeFile)
+
+				fmt.Println("done")
+			}
+
 			runs := make([]*benchmark.InsertPerformanceResult, numRuns)
 
 			for i := 0; i < numRuns; i++ {
@@ -122,14 +1133,22 @@ func runInsertPerformance(numRecords, batchSize, connections, numRuns int, outpu
 			}
 
 			statsResults[keyType] = aggregateInsertPerformanceResults(runs)
+			allRuns[keyType] = runs
+			measurementCV[keyType] = meanMeasurementCV(runs)
 		}
 
-		display.InsertPerformanceStatistics(statsResults, allKeyTypes, numRecords, connections, batchSize, numRuns)
+		baseline, comparisonResults := comparisonBaseline(statsResults)
+		display.InsertPerformanceStatistics(comparisonResults, keyTypes, baseline, numRecords, connections, batchSize, numRuns)
+		recordResults("insert-performance", allRuns)
+		display.EfficiencyScore(comparisonResults, keyTypes, baseline, scoreWeights)
+		if runner.Options.Remeasure > 1 {
+			display.MeasurementStability(measurementCV, statsResults, keyTypes)
+		}
 
 		if outputFile != "" {
 			fmt.Printf("\nExporting results to CSV...\n")
 
-			if err := export.InsertPerformanceStatsToCSV(statsResults, allKeyTypes, outputFile); err != nil {
+			if err := export.InsertPerformanceStatsToCSV(statsResults, keyTypes, outputFile); err != nil {
 				log.Printf("Warning: Failed to export stats CSV: %v", err)
 			} else {
 				fmt.Printf("✓ Statistical summary: %s\n", outputFile)
@@ -139,69 +1158,204 @@ func runInsertPerformance(numRecords, batchSize, connections, numRuns int, outpu
 			if rawFile == outputFile {
 				rawFile = outputFile + ".raw"
 			}
-			if err := export.InsertPerformanceRawRunsToCSV(statsResults, allKeyTypes, rawFile); err != nil {
-				log.Printf("Warning: Failed to export raw runs CSV: %v", err)
+			if err := export.InsertPerformanceRawRunsToCSV(statsResults, keyTypes, rawFile); err != nil {
+				log.Printf("Warning: Failed to export raw runs CSV: %v", err)
+			} else {
+				fmt.Printf("✓ Raw runs data: %s\n", rawFile)
+			}
+
+			comparisonsFile := strings.Replace(outputFile, ".csv", "_comparisons.csv", 1)
+			if comparisonsFile == outputFile {
+				comparisonsFile = outputFile + ".comparisons"
+			}
+			if err := export.ComparisonsToCSV(comparisonResults, keyTypes, baseline, comparisonsFile); err != nil {
+				log.Printf("Warning: Failed to export comparisons CSV: %v", err)
+			} else {
+				fmt.Printf("✓ Comparisons vs %s: %s\n", strings.ToUpper(baseline), comparisonsFile)
+			}
+
+			jsonFile := strings.Replace(outputFile, ".csv", ".json", 1)
+			if jsonFile == outputFile {
+				jsonFile = outputFile + ".json"
+			}
+			if err := export.InsertPerformanceStatsToJSON(statsResults, keyTypes, runSettings, jsonFile); err != nil {
+				log.Printf("Warning: Failed to export stats JSON: %v", err)
+			} else {
+				fmt.Printf("✓ Statistical summary (JSON): %s\n", jsonFile)
+			}
+		}
+
+		if gnuplot != "" {
+			datFile, scriptFile, err := export.ResultsToGnuplot(statsResults, keyTypes, gnuplot)
+			if err != nil {
+				log.Printf("Warning: Failed to export gnuplot files: %v", err)
+			} else {
+				fmt.Printf("✓ gnuplot data: %s, script: %s (run: gnuplot %s)\n", datFile, scriptFile, filepath.Base(scriptFile))
+			}
+		}
+
+		if benchstatFile != "" {
+			if err := export.ToBenchstat(statsResults, keyTypes, numRecords, connections, benchstatFile); err != nil {
+				log.Printf("Warning: Failed to export benchstat file: %v", err)
+			} else {
+				fmt.Printf("✓ benchstat results: %s (compare: benchstat old.txt %s)\n", benchstatFile, benchstatFile)
+			}
+		}
+
+		if resultsDB != "" {
+			run := export.RunInfo{
+				Scenario:    "insert-performance",
+				NumRecords:  numRecords,
+				Connections: connections,
+				BatchSize:   batchSize,
+				NumRuns:     numRuns,
+				Host:        sysinfo.Collect(),
+			}
+			if err := export.ResultsToSQLite(comparisonResults, keyTypes, baseline, run, resultsDB); err != nil {
+				log.Printf("Warning: Failed to write results database: %v", err)
 			} else {
-				fmt.Printf("✓ Raw runs data: %s\n", rawFile)
+				fmt.Printf("✓ Appended to results database: %s\n", resultsDB)
 			}
 		}
 	}
 }
 
+// trimOutliers excludes 1.5×IQR outliers from the aggregated statistics, from -trim-outliers
+var trimOutliers bool
+
+// calculateStats computes a metric's statistics over the runs, without outliers when
+// -trim-outliers is set
+func calculateStats(values []float64) statistics.Stats {
+	if trimOutliers {
+		return statistics.CalculateTrimmed(values)
+	}
+	return statistics.Calculate(values)
+}
+
 func aggregateInsertPerformanceResults(runs []*benchmark.InsertPerformanceResult) map[string]statistics.Stats {
 	numRuns := len(runs)
 
 	throughput := make([]float64, numRuns)
 	pageSplits := make([]float64, numRuns)
+	pagesDirtied := make([]float64, numRuns)
 	fragmentation := make([]float64, numRuns)
 	avgLeafDensity := make([]float64, numRuns)
 	tableSizeMB := make([]float64, numRuns)
 	indexSizeMB := make([]float64, numRuns)
-	p50Latency := make([]float64, numRuns)
-	p95Latency := make([]float64, numRuns)
-	p99Latency := make([]float64, numRuns)
+	indexTableRatio := make([]float64, numRuns)
 	readIOPS := make([]float64, numRuns)
 	writeIOPS := make([]float64, numRuns)
 	readThroughputMB := make([]float64, numRuns)
 	writeThroughputMB := make([]float64, numRuns)
+	writeAmplification := make([]float64, numRuns)
+	walMB := make([]float64, numRuns)
+	fpiMB := make([]float64, numRuns)
+	avgCPUPercent := make([]float64, numRuns)
+	peakRSSMB := make([]float64, numRuns)
 
 	for i, run := range runs {
 		throughput[i] = run.Throughput
 		pageSplits[i] = float64(run.PageSplits)
+		pagesDirtied[i] = run.IndexPagesDirtiedPer1k()
 		fragmentation[i] = run.Fragmentation.FragmentationPercent
 		avgLeafDensity[i] = run.Fragmentation.AvgLeafDensity
 		tableSizeMB[i] = float64(run.TableSize) / (1024 * 1024)
 		indexSizeMB[i] = float64(run.IndexSize) / (1024 * 1024)
-		p50Latency[i] = float64(run.LatencyP50.Microseconds())
-		p95Latency[i] = float64(run.LatencyP95.Microseconds())
-		p99Latency[i] = float64(run.LatencyP99.Microseconds())
+		indexTableRatio[i] = run.IndexTableRatio
 		readIOPS[i] = run.ReadIOPS
 		writeIOPS[i] = run.WriteIOPS
 		readThroughputMB[i] = run.ReadThroughputMB
 		writeThroughputMB[i] = run.WriteThroughputMB
+		writeAmplification[i] = run.WriteAmplification
+		walMB[i] = float64(run.WALBytes) / (1024 * 1024)
+		fpiMB[i] = float64(run.FPIBytes) / (1024 * 1024)
+		avgCPUPercent[i] = run.AvgCPUPercent
+		peakRSSMB[i] = run.PeakRSSMB
+	}
+
+	stats := map[string]statistics.Stats{
+		"throughput":                 calculateStats(throughput),
+		"page_splits":                calculateStats(pageSplits),
+		"index_pages_dirtied_per_1k": calculateStats(pagesDirtied),
+		"fragmentation":              calculateStats(fragmentation),
+		"avg_leaf_density":           calculateStats(avgLeafDensity),
+		"table_size_mb":              calculateStats(tableSizeMB),
+		"index_size_mb":              calculateStats(indexSizeMB),
+		"index_table_ratio":          calculateStats(indexTableRatio),
+		"read_iops":                  calculateStats(readIOPS),
+		"write_iops":                 calculateStats(writeIOPS),
+		"read_throughput_mb":         calculateStats(readThroughputMB),
+		"write_throughput_mb":        calculateStats(writeThroughputMB),
+		"write_amplification":        calculateStats(writeAmplification),
+		"wal_mb":                     calculateStats(walMB),
+		"fpi_mb":                     calculateStats(fpiMB),
+		"avg_cpu_percent":            calculateStats(avgCPUPercent),
+		"peak_rss_mb":                calculateStats(peakRSSMB),
+	}
+
+	// Only rate-limited runs have a schedule to lag behind
+	if runs[0].Rate > 0 {
+		lagAvg := make([]float64, numRuns)
+		lagMax := make([]float64, numRuns)
+		skipped := make([]float64, numRuns)
+		for i, run := range runs {
+			lagAvg[i] = float64(run.ScheduleLagAvg.Microseconds())
+			lagMax[i] = float64(run.ScheduleLagMax.Microseconds())
+			skipped[i] = float64(run.Skipped)
+		}
+		stats["schedule_lag_avg_us"] = calculateStats(lagAvg)
+		stats["schedule_lag_max_us"] = calculateStats(lagMax)
+		stats["skipped_transactions"] = calculateStats(skipped)
+	}
+
+	// Only the SQLite benchmarker reads its database file's pages
+	if runs[0].FilePages > 0 {
+		filePages := make([]float64, numRuns)
+		freelistPages := make([]float64, numRuns)
+		for i, run := range runs {
+			filePages[i] = float64(run.FilePages)
+			freelistPages[i] = float64(run.FreelistPages)
+		}
+		stats["file_pages"] = calculateStats(filePages)
+		stats["freelist_pages"] = calculateStats(freelistPages)
 	}
 
-	return map[string]statistics.Stats{
-		"throughput":          statistics.Calculate(throughput),
-		"page_splits":         statistics.Calculate(pageSplits),
-		"fragmentation":       statistics.Calculate(fragmentation),
-		"avg_leaf_density":    statistics.Calculate(avgLeafDensity),
-		"table_size_mb":       statistics.Calculate(tableSizeMB),
-		"index_size_mb":       statistics.Calculate(indexSizeMB),
-		"p50_latency_us":      statistics.Calculate(p50Latency),
-		"p95_latency_us":      statistics.Calculate(p95Latency),
-		"p99_latency_us":      statistics.Calculate(p99Latency),
-		"read_iops":           statistics.Calculate(readIOPS),
-		"write_iops":          statistics.Calculate(writeIOPS),
-		"read_throughput_mb":  statistics.Calculate(readThroughputMB),
-		"write_throughput_mb": statistics.Calculate(writeThroughputMB),
+	// Latencies are only collected for concurrent runs
+	for _, percentile := range benchmark.Percentiles {
+		var latency []float64
+		for _, run := range runs {
+			if value, ok := run.Latency[percentile]; ok {
+				latency = append(latency, float64(value.Microseconds()))
+			}
+		}
+		if len(latency) > 0 {
+			stats[metric.LatencyName(percentile)] = calculateStats(latency)
+		}
+	}
+
+	return stats
+}
+
+// meanMeasurementCV averages each metric's -remeasure CV over the runs
+func meanMeasurementCV(runs []*benchmark.InsertPerformanceResult) map[string]float64 {
+	samples := make(map[string][]float64)
+	for _, run := range runs {
+		for name, cv := range run.MeasurementCV {
+			samples[name] = append(samples[name], cv)
+		}
 	}
+
+	mean := make(map[string]float64, len(samples))
+	for name, values := range samples {
+		mean[name] = statistics.Mean(values)
+	}
+	return mean
 }
 
-func runReadAfterFragmentation(numRecords, numOps, numRuns int) {
+func runReadAfterFragmentation(keyTypes []string, numRecords, numOps, numRuns int) {
 	results := make(map[string]*benchmark.ReadAfterFragmentationResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
@@ -217,13 +1371,14 @@ func runReadAfterFragmentation(numRecords, numOps, numRuns int) {
 		container.Stop(container.PostgresConfig.ComposeFile)
 	}
 
-	display.ReadAfterFragmentation(results, allKeyTypes)
+	display.ReadAfterFragmentation(results, keyTypes)
+	recordResults("read-after-fragmentation", results)
 }
 
-func runUpdatePerformance(numRecords, numOps, batchSize, numRuns int) {
+func runUpdatePerformance(keyTypes []string, numRecords, numOps, batchSize, numRuns int) {
 	results := make(map[string]*benchmark.UpdatePerformanceResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
@@ -239,19 +1394,20 @@ func runUpdatePerformance(numRecords, numOps, batchSize, numRuns int) {
 		container.Stop(container.PostgresConfig.ComposeFile)
 	}
 
-	display.UpdatePerformance(results, allKeyTypes)
+	display.UpdatePerformance(results, keyTypes)
+	recordResults("update-performance", results)
 }
 
-func runMixedWorkloadInsertHeavy(totalOps, connections, batchSize, numRuns int) {
+func runMixedWorkloadInsertHeavy(keyTypes []string, totalOps, connections, batchSize, numRuns int) {
 	results := make(map[string]*benchmark.MixedWorkloadResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
 		container.Start(container.PostgresConfig)
 
-		result, err := runner.MixedWorkloadInsertHeavy(keyType, totalOps, connections, batchSize)
+		result, err := runner.MixedWorkloadInsertHeavy(keyType, insertHeavyDataset, totalOps, connections, batchSize)
 		if err != nil {
 			container.Stop(container.PostgresConfig.ComposeFile)
 			log.Fatalf("Scenario failed for %s: %v", keyType, err)
@@ -261,19 +1417,20 @@ func runMixedWorkloadInsertHeavy(totalOps, connections, batchSize, numRuns int)
 		container.Stop(container.PostgresConfig.ComposeFile)
 	}
 
-	display.MixedWorkload(results, allKeyTypes, "Insert-Heavy (90% insert, 10% read)")
+	display.MixedWorkload(results, keyTypes, "Insert-Heavy (90% insert, 10% read)")
+	recordResults("mixed-insert-heavy", results)
 }
 
-func runMixedWorkloadReadHeavy(totalOps, connections, numRuns int) {
+func runMixedWorkloadReadHeavy(keyTypes []string, totalOps, connections, numRuns int) {
 	results := make(map[string]*benchmark.MixedWorkloadResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
 		container.Start(container.PostgresConfig)
 
-		result, err := runner.MixedWorkloadReadHeavy(keyType, totalOps, connections)
+		result, err := runner.MixedWorkloadReadHeavy(keyType, readHeavyDataset, totalOps, connections)
 		if err != nil {
 			container.Stop(container.PostgresConfig.ComposeFile)
 			log.Fatalf("Scenario failed for %s: %v", keyType, err)
@@ -283,19 +1440,403 @@ func runMixedWorkloadReadHeavy(totalOps, connections, numRuns int) {
 		container.Stop(container.PostgresConfig.ComposeFile)
 	}
 
-	display.MixedWorkload(results, allKeyTypes, "Read-Heavy (10% insert, 90% read)")
+	display.MixedWorkload(results, keyTypes, "Read-Heavy (10% insert, 90% read)")
+	recordResults("mixed-read-heavy", results)
 }
 
-func runMixedWorkloadBalanced(totalOps, connections, numRuns int) {
+func runMixedWorkloadBalanced(keyTypes []string, totalOps, connections, numRuns int) {
 	results := make(map[string]*benchmark.MixedWorkloadResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.MixedWorkloadBalanced(keyType, balancedDataset, totalOps, connections)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.MixedWorkload(results, keyTypes, "Balanced (50% insert, 30% read, 20% update)")
+	recordResults("mixed-balanced", results)
+}
+
+func runJSONBGin(keyTypes []string, numRecords, batchSize int) {
+	results := make(map[string]*benchmark.JSONBGinResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.JSONBGinPerformance(keyType, numRecords, batchSize)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.JSONBGin(results, keyTypes)
+	recordResults("jsonb-gin", results)
+}
+
+func runCommitOverhead(keyTypes []string, numRecords int) {
+	results := make(map[string]*benchmark.CommitOverheadResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.CommitOverhead(keyType, numRecords, commitOverheadBatchSizes)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.CommitOverhead(results, keyTypes)
+	recordResults("commit-overhead", results)
+}
+
+func runInsertReturning(keyTypes []string, numRecords, connections int) {
+	results := make(map[string]*benchmark.InsertReturningResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.InsertReturning(keyType, numRecords, connections)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.InsertReturning(results, keyTypes)
+	recordResults("insert-returning", results)
+}
+
+func runUpsertPerformance(keyTypes []string, numRecords, numUpserts int) {
+	results := make(map[string]*benchmark.UpsertPerformanceResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.UpsertPerformance(keyType, numRecords, numUpserts, upsertConflictRatio)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.UpsertPerformance(results, keyTypes)
+	recordResults("upsert-performance", results)
+}
+
+func runRangeScan(keyTypes []string, numRecords, numScans int) {
+	results := make(map[string]*benchmark.RangeScanResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.RangeScanPerformance(keyType, numRecords, numScans, rangeScanSize)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.RangeScan(results, keyTypes)
+	recordResults("range-scan", results)
+}
+
+func runCacheCompetition(keyTypes []string, numRecords, numOps int, tuning map[string]string) {
+	// Shrink the buffer pool for this scenario; an explicit -pg-tuning shared_buffers wins
+	settings := map[string]string{"shared_buffers": cacheCompetitionSharedBuffers}
+	maps.Copy(settings, tuning)
+	container.PostgresConfig.AfterStart = func() error {
+		return postgres.ApplyTuning(settings)
+	}
+
+	results := make(map[string]*benchmark.CacheCompetitionResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.CacheCompetition(keyType, numRecords, numOps)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.CacheCompetition(results, keyTypes)
+	recordResults("cache-competition", results)
+}
+
+// parseWorkingSetFractions parses -working-set-fractions into ascending multiples
+func parseWorkingSetFractions(spec string) ([]float64, error) {
+	var fractions []float64
+	for _, entry := range strings.Split(spec, ",") {
+		fraction, err := strconv.ParseFloat(strings.TrimSpace(entry), 64)
+		if err != nil || fraction <= 0 {
+			return nil, fmt.Errorf("expected positive multiples of shared_buffers, got %q", entry)
+		}
+		fractions = append(fractions, fraction)
+	}
+	sort.Float64s(fractions)
+	return fractions, nil
+}
+
+func runWorkingSetSweep(keyTypes []string, fractions []float64, numOps int, tuning map[string]string) {
+	// Shrink the buffer pool for this scenario; an explicit -pg-tuning shared_buffers wins
+	settings := map[string]string{"shared_buffers": workingSetSharedBuffers}
+	maps.Copy(settings, tuning)
+	container.PostgresConfig.AfterStart = func() error {
+		return postgres.ApplyTuning(settings)
+	}
+
+	results := make(map[string]*benchmark.WorkingSetSweepResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.WorkingSetSweep(keyType, fractions, numOps)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.WorkingSetSweep(results, keyTypes)
+	recordResults("working-set-sweep", results)
+}
+
+// runBatchVsSingle runs insert-performance per key type with single-row inserts and
+// with batchSize rows per transaction, each on a fresh container
+func runBatchVsSingle(keyTypes []string, numRecords, batchSize, connections int) {
+	single := make(map[string]*benchmark.InsertPerformanceResult)
+	batched := make(map[string]*benchmark.InsertPerformanceResult)
+
+	for _, keyType := range keyTypes {
+		for _, size := range []int{1, batchSize} {
+			fmt.Printf("\nTesting %s (batch size %d)\n", strings.ToUpper(keyType), size)
+			fmt.Println(strings.Repeat("-", 70))
+
+			container.Start(container.PostgresConfig)
+
+			result, err := runner.InsertPerformance(keyType, numRecords, size, connections)
+			if err != nil {
+				container.Stop(container.PostgresConfig.ComposeFile)
+				log.Fatalf("Scenario failed for %s: %v", keyType, err)
+			}
+
+			if size == 1 {
+				single[keyType] = result
+			} else {
+				batched[keyType] = result
+			}
+			container.Stop(container.PostgresConfig.ComposeFile)
+		}
+	}
+
+	display.BatchVsSingle(single, batched, keyTypes, batchSize)
+
+	both := make(map[string]map[string]*benchmark.InsertPerformanceResult)
+	for _, keyType := range keyTypes {
+		both[keyType] = map[string]*benchmark.InsertPerformanceResult{"single": single[keyType], "batched": batched[keyType]}
+	}
+	recordResults("batch-vs-single", both)
+}
+
+// runInsertOrder runs insert-performance for uuidv7 under each -insert-order
+// progression, plus uuidv4 as the random-key reference
+func runInsertOrder(numRecords, batchSize, connections int) {
+	results := make(map[string]*benchmark.InsertPerformanceResult)
+	defer func(order string) { runner.Options.InsertOrder = order }(runner.Options.InsertOrder)
+
+	for _, order := range append(append([]string{}, pgbench.InsertOrders...), "") {
+		keyType, column := "uuidv7", order
+		if order == "" {
+			keyType, column = "uuidv4", "uuidv4"
+			fmt.Printf("\nTesting UUIDV4 (reference)\n")
+		} else {
+			fmt.Printf("\nTesting UUIDV7 (%s order)\n", order)
+		}
+		fmt.Println(strings.Repeat("-", 70))
+
+		runner.Options.InsertOrder = order
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.InsertPerformance(keyType, numRecords, batchSize, connections)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", column, err)
+		}
+
+		results[column] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.InsertOrder(results, pgbench.InsertOrders, batchSize)
+	recordResults("insert-order", results)
+}
+
+// runExportFigures runs insert-performance at each figureScales size and
+// read-after-fragmentation once per key type, then writes the standard figures into dir:
+// page_splits, fragmentation_vs_scale, buffer_hit_ratio and write_amplification
+func runExportFigures(keyTypes []string, dir string, numRecords, numOps, batchSize, connections int) {
+	scaled := make([]map[string]*benchmark.InsertPerformanceResult, len(figureScales))
+	xs := make([]float64, len(figureScales))
+	for i, scale := range figureScales {
+		records := max(int(float64(numRecords)*scale), 1)
+		xs[i] = float64(records)
+		scaled[i] = make(map[string]*benchmark.InsertPerformanceResult)
+
+		for _, keyType := range keyTypes {
+			fmt.Printf("\nTesting %s (%d records)\n", strings.ToUpper(keyType), records)
+			fmt.Println(strings.Repeat("-", 70))
+
+			container.Start(container.PostgresConfig)
+
+			result, err := runner.InsertPerformance(keyType, records, batchSize, connections)
+			if err != nil {
+				container.Stop(container.PostgresConfig.ComposeFile)
+				log.Fatalf("Scenario failed for %s: %v", keyType, err)
+			}
+
+			scaled[i][keyType] = result
+			container.Stop(container.PostgresConfig.ComposeFile)
+		}
+	}
+	inserts := scaled[len(scaled)-1]
+	reads := collectReadAfterFragmentationResults(keyTypes, numRecords, numOps)
+
+	display.InsertPerformance(inserts, keyTypes, connections, batchSize)
+	display.ReadAfterFragmentation(reads, keyTypes)
+
+	insertValue := func(keyType, name string) float64 {
+		value, _ := inserts[keyType].MetricValue(name)
+		return value
+	}
+	readValue := func(keyType, name string) float64 {
+		value, _ := reads[keyType].MetricValue(name)
+		return value
+	}
+
+	var scripts []string
+	addFigure := func(scriptPath string, err error) {
+		if err != nil {
+			log.Fatalf("Failed to export figure: %v", err)
+		}
+		scripts = append(scripts, scriptPath)
+	}
+	addFigure(export.BarFigure(dir, "page_splits", keyTypes, []string{"page_splits"}, insertValue))
+	addFigure(export.LineFigure(dir, "fragmentation_vs_scale", "records", "fragmentation", xs, keyTypes, func(keyType string, i int) float64 {
+		return scaled[i][keyType].Fragmentation.FragmentationPercent
+	}))
+	addFigure(export.BarFigure(dir, "buffer_hit_ratio", keyTypes, []string{"buffer_hit_ratio", "index_hit_ratio"}, readValue))
+	addFigure(export.BarFigure(dir, "write_amplification", keyTypes, []string{"write_amplification"}, insertValue))
+
+	fmt.Println()
+	for _, script := range scripts {
+		fmt.Printf("✓ figure: %s (run: gnuplot %s)\n", script, filepath.Base(script))
+	}
+	fmt.Printf("Render all from %s with: for f in *.gp; do gnuplot \"$f\"; done\n", dir)
+}
+
+// runConnectionScaling runs once on a single container, as connection setup does not
+// depend on the key type
+func runConnectionScaling(numOps int) {
+	container.Start(container.PostgresConfig)
+
+	result, err := runner.ConnectionScaling(connectionScalingClients, numOps)
+	container.Stop(container.PostgresConfig.ComposeFile)
+	if err != nil {
+		log.Fatalf("Scenario failed: %v", err)
+	}
+
+	display.ConnectionScaling(result)
+	recordResults("connection-scaling", result)
+}
+
+func runReindexMaintenance(keyTypes []string, numRecords, batchSize int) {
+	results := make(map[string]*benchmark.ReindexMaintenanceResult)
+
+	for _, keyType := range keyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		container.Start(container.PostgresConfig)
+
+		result, err := runner.ReindexMaintenance(keyType, numRecords, batchSize, reindexCycles)
+		if err != nil {
+			container.Stop(container.PostgresConfig.ComposeFile)
+			log.Fatalf("Scenario failed for %s: %v", keyType, err)
+		}
+
+		results[keyType] = result
+		container.Stop(container.PostgresConfig.ComposeFile)
+	}
+
+	display.ReindexMaintenance(results, keyTypes)
+	recordResults("reindex-maintenance", results)
+}
+
+func runUpdateChurn(keyTypes []string, numRecords, numUpdates int) {
+	results := make(map[string]*benchmark.UpdateChurnResult)
+
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
 		container.Start(container.PostgresConfig)
 
-		result, err := runner.MixedWorkloadBalanced(keyType, totalOps, connections)
+		result, err := runner.UpdateChurn(keyType, numRecords, numUpdates, updateChurnRounds)
 		if err != nil {
 			container.Stop(container.PostgresConfig.ComposeFile)
 			log.Fatalf("Scenario failed for %s: %v", keyType, err)
@@ -305,14 +1846,60 @@ func runMixedWorkloadBalanced(totalOps, connections, numRuns int) {
 		container.Stop(container.PostgresConfig.ComposeFile)
 	}
 
-	display.MixedWorkload(results, allKeyTypes, "Balanced (50% insert, 30% read, 20% update)")
+	display.UpdateChurn(results, keyTypes)
+	recordResults("update-churn", results)
+}
+
+// serverScenarios maps scenario names to single key type runs for -serve mode
+func serverScenarios() map[string]server.ScenarioFunc {
+	return map[string]server.ScenarioFunc{
+		"insert-performance": func(cfg server.Config, keyType string) (any, error) {
+			return runner.InsertPerformance(keyType, cfg.NumRecords, cfg.BatchSize, cfg.Connections)
+		},
+		"read-after-fragmentation": func(cfg server.Config, keyType string) (any, error) {
+			return runner.ReadAfterFragmentation(keyType, cfg.NumRecords, cfg.NumOps)
+		},
+		"update-performance": func(cfg server.Config, keyType string) (any, error) {
+			return runner.UpdatePerformance(keyType, cfg.NumRecords, cfg.NumOps, cfg.BatchSize)
+		},
+		"mixed-insert-heavy": func(cfg server.Config, keyType string) (any, error) {
+			return runner.MixedWorkloadInsertHeavy(keyType, insertHeavyDataset, cfg.NumOps, cfg.Connections, cfg.BatchSize)
+		},
+		"mixed-read-heavy": func(cfg server.Config, keyType string) (any, error) {
+			return runner.MixedWorkloadReadHeavy(keyType, readHeavyDataset, cfg.NumOps, cfg.Connections)
+		},
+		"mixed-balanced": func(cfg server.Config, keyType string) (any, error) {
+			return runner.MixedWorkloadBalanced(keyType, balancedDataset, cfg.NumOps, cfg.Connections)
+		},
+		"jsonb-gin": func(cfg server.Config, keyType string) (any, error) {
+			return runner.JSONBGinPerformance(keyType, cfg.NumRecords, cfg.BatchSize)
+		},
+		"commit-overhead": func(cfg server.Config, keyType string) (any, error) {
+			return runner.CommitOverhead(keyType, cfg.NumRecords, commitOverheadBatchSizes)
+		},
+		"insert-returning": func(cfg server.Config, keyType string) (any, error) {
+			return runner.InsertReturning(keyType, cfg.NumRecords, cfg.Connections)
+		},
+		"upsert-performance": func(cfg server.Config, keyType string) (any, error) {
+			return runner.UpsertPerformance(keyType, cfg.NumRecords, cfg.NumOps, upsertConflictRatio)
+		},
+		"range-scan": func(cfg server.Config, keyType string) (any, error) {
+			return runner.RangeScanPerformance(keyType, cfg.NumRecords, cfg.NumOps, rangeScanSize)
+		},
+		"reindex-maintenance": func(cfg server.Config, keyType string) (any, error) {
+			return runner.ReindexMaintenance(keyType, cfg.NumRecords, cfg.BatchSize, reindexCycles)
+		},
+		"update-churn": func(cfg server.Config, keyType string) (any, error) {
+			return runner.UpdateChurn(keyType, cfg.NumRecords, cfg.NumOps, updateChurnRounds)
+		},
+	}
 }
 
 // Helper functions for runAllScenarios - collect results without displaying
-func collectInsertPerformanceResults(numRecords, batchSize, connections int) map[string]*benchmark.InsertPerformanceResult {
+func collectInsertPerformanceResults(keyTypes []string, numRecords, batchSize, connections int) map[string]*benchmark.InsertPerformanceResult {
 	results := make(map[string]*benchmark.InsertPerformanceResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
@@ -331,10 +1918,10 @@ func collectInsertPerformanceResults(numRecords, batchSize, connections int) map
 	return results
 }
 
-func collectReadAfterFragmentationResults(numRecords, numOps int) map[string]*benchmark.ReadAfterFragmentationResult {
+func collectReadAfterFragmentationResults(keyTypes []string, numRecords, numOps int) map[string]*benchmark.ReadAfterFragmentationResult {
 	results := make(map[string]*benchmark.ReadAfterFragmentationResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
@@ -353,10 +1940,10 @@ func collectReadAfterFragmentationResults(numRecords, numOps int) map[string]*be
 	return results
 }
 
-func collectUpdatePerformanceResults(numRecords, numOps, batchSize int) map[string]*benchmark.UpdatePerformanceResult {
+func collectUpdatePerformanceResults(keyTypes []string, numRecords, numOps, batchSize int) map[string]*benchmark.UpdatePerformanceResult {
 	results := make(map[string]*benchmark.UpdatePerformanceResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
@@ -375,16 +1962,16 @@ func collectUpdatePerformanceResults(numRecords, numOps, batchSize int) map[stri
 	return results
 }
 
-func collectMixedWorkloadInsertHeavyResults(totalOps, connections, batchSize int) map[string]*benchmark.MixedWorkloadResult {
+func collectMixedWorkloadInsertHeavyResults(keyTypes []string, totalOps, connections, batchSize int) map[string]*benchmark.MixedWorkloadResult {
 	results := make(map[string]*benchmark.MixedWorkloadResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
 		container.Start(container.PostgresConfig)
 
-		result, err := runner.MixedWorkloadInsertHeavy(keyType, totalOps, connections, batchSize)
+		result, err := runner.MixedWorkloadInsertHeavy(keyType, insertHeavyDataset, totalOps, connections, batchSize)
 		if err != nil {
 			container.Stop(container.PostgresConfig.ComposeFile)
 			log.Fatalf("Scenario failed for %s: %v", keyType, err)
@@ -397,16 +1984,16 @@ func collectMixedWorkloadInsertHeavyResults(totalOps, connections, batchSize int
 	return results
 }
 
-func collectMixedWorkloadReadHeavyResults(totalOps, connections int) map[string]*benchmark.MixedWorkloadResult {
+func collectMixedWorkloadReadHeavyResults(keyTypes []string, totalOps, connections int) map[string]*benchmark.MixedWorkloadResult {
 	results := make(map[string]*benchmark.MixedWorkloadResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
 		container.Start(container.PostgresConfig)
 
-		result, err := runner.MixedWorkloadReadHeavy(keyType, totalOps, connections)
+		result, err := runner.MixedWorkloadReadHeavy(keyType, readHeavyDataset, totalOps, connections)
 		if err != nil {
 			container.Stop(container.PostgresConfig.ComposeFile)
 			log.Fatalf("Scenario failed for %s: %v", keyType, err)
@@ -419,16 +2006,16 @@ func collectMixedWorkloadReadHeavyResults(totalOps, connections int) map[string]
 	return results
 }
 
-func collectMixedWorkloadBalancedResults(totalOps, connections int) map[string]*benchmark.MixedWorkloadResult {
+func collectMixedWorkloadBalancedResults(keyTypes []string, totalOps, connections int) map[string]*benchmark.MixedWorkloadResult {
 	results := make(map[string]*benchmark.MixedWorkloadResult)
 
-	for _, keyType := range allKeyTypes {
+	for _, keyType := range keyTypes {
 		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
 		fmt.Println(strings.Repeat("-", 70))
 
 		container.Start(container.PostgresConfig)
 
-		result, err := runner.MixedWorkloadBalanced(keyType, totalOps, connections)
+		result, err := runner.MixedWorkloadBalanced(keyType, balancedDataset, totalOps, connections)
 		if err != nil {
 			container.Stop(container.PostgresConfig.ComposeFile)
 			log.Fatalf("Scenario failed for %s: %v", keyType, err)
@@ -441,7 +2028,7 @@ func collectMixedWorkloadBalancedResults(totalOps, connections int) map[string]*
 	return results
 }
 
-func runAllScenarios(numRecords, numOps, connections, batchSize, numRuns int, output string) {
+func runAllScenarios(keyTypes []string, numRecords, numOps, connections, batchSize, numRuns int, output string) {
 	fmt.Println("\n" + strings.Repeat("=", 100))
 	fmt.Println("RUNNING ALL SCENARIOS - COMPREHENSIVE BENCHMARK SUITE")
 	fmt.Println(strings.Repeat("=", 100))
@@ -452,27 +2039,33 @@ func runAllScenarios(numRecords, numOps, connections, batchSize, numRuns int, ou
 	// Collect all results first
 	fmt.Println("\n[1/6] INSERT PERFORMANCE")
 	fmt.Println(strings.Repeat("=", 100))
-	insertResults := collectInsertPerformanceResults(numRecords, batchSize, connections)
+	insertResults := collectInsertPerformanceResults(keyTypes, numRecords, batchSizeFor("insert-performance", batchSize), connections)
+	recordResults("insert-performance", insertResults)
 
 	fmt.Println("\n[2/6] READ AFTER FRAGMENTATION")
 	fmt.Println(strings.Repeat("=", 100))
-	readResults := collectReadAfterFragmentationResults(numRecords, numOps)
+	readResults := collectReadAfterFragmentationResults(keyTypes, numRecords, numOps)
+	recordResults("read-after-fragmentation", readResults)
 
 	fmt.Println("\n[3/6] UPDATE PERFORMANCE")
 	fmt.Println(strings.Repeat("=", 100))
-	updateResults := collectUpdatePerformanceResults(numRecords, numOps, batchSize)
+	updateResults := collectUpdatePerformanceResults(keyTypes, numRecords, numOps, batchSize)
+	recordResults("update-performance", updateResults)
 
 	fmt.Println("\n[4/6] MIXED INSERT-HEAVY")
 	fmt.Println(strings.Repeat("=", 100))
-	mixedInsertHeavyResults := collectMixedWorkloadInsertHeavyResults(numOps, connections, batchSize)
+	mixedInsertHeavyResults := collectMixedWorkloadInsertHeavyResults(keyTypes, numOps, connections, batchSize)
+	recordResults("mixed-insert-heavy", mixedInsertHeavyResults)
 
 	fmt.Println("\n[5/6] MIXED READ-HEAVY")
 	fmt.Println(strings.Repeat("=", 100))
-	mixedReadHeavyResults := collectMixedWorkloadReadHeavyResults(numOps, connections)
+	mixedReadHeavyResults := collectMixedWorkloadReadHeavyResults(keyTypes, numOps, connections)
+	recordResults("mixed-read-heavy", mixedReadHeavyResults)
 
 	fmt.Println("\n[6/6] MIXED BALANCED")
 	fmt.Println(strings.Repeat("=", 100))
-	mixedBalancedResults := collectMixedWorkloadBalancedResults(numOps, connections)
+	mixedBalancedResults := collectMixedWorkloadBalancedResults(keyTypes, numOps, connections)
+	recordResults("mixed-balanced", mixedBalancedResults)
 
 	totalDuration := time.Since(startTime)
 	fmt.Println("\n" + strings.Repeat("=", 100))
@@ -484,10 +2077,10 @@ func runAllScenarios(numRecords, numOps, connections, batchSize, numRuns int, ou
 	fmt.Println("BENCHMARK RESULTS SUMMARY")
 	fmt.Println(strings.Repeat("=", 100))
 
-	display.InsertPerformance(insertResults, allKeyTypes, connections, batchSize)
-	display.ReadAfterFragmentation(readResults, allKeyTypes)
-	display.UpdatePerformance(updateResults, allKeyTypes)
-	display.MixedWorkload(mixedInsertHeavyResults, allKeyTypes, "Insert-Heavy (90% insert, 10% read)")
-	display.MixedWorkload(mixedReadHeavyResults, allKeyTypes, "Read-Heavy (10% insert, 90% read)")
-	display.MixedWorkload(mixedBalancedResults, allKeyTypes, "Balanced (50% insert, 30% read, 20% update)")
+	display.InsertPerformance(insertResults, keyTypes, connections, batchSizeFor("insert-performance", batchSize))
+	display.ReadAfterFragmentation(readResults, keyTypes)
+	display.UpdatePerformance(updateResults, keyTypes)
+	display.MixedWorkload(mixedInsertHeavyResults, keyTypes, "Insert-Heavy (90% insert, 10% read)")
+	display.MixedWorkload(mixedReadHeavyResults, keyTypes, "Read-Heavy (10% insert, 90% read)")
+	display.MixedWorkload(mixedBalancedResults, keyTypes, "Balanced (50% insert, 30% read, 20% update)")
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+	"github.com/moguls753/uuid-benchmark/internal/display"
+	"github.com/moguls753/uuid-benchmark/internal/export"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+func main() {
+	noColor := flag.Bool("no-color", false, "Disable colored output (also disabled by the NO_COLOR env var or when stdout is not a terminal)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-no-color] a.json b.json\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Compares two statistical summaries written by the benchmark's -output option.")
+		fmt.Fprintln(os.Stderr, "Deltas are B relative to A; green is an improvement, red a regression.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	display.SetColor(!*noColor)
+
+	a, err := export.LoadStatsJSON(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", flag.Arg(0), err)
+	}
+	b, err := export.LoadStatsJSON(flag.Arg(1))
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", flag.Arg(1), err)
+	}
+
+	if a.Scenario != b.Scenario {
+		fmt.Printf("Warning: comparing different scenarios (%s vs %s)\n", a.Scenario, b.Scenario)
+	}
+
+	fmt.Printf("A: %s\n", flag.Arg(0))
+	fmt.Printf("B: %s\n", flag.Arg(1))
+	for _, name := range settingNames(a.Settings, b.Settings) {
+		if a.Settings[name] != b.Settings[name] {
+			fmt.Printf("Setting %s: %s (A) vs %s (B)\n", name, settingValue(a.Settings, name), settingValue(b.Settings, name))
+		}
+	}
+
+	for _, keyType := range a.KeyTypes {
+		statsA := a.Results[keyType]
+		statsB, ok := b.Results[keyType]
+		if !ok {
+			fmt.Printf("\n%s: missing in B, skipped\n", strings.ToUpper(keyType))
+			continue
+		}
+
+		fmt.Println()
+		fmt.Println(strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 78))
+		fmt.Printf("%-26s %16s %16s %16s\n", "Metric", "Median A", "Median B", "Delta")
+
+		for _, name := range metricNames(statsA, statsB) {
+			medianA := statsA[name].Median
+			medianB := statsB[name].Median
+
+			delta := "n/a"
+			if medianA != 0 {
+				delta = fmt.Sprintf("%+.1f%%", (medianB-medianA)/medianA*100)
+			}
+			delta = fmt.Sprintf("%16s", delta)
+
+			if m, ok := metric.Lookup(name); ok && medianA != medianB {
+				improved := (medianB > medianA) == m.HigherIsBetter
+				if improved {
+					delta = display.Green(delta)
+				} else {
+					delta = display.Red(delta)
+				}
+			}
+
+			fmt.Printf("%-26s %16.2f %16.2f %s\n", name, medianA, medianB, delta)
+		}
+	}
+
+	for _, keyType := range b.KeyTypes {
+		if _, ok := a.Results[keyType]; !ok {
+			fmt.Printf("\n%s: missing in A, skipped\n", strings.ToUpper(keyType))
+		}
+	}
+}
+
+// metricNames returns the metrics present in both summaries, registry metrics first
+func metricNames(a, b map[string]statistics.Stats) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, m := range metric.Registry {
+		_, inA := a[m.Name]
+		_, inB := b[m.Name]
+		if inA && inB {
+			names = append(names, m.Name)
+			seen[m.Name] = true
+		}
+	}
+
+	var extra []string
+	for name := range a {
+		if _, inB := b[name]; inB && !seen[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+
+	return append(names, extra...)
+}
+
+// settingNames returns the run settings recorded in either summary, sorted
+func settingNames(a, b map[string]string) []string {
+	var names []string
+	for name := range a {
+		names = append(names, name)
+	}
+	for name := range b {
+		if _, inA := a[name]; !inA {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// settingValue returns a recorded setting, or "unrecorded" for summaries predating it
+func settingValue(settings map[string]string, name string) string {
+	if value, ok := settings[name]; ok {
+		return value
+	}
+	return "unrecorded"
+}
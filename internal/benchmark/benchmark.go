@@ -2,6 +2,7 @@ package benchmark
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"time"
 )
@@ -17,8 +18,20 @@ type BenchmarkResult struct {
 	TableSize           int64
 	IndexSize           int64
 	Fragmentation       IndexFragmentationStats
-	BufferHitRatio      float64 // Cache hit ratio (0.0 to 1.0)
-	IndexBufferHitRatio float64 // Index-specific cache hit ratio
+	BufferHitRatio      float64     // Cache hit ratio (0.0 to 1.0)
+	IndexBufferHitRatio float64     // Index-specific cache hit ratio
+	BlocksRead          int64       // Index + heap blocks read from outside shared buffers since the last stats reset
+	WALBytes            int64       // WAL generated over the measured insert range
+	FPIBytes            int64       // Full-page image bytes within WALBytes, as stored (compressed with wal_compression)
+	IndexPagesDirtied   int64       // Distinct B-tree pages modified over the measured insert range
+	Tuples              *TupleStats // Heap breakdown from pgstattuple, nil unless -measure-bloat
+}
+
+// TupleStats is pgstattuple's breakdown of the table heap
+type TupleStats struct {
+	TupleCount       int64   // Live tuples
+	DeadTuplePercent float64 // Space held by dead tuples, % of the table
+	FreePercent      float64 // Free space, % of the table
 }
 
 type IndexFragmentationStats struct {
@@ -28,16 +41,82 @@ type IndexFragmentationStats struct {
 	EmptyPages           int64
 }
 
+// SetupTiming breaks down the DDL/setup phase that precedes a workload
+type SetupTiming struct {
+	Extensions  time.Duration // CREATE EXTENSION statements and uuidv7 setup in Connect
+	DropTable   time.Duration // DROP TABLE IF EXISTS
+	CreateTable time.Duration // CREATE TABLE plus any extra columns and indexes
+}
+
+// Total returns the whole setup time
+func (s SetupTiming) Total() time.Duration {
+	return s.Extensions + s.DropTable + s.CreateTable
+}
+
 // ConcurrentBenchmarkResult holds results from concurrent pgbench operations
 type ConcurrentBenchmarkResult struct {
 	Duration     time.Duration
 	TotalOps     int
 	Throughput   float64
-	LatencyP50   time.Duration
-	LatencyP95   time.Duration
-	LatencyP99   time.Duration
+	Latency      map[float64]time.Duration // Transaction latency per percentile in Percentiles
 	SuccessCount int
 	ErrorCount   int
+
+	InitialConnectionTime    time.Duration // Connection setup time reported by pgbench
+	ThroughputIncludingSetup float64       // TPS including connection setup
+}
+
+// KeyWidthBytes is the on-disk width of the id column for each key type
+var KeyWidthBytes = map[string]int{
+	"bigserial":      8,
+	"snowflake":      8,
+	"uuidv4":         16,
+	"uuidv7":         16,
+	"uuidv1":         16,
+	"uuidv6":         16,
+	"uuidv8":         16,
+	"ulid":           16,
+	"ulid_monotonic": 16,
+	"ksuid":          28, // 27 base62 characters plus a 1-byte varlena header
+}
+
+// Logical payload of one inserted row besides the key: data TEXT ('test_data_<n>'
+// plus varlena header) and the created_at TIMESTAMP
+const (
+	dataColumnBytes      = 12
+	createdAtColumnBytes = 8
+)
+
+// payloadBytes is the width data values are padded to, 0 = unpadded; set via
+// SetPayloadBytes
+var payloadBytes int
+
+// SetPayloadBytes makes EstimatedRowBytes count data values padded to n bytes
+func SetPayloadBytes(n int) {
+	payloadBytes = n
+}
+
+// EstimatedRowBytes estimates the logical bytes of one inserted row, excluding tuple
+// headers, indexes and WAL, which are what write amplification measures
+func EstimatedRowBytes(keyType string) int {
+	dataBytes := dataColumnBytes
+	if payloadBytes > 0 {
+		// Values of 127 bytes and more take a 4-byte varlena header instead of 1
+		dataBytes = payloadBytes + 1
+		if payloadBytes >= 127 {
+			dataBytes = payloadBytes + 4
+		}
+	}
+	return KeyWidthBytes[keyType] + dataBytes + createdAtColumnBytes
+}
+
+// WriteAmplification returns bytes written to disk per logical byte inserted
+func WriteAmplification(writeBytes uint64, numRecords int, keyType string) float64 {
+	logicalBytes := float64(numRecords * EstimatedRowBytes(keyType))
+	if logicalBytes == 0 {
+		return 0
+	}
+	return float64(writeBytes) / logicalBytes
 }
 
 func FormatBytes(bytes int64) string {
@@ -53,9 +132,19 @@ func FormatBytes(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-func CalculatePercentiles(latencies []time.Duration) (p50, p95, p99 time.Duration) {
+// Percentiles are the latency percentiles every scenario reports, set via -percentiles
+var Percentiles = []float64{50, 95, 99}
+
+// SetPercentiles replaces the reported latency percentiles
+func SetPercentiles(percentiles []float64) {
+	Percentiles = percentiles
+}
+
+// CalculatePercentiles returns the nearest-rank latency at each percentile, sorting
+// latencies in place
+func CalculatePercentiles(latencies []time.Duration, percentiles []float64) map[float64]time.Duration {
 	if len(latencies) == 0 {
-		return 0, 0, 0
+		return nil
 	}
 
 	sort.Slice(latencies, func(i, j int) bool {
@@ -63,9 +152,15 @@ func CalculatePercentiles(latencies []time.Duration) (p50, p95, p99 time.Duratio
 	})
 
 	n := len(latencies)
-	p50 = latencies[n*50/100]
-	p95 = latencies[n*95/100]
-	p99 = latencies[n*99/100]
+	result := make(map[float64]time.Duration, len(percentiles))
+	for _, p := range percentiles {
+		// Rank ceil(p% of n), 1-based, is the smallest value with at least p% of the
+		// latencies at or below it. It is computed in integers from p in millionths of a
+		// percent, as floating point puts whole ranks such as 99.9% of 82000 just above.
+		millionths := int64(math.Round(p * 1e6))
+		rank := (millionths*int64(n) + 1e8 - 1) / 1e8
+		result[p] = latencies[max(0, min(int(rank)-1, n-1))]
+	}
 
-	return p50, p95, p99
+	return result
 }
@@ -0,0 +1,83 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+)
+
+// latencySeries returns 1ms..n ms in reverse order, so the i-th smallest is i ms
+func latencySeries(n int) []time.Duration {
+	latencies := make([]time.Duration, n)
+	for i := range latencies {
+		latencies[i] = time.Duration(n-i) * time.Millisecond
+	}
+	return latencies
+}
+
+func TestCalculatePercentiles(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		want map[float64]time.Duration
+	}{
+		{"n=1", 1, map[float64]time.Duration{0: 1 * time.Millisecond, 50: 1 * time.Millisecond, 99: 1 * time.Millisecond, 100: 1 * time.Millisecond}},
+		{"n=2", 2, map[float64]time.Duration{0: 1 * time.Millisecond, 50: 1 * time.Millisecond, 51: 2 * time.Millisecond, 99: 2 * time.Millisecond, 100: 2 * time.Millisecond}},
+		{"n=100", 100, map[float64]time.Duration{50: 50 * time.Millisecond, 95: 95 * time.Millisecond, 99: 99 * time.Millisecond, 99.9: 100 * time.Millisecond, 100: 100 * time.Millisecond}},
+		{"n=1000", 1000, map[float64]time.Duration{50: 500 * time.Millisecond, 99: 990 * time.Millisecond, 99.9: 999 * time.Millisecond, 99.99: 1000 * time.Millisecond, 100: 1000 * time.Millisecond}},
+		// p% of n is a whole rank here, which floating point puts just above it
+		{"n=41000", 41000, map[float64]time.Duration{99.9: 40959 * time.Millisecond}},
+		{"n=82000", 82000, map[float64]time.Duration{99.9: 81918 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percentiles := make([]float64, 0, len(tt.want))
+			for p := range tt.want {
+				percentiles = append(percentiles, p)
+			}
+
+			got := CalculatePercentiles(latencySeries(tt.n), percentiles)
+			for p, want := range tt.want {
+				if got[p] != want {
+					t.Errorf("p%v = %v, want %v", p, got[p], want)
+				}
+			}
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		if got := CalculatePercentiles(nil, []float64{50}); got != nil {
+			t.Errorf("got %v, want nil", got)
+		}
+	})
+}
+
+func TestMixedWorkloadOperationThroughputs(t *testing.T) {
+	// Each fake client runs 500 inserts and 250 reads spread over the same one-second run
+	run := func(clients int) *MixedWorkloadResult {
+		r := &MixedWorkloadResult{
+			Duration:          time.Second,
+			ObservedInsertOps: 500 * clients,
+			ObservedReadOps:   250 * clients,
+		}
+		r.SetOperationThroughputs()
+		return r
+	}
+
+	one, two := run(1), run(2)
+	if one.InsertThroughput != 500 || one.ReadThroughput != 250 || one.UpdateThroughput != 0 {
+		t.Errorf("1 client: insert %v, read %v, update %v; want 500, 250, 0", one.InsertThroughput, one.ReadThroughput, one.UpdateThroughput)
+	}
+	// Summing the clients' latencies would count two seconds and keep 500 inserts/sec
+	if two.InsertThroughput != 2*one.InsertThroughput || two.ReadThroughput != 2*one.ReadThroughput {
+		t.Errorf("2 clients: insert %v, read %v; want double the single client's", two.InsertThroughput, two.ReadThroughput)
+	}
+
+	t.Run("zero duration", func(t *testing.T) {
+		r := &MixedWorkloadResult{ObservedInsertOps: 10}
+		r.SetOperationThroughputs()
+		if r.InsertThroughput != 0 {
+			t.Errorf("insert throughput = %v, want 0", r.InsertThroughput)
+		}
+	})
+}
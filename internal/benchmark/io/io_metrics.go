@@ -11,7 +11,8 @@ import (
 	"time"
 )
 
-// IOStats represents I/O statistics from cgroup io.stat
+// IOStats represents I/O statistics from cgroup v2 io.stat or the cgroup v1 blkio
+// throttle counters
 type IOStats struct {
 	ReadBytes  uint64
 	WriteBytes uint64
@@ -28,7 +29,8 @@ type IOMetrics struct {
 	WriteThroughputMB float64
 }
 
-// GetContainerIOStats reads I/O statistics from cgroup v2 io.stat for a container
+// GetContainerIOStats reads I/O statistics from cgroup v2 io.stat for a container, or
+// from the blkio controller on cgroup v1 hosts
 func GetContainerIOStats(containerName string) (*IOStats, error) {
 	// Path to cgroup v2 io.stat for the container
 	// Docker containers are typically under /sys/fs/cgroup/system.slice/docker-<container_id>.scope/
@@ -40,6 +42,10 @@ func GetContainerIOStats(containerName string) (*IOStats, error) {
 		return nil, fmt.Errorf("failed to find container cgroup: %w", err)
 	}
 
+	if !cgroupV2() {
+		return readBlkioStats(cgroupPath)
+	}
+
 	ioStatPath := cgroupPath + "/io.stat"
 	file, err := os.Open(ioStatPath)
 	if err != nil {
@@ -95,6 +101,66 @@ func GetContainerIOStats(containerName string) (*IOStats, error) {
 	return stats, nil
 }
 
+// readBlkioStats reads the cgroup v1 blkio throttle counters, which count bytes and
+// operations per device and direction whatever the I/O scheduler
+func readBlkioStats(cgroupPath string) (*IOStats, error) {
+	stats := &IOStats{
+		Timestamp: time.Now(),
+	}
+
+	if err := readBlkioFile(cgroupPath+"/blkio.throttle.io_service_bytes", &stats.ReadBytes, &stats.WriteBytes); err != nil {
+		return nil, err
+	}
+	if err := readBlkioFile(cgroupPath+"/blkio.throttle.io_serviced", &stats.ReadOps, &stats.WriteOps); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// readBlkioFile sums the Read and Write lines of a blkio throttle file over all devices
+func readBlkioFile(path string, read, write *uint64) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Format: <major>:<minor> <Read|Write|Sync|Async|Discard|Total> <value>,
+		// followed by a device-less "Total <value>" line
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		value, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		switch fields[1] {
+		case "Read":
+			*read += value
+		case "Write":
+			*write += value
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// cgroupV2 reports whether the host mounts the unified cgroup v2 hierarchy
+func cgroupV2() bool {
+	_, err := os.Stat("/sys/fs/cgroup/cgroup.controllers")
+	return err == nil
+}
+
 // CalculateIOMetrics calculates I/O metrics from two IOStats snapshots
 func CalculateIOMetrics(start, end *IOStats) IOMetrics {
 	duration := end.Timestamp.Sub(start.Timestamp).Seconds()
@@ -135,9 +201,19 @@ func findContainerCgroupPath(containerName string) (string, error) {
 		fmt.Sprintf("/sys/fs/cgroup/system.slice/docker-%s.scope", containerID),
 		fmt.Sprintf("/sys/fs/cgroup/docker/%s", containerID),
 	}
+	statFile := "/io.stat"
+
+	// cgroup v1 mounts each controller separately; I/O lives under blkio
+	if !cgroupV2() {
+		possiblePaths = []string{
+			fmt.Sprintf("/sys/fs/cgroup/blkio/system.slice/docker-%s.scope", containerID),
+			fmt.Sprintf("/sys/fs/cgroup/blkio/docker/%s", containerID),
+		}
+		statFile = "/blkio.throttle.io_serviced"
+	}
 
 	for _, path := range possiblePaths {
-		if _, err := os.Stat(path + "/io.stat"); err == nil {
+		if _, err := os.Stat(path + statFile); err == nil {
 			return path, nil
 		}
 	}
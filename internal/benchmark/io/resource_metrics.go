@@ -0,0 +1,167 @@
+package docker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResourceUsage represents container CPU and memory usage over a sampling period
+type ResourceUsage struct {
+	AvgCPUPercent  float64 // Average CPU usage (100% = one fully used core)
+	PeakCPUPercent float64 // Highest CPU usage observed in a single sampling interval
+	AvgRSSMB       float64 // Average anonymous (RSS) memory in MB
+	PeakRSSMB      float64 // Highest anonymous (RSS) memory observed in MB
+	Samples        int
+}
+
+// ResourceSampler periodically samples cgroup v2 cpu.stat and memory.stat for a container
+type ResourceSampler struct {
+	cgroupPath string
+	interval   time.Duration
+	stop       chan struct{}
+	done       chan struct{}
+	once       sync.Once
+	usage      ResourceUsage
+}
+
+// StartResourceSampler starts a goroutine sampling the container's CPU and memory usage
+// every interval until Stop is called
+func StartResourceSampler(containerName string, interval time.Duration) (*ResourceSampler, error) {
+	cgroupPath, err := findContainerCgroupPath(containerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find container cgroup: %w", err)
+	}
+
+	startUsage, err := readCPUUsage(cgroupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ResourceSampler{
+		cgroupPath: cgroupPath,
+		interval:   interval,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	go s.run(startUsage, time.Now())
+
+	return s, nil
+}
+
+// Stop ends sampling and returns the aggregated usage. It is safe to call on a nil
+// sampler and to call more than once.
+func (s *ResourceSampler) Stop() ResourceUsage {
+	if s == nil {
+		return ResourceUsage{}
+	}
+
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+
+	return s.usage
+}
+
+func (s *ResourceSampler) run(startUsage uint64, startTime time.Time) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	lastUsage, lastTime := startUsage, startTime
+	totalRSS := 0.0
+
+	sample := func() {
+		now := time.Now()
+
+		usage, err := readCPUUsage(s.cgroupPath)
+		if err != nil {
+			return
+		}
+		rss, err := readAnonMemory(s.cgroupPath)
+		if err != nil {
+			return
+		}
+
+		elapsed := now.Sub(lastTime).Microseconds()
+		if elapsed > 0 {
+			cpuPercent := float64(usage-lastUsage) / float64(elapsed) * 100
+			if cpuPercent > s.usage.PeakCPUPercent {
+				s.usage.PeakCPUPercent = cpuPercent
+			}
+		}
+
+		rssMB := float64(rss) / (1024 * 1024)
+		if rssMB > s.usage.PeakRSSMB {
+			s.usage.PeakRSSMB = rssMB
+		}
+		totalRSS += rssMB
+		s.usage.Samples++
+
+		if total := now.Sub(startTime).Microseconds(); total > 0 {
+			s.usage.AvgCPUPercent = float64(usage-startUsage) / float64(total) * 100
+		}
+		s.usage.AvgRSSMB = totalRSS / float64(s.usage.Samples)
+
+		lastUsage, lastTime = usage, now
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			sample()
+		case <-s.stop:
+			sample()
+			return
+		}
+	}
+}
+
+// readCPUUsage returns the cumulative CPU time (usage_usec) from cpu.stat
+func readCPUUsage(cgroupPath string) (uint64, error) {
+	value, err := readStatField(cgroupPath+"/cpu.stat", "usage_usec")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read cpu.stat: %w", err)
+	}
+	return value, nil
+}
+
+// readAnonMemory returns the anonymous memory (RSS) in bytes from memory.stat
+func readAnonMemory(cgroupPath string) (uint64, error) {
+	value, err := readStatField(cgroupPath+"/memory.stat", "anon")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read memory.stat: %w", err)
+	}
+	return value, nil
+}
+
+// readStatField reads a single "key value" line from a flat-keyed cgroup file
+func readStatField(path, key string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != key {
+			continue
+		}
+		return strconv.ParseUint(fields[1], 10, 64)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("field %s not found in %s", key, path)
+}
@@ -0,0 +1,307 @@
+package benchmark
+
+import (
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+// The MetricValue methods return a result's value for a registry metric name, in the
+// registry's unit, and false if the scenario does not report that metric. In each
+// scenario "throughput" is its primary rate (inserts, reads or updates per second), as
+// in the comparison tables.
+
+func mb(bytes int64) float64 {
+	return float64(bytes) / (1024 * 1024)
+}
+
+func us(d time.Duration) float64 {
+	return float64(d.Microseconds())
+}
+
+// addTuples adds the pgstattuple breakdown, if it was measured
+func addTuples(values map[string]float64, tuples *TupleStats) {
+	if tuples == nil {
+		return
+	}
+	values["dead_tuple_percent"] = tuples.DeadTuplePercent
+	values["free_percent"] = tuples.FreePercent
+}
+
+// addFileStats adds the SQLite database file's page counts, if they were read
+func addFileStats(values map[string]float64, pages, freelistPages int64) {
+	if pages == 0 {
+		return
+	}
+	values["file_pages"] = float64(pages)
+	values["freelist_pages"] = float64(freelistPages)
+}
+
+// addRateLimit adds the schedule lag and skipped transactions of a rate-limited run
+func addRateLimit(values map[string]float64, r *InsertPerformanceResult) {
+	if r.Rate <= 0 {
+		return
+	}
+	values["schedule_lag_avg_us"] = us(r.ScheduleLagAvg)
+	values["schedule_lag_max_us"] = us(r.ScheduleLagMax)
+	values["skipped_transactions"] = float64(r.Skipped)
+}
+
+// addLatencies adds each latency percentile under its metric name
+func addLatencies(values map[string]float64, latency map[float64]time.Duration) {
+	for percentile, d := range latency {
+		values[metric.LatencyName(percentile)] = us(d)
+	}
+}
+
+func (r *InsertPerformanceResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"duration":                   r.Duration.Seconds(),
+		"throughput":                 r.Throughput,
+		"tps":                        r.TPS,
+		"tps_including_setup":        r.TPSIncludingSetup,
+		"connection_time":            r.ConnectionTime.Seconds(),
+		"page_splits":                float64(r.PageSplits),
+		"index_pages_dirtied_per_1k": r.IndexPagesDirtiedPer1k(),
+		"fragmentation":              r.Fragmentation.FragmentationPercent,
+		"avg_leaf_density":           r.Fragmentation.AvgLeafDensity,
+		"table_size_mb":              mb(r.TableSize),
+		"index_size_mb":              mb(r.IndexSize),
+		"index_table_ratio":          r.IndexTableRatio,
+		"read_iops":                  r.ReadIOPS,
+		"write_iops":                 r.WriteIOPS,
+		"read_throughput_mb":         r.ReadThroughputMB,
+		"write_throughput_mb":        r.WriteThroughputMB,
+		"write_amplification":        r.WriteAmplification,
+		"wal_mb":                     mb(r.WALBytes),
+		"fpi_mb":                     mb(r.FPIBytes),
+		"temp_bytes":                 float64(r.TempBytes),
+		"setup_time":                 r.Setup.Total().Seconds(),
+		"avg_cpu_percent":            r.AvgCPUPercent,
+		"peak_cpu_percent":           r.PeakCPUPercent,
+		"peak_rss_mb":                r.PeakRSSMB,
+	}
+	addTuples(values, r.Tuples)
+	addFileStats(values, r.FilePages, r.FreelistPages)
+	addRateLimit(values, r)
+	addLatencies(values, r.Latency)
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *ReadAfterFragmentationResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"duration":            r.ReadDuration.Seconds(),
+		"throughput":          r.ReadThroughput,
+		"buffer_hit_ratio":    r.BufferHitRatio * 100,
+		"index_hit_ratio":     r.IndexBufferHitRatio * 100,
+		"read_amplification":  r.ReadAmplification,
+		"fragmentation":       r.Fragmentation.FragmentationPercent,
+		"avg_leaf_density":    r.Fragmentation.AvgLeafDensity,
+		"read_iops":           r.ReadIOPS,
+		"write_iops":          r.WriteIOPS,
+		"read_throughput_mb":  r.ReadThroughputMB,
+		"write_throughput_mb": r.WriteThroughputMB,
+		"temp_bytes":          float64(r.TempBytes),
+		"setup_time":          r.Setup.Total().Seconds(),
+		"avg_cpu_percent":     r.AvgCPUPercent,
+		"peak_cpu_percent":    r.PeakCPUPercent,
+		"peak_rss_mb":         r.PeakRSSMB,
+	}
+	if len(r.ModeThroughput) > 0 {
+		values["read_throughput_simple"] = r.ModeThroughput["simple"]
+		values["read_throughput_prepared"] = r.ModeThroughput["prepared"]
+		values["parse_plan_share"] = r.ParsePlanShare() * 100
+	}
+	addLatencies(values, r.Latency)
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *UpdatePerformanceResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"duration":            r.UpdateDuration.Seconds(),
+		"throughput":          r.UpdateThroughput,
+		"fragmentation":       r.Fragmentation.FragmentationPercent,
+		"avg_leaf_density":    r.Fragmentation.AvgLeafDensity,
+		"correlation_before":  r.CorrelationBefore,
+		"correlation_after":   r.CorrelationAfter,
+		"correlation_delta":   r.CorrelationDelta,
+		"read_iops":           r.ReadIOPS,
+		"write_iops":          r.WriteIOPS,
+		"read_throughput_mb":  r.ReadThroughputMB,
+		"write_throughput_mb": r.WriteThroughputMB,
+		"temp_bytes":          float64(r.TempBytes),
+		"setup_time":          r.Setup.Total().Seconds(),
+		"avg_cpu_percent":     r.AvgCPUPercent,
+		"peak_cpu_percent":    r.PeakCPUPercent,
+		"peak_rss_mb":         r.PeakRSSMB,
+	}
+	addTuples(values, r.Tuples)
+	if r.VacuumTuples != nil {
+		values["free_percent_after_vacuum"] = r.VacuumTuples.FreePercent
+		values["index_before_vacuum_mb"] = mb(r.IndexSizeBefore)
+		values["index_after_vacuum_mb"] = mb(r.IndexSizeAfter)
+		values["vacuum_time"] = r.VacuumDuration.Seconds()
+	}
+	addFileStats(values, r.FilePages, r.FreelistPages)
+	addLatencies(values, r.Latency)
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *MixedWorkloadResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"duration":            r.Duration.Seconds(),
+		"throughput":          r.OverallThroughput,
+		"tps_including_setup": r.TPSIncludingSetup,
+		"connection_time":     r.ConnectionTime.Seconds(),
+		"insert_throughput":   r.InsertThroughput,
+		"read_throughput":     r.ReadThroughput,
+		"update_throughput":   r.UpdateThroughput,
+		"buffer_hit_ratio":    r.BufferHitRatio * 100,
+		"index_hit_ratio":     r.IndexBufferHitRatio * 100,
+		"fragmentation":       r.Fragmentation.FragmentationPercent,
+		"avg_leaf_density":    r.Fragmentation.AvgLeafDensity,
+		"table_size_mb":       mb(r.TableSize),
+		"index_size_mb":       mb(r.IndexSize),
+		"read_iops":           r.ReadIOPS,
+		"write_iops":          r.WriteIOPS,
+		"read_throughput_mb":  r.ReadThroughputMB,
+		"write_throughput_mb": r.WriteThroughputMB,
+		"temp_bytes":          float64(r.TempBytes),
+		"setup_time":          r.Setup.Total().Seconds(),
+		"avg_cpu_percent":     r.AvgCPUPercent,
+		"peak_cpu_percent":    r.PeakCPUPercent,
+		"peak_rss_mb":         r.PeakRSSMB,
+	}
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *JSONBGinResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"duration":          r.Duration.Seconds(),
+		"throughput":        r.Throughput,
+		"page_splits":       float64(r.PageSplits),
+		"table_size_mb":     mb(r.TableSize),
+		"index_size_mb":     mb(r.PKIndexSize),
+		"gin_index_size_mb": mb(r.GinIndexSize),
+		"gin_build_time":    r.GinBuildDuration.Seconds(),
+		"temp_bytes":        float64(r.GinBuildTempBytes),
+		"fragmentation":     r.Fragmentation.FragmentationPercent,
+		"avg_leaf_density":  r.Fragmentation.AvgLeafDensity,
+	}
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *CacheCompetitionResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"throughput":        r.ReadThroughput,
+		"table_size_mb":     mb(r.TableSize),
+		"index_size_mb":     mb(r.IndexSize),
+		"cache_heap_share":  float64(r.HeapBuffers) / float64(max(r.SharedBuffers, 1)) * 100,
+		"cache_index_share": float64(r.IndexBuffers) / float64(max(r.SharedBuffers, 1)) * 100,
+		"buffer_hit_ratio":  r.HeapHitRatio * 100,
+		"index_hit_ratio":   r.IndexHitRatio * 100,
+	}
+	value, ok := values[name]
+	return value, ok
+}
+
+// MetricValue reports the largest working set, the point furthest past the cache
+func (r *WorkingSetSweepResult) MetricValue(name string) (float64, bool) {
+	if len(r.Points) == 0 {
+		return 0, false
+	}
+	last := r.Points[len(r.Points)-1]
+	values := map[string]float64{
+		"throughput":       last.ReadThroughput,
+		"buffer_hit_ratio": last.HeapHitRatio * 100,
+		"index_hit_ratio":  last.IndexHitRatio * 100,
+		"table_size_mb":    mb(last.TableSize),
+		"index_size_mb":    mb(last.IndexSize),
+	}
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *ReindexMaintenanceResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"fragmentation_regrowth": r.RegrowthPer100k,
+		"reindex_time":           r.TotalReindexTime.Seconds(),
+		"index_size_mb":          mb(r.FinalIndexSize),
+		"fragmentation":          r.FinalFragmentation,
+	}
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *UpdateChurnResult) MetricValue(name string) (float64, bool) {
+	final := r.Final()
+	values := map[string]float64{
+		"dead_tuples":      float64(final.DeadTuples),
+		"table_bloat":      final.TableBloat,
+		"index_bloat":      final.IndexBloat,
+		"index_growth":     r.IndexGrowth(),
+		"hot_update_ratio": r.HOTUpdateRatio,
+		"table_size_mb":    mb(final.TableSize),
+		"index_size_mb":    mb(final.IndexSize),
+	}
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *CommitOverheadResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"commit_overhead": us(r.PerCommitOverhead),
+		"row_cost":        us(r.PerRowCost),
+		"fit_r2":          r.FitRSquared,
+	}
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *InsertReturningResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"throughput":         r.ReturningThroughput,
+		"returning_overhead": r.ReturningOverhead(),
+	}
+	addLatencies(values, r.ReturningLatency)
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *UpsertPerformanceResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"duration":      r.Duration.Seconds(),
+		"throughput":    r.Throughput,
+		"conflict_rate": r.ConflictRate(),
+		"page_splits":   float64(r.PageSplits),
+		"table_size_mb": mb(r.TableSize),
+		"index_size_mb": mb(r.IndexSize),
+
+		"fragmentation":    r.Fragmentation.FragmentationPercent,
+		"avg_leaf_density": r.Fragmentation.AvgLeafDensity,
+	}
+	addLatencies(values, r.Latency)
+	value, ok := values[name]
+	return value, ok
+}
+
+func (r *RangeScanResult) MetricValue(name string) (float64, bool) {
+	values := map[string]float64{
+		"duration":         r.ScanDuration.Seconds(),
+		"throughput":       r.ScanThroughput,
+		"rows_per_scan":    r.RowsPerScan,
+		"pages_per_scan":   r.PagesPerScan,
+		"buffer_hit_ratio": r.BufferHitRatio * 100,
+
+		"fragmentation":    r.Fragmentation.FragmentationPercent,
+		"avg_leaf_density": r.Fragmentation.AvgLeafDensity,
+	}
+	addLatencies(values, r.Latency)
+	value, ok := values[name]
+	return value, ok
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+)
+
+// MeasureMetrics collects the InnoDB counterparts of the PostgreSQL metrics. The rows
+// live in the primary key's clustered index, so TableSize and IndexSize are both its
+// size; secondary indexes, of which the benchmark creates none, are not counted.
+func (m *MySQLBenchmarker) MeasureMetrics() (*benchmark.BenchmarkResult, error) {
+	result := &benchmark.BenchmarkResult{}
+
+	// Refresh the persistent statistics the page counts below are read from
+	rows, err := m.db.Query(fmt.Sprintf("ANALYZE TABLE %s", m.tableName))
+	if err != nil {
+		return nil, fmt.Errorf("analyze table: %w", err)
+	}
+	rows.Close()
+
+	var pageSize int64
+	if err := m.db.QueryRow("SELECT @@innodb_page_size").Scan(&pageSize); err != nil {
+		return nil, fmt.Errorf("read page size: %w", err)
+	}
+
+	var pages, leafPages int64
+	err = m.db.QueryRow(`
+		SELECT
+			MAX(CASE WHEN stat_name = 'size' THEN stat_value END),
+			MAX(CASE WHEN stat_name = 'n_leaf_pages' THEN stat_value END)
+		FROM mysql.innodb_index_stats
+		WHERE database_name = ? AND table_name = ? AND index_name = 'PRIMARY'
+	`, dbName, m.tableName).Scan(&pages, &leafPages)
+	if err != nil {
+		return nil, fmt.Errorf("read index stats: %w", err)
+	}
+	result.TableSize = pages * pageSize
+	result.IndexSize = pages * pageSize
+	result.Fragmentation.LeafPages = leafPages
+
+	fragmentation, err := m.measureFreeSpace()
+	if err != nil {
+		return nil, fmt.Errorf("measure fragmentation: %w", err)
+	}
+	result.Fragmentation.FragmentationPercent = fragmentation
+
+	density, err := m.measureLeafDensity(pageSize)
+	if err != nil {
+		fmt.Printf("Warning: Could not measure leaf density: %v\n", err)
+	} else {
+		result.Fragmentation.AvgLeafDensity = density
+	}
+
+	splits, err := m.pageSplitCounter()
+	if err != nil {
+		fmt.Printf("Warning: Could not count page splits: %v\n", err)
+	} else {
+		result.PageSplits = int(splits - m.pageSplitsBefore)
+	}
+
+	return result, nil
+}
+
+// measureFreeSpace returns the tablespace's free space (DATA_FREE) as % of its size,
+// the usual InnoDB fragmentation figure. It is not comparable to pgstatindex's
+// out-of-order leaf pages: InnoDB does not expose leaf page order.
+func (m *MySQLBenchmarker) measureFreeSpace() (float64, error) {
+	// The session setting only holds on the connection it was set on
+	conn, err := m.db.Conn(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("open connection: %w", err)
+	}
+	defer conn.Close()
+
+	// information_schema.TABLES otherwise serves cached statistics for up to a day
+	if _, err := conn.ExecContext(context.Background(), "SET SESSION information_schema_stats_expiry = 0"); err != nil {
+		return 0, fmt.Errorf("disable statistics cache: %w", err)
+	}
+
+	var dataLength, indexLength, dataFree int64
+	err = conn.QueryRowContext(context.Background(), `
+		SELECT DATA_LENGTH, INDEX_LENGTH, DATA_FREE
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+	`, dbName, m.tableName).Scan(&dataLength, &indexLength, &dataFree)
+	if err != nil {
+		return 0, err
+	}
+
+	total := dataLength + indexLength + dataFree
+	if total == 0 {
+		return 0, nil
+	}
+	return float64(dataFree) / float64(total) * 100, nil
+}
+
+// measureLeafDensity returns how full the primary key's pages in the buffer pool are,
+// in %. Right after a load the whole index is cached; non-leaf pages, a small fraction,
+// are included.
+func (m *MySQLBenchmarker) measureLeafDensity(pageSize int64) (float64, error) {
+	var pages, dataSize int64
+	err := m.db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(DATA_SIZE), 0)
+		FROM information_schema.INNODB_BUFFER_PAGE
+		WHERE TABLE_NAME = ? AND INDEX_NAME = 'PRIMARY' AND PAGE_TYPE = 'INDEX'
+	`, fmt.Sprintf("`%s`.`%s`", dbName, m.tableName)).Scan(&pages, &dataSize)
+	if err != nil {
+		return 0, err
+	}
+	if pages == 0 {
+		return 0, fmt.Errorf("no pages of %s cached in the buffer pool", m.tableName)
+	}
+	return float64(dataSize) / float64(pages*pageSize) * 100, nil
+}
+
+// pageSplitCounter reads InnoDB's server-wide index_page_splits monitor counter; the
+// benchmark table is the only one written, so its delta is the table's page splits
+func (m *MySQLBenchmarker) pageSplitCounter() (int64, error) {
+	var count int64
+	err := m.db.QueryRow("SELECT COUNT FROM information_schema.INNODB_METRICS WHERE NAME = 'index_page_splits'").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("read index_page_splits counter: %w", err)
+	}
+	return count, nil
+}
+
+// BufferPoolCounters returns InnoDB's cumulative logical page requests and the pages
+// of them read from disk, whose deltas give a run's buffer pool hit ratio
+func (m *MySQLBenchmarker) BufferPoolCounters() (requests, reads int64, err error) {
+	rows, err := m.db.Query("SHOW GLOBAL STATUS WHERE Variable_name IN ('Innodb_buffer_pool_read_requests', 'Innodb_buffer_pool_reads')")
+	if err != nil {
+		return 0, 0, fmt.Errorf("read buffer pool status: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var value int64
+		if err := rows.Scan(&name, &value); err != nil {
+			return 0, 0, fmt.Errorf("scan buffer pool status: %w", err)
+		}
+		switch name {
+		case "Innodb_buffer_pool_read_requests":
+			requests = value
+		case "Innodb_buffer_pool_reads":
+			reads = value
+		}
+	}
+	return requests, reads, rows.Err()
+}
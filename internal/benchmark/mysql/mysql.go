@@ -0,0 +1,232 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const (
+	dbHost = "localhost"
+	dbPort = "3306"
+	dbName = "uuid_benchmark"
+
+	// root rather than the compose file's benchmark user: enabling InnoDB monitor
+	// counters and creating functions with binary logging on need admin privileges
+	dbUser     = "root"
+	dbPassword = "rootpass123"
+
+	// ContainerName is the container started by docker-compose.mysql.yml
+	ContainerName = "uuid-bench-mysql"
+)
+
+// KeyTypes are the key types the MySQL benchmarker can generate, in default run order
+var KeyTypes = []string{"bigserial", "uuidv4", "uuidv7", "uuidv1"}
+
+// Options holds benchmark-wide settings applied to every benchmarker
+type Options struct {
+	Seed int64 // Seed of the generator picking rows to read and update, 0 = unseeded
+
+	PayloadBytes int // Width data values are padded to with 'x', 0 = unpadded
+}
+
+// MySQLBenchmarker runs the workloads against InnoDB, whose primary key is the
+// clustered index holding the rows themselves, so key order decides where each row
+// is physically written
+type MySQLBenchmarker struct {
+	opts      Options
+	db        *sql.DB
+	keyType   string
+	tableName string
+
+	pageSplitsBefore int64 // index_page_splits monitor counter when the table was created
+
+	ids []any // Ids of the loaded table the point operations pick from, set by PrepareIDs
+}
+
+func New(opts Options) *MySQLBenchmarker {
+	return &MySQLBenchmarker{opts: opts}
+}
+
+func connString() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPassword, dbHost, dbPort, dbName)
+}
+
+func (m *MySQLBenchmarker) Connect() error {
+	db, err := sql.Open("mysql", connString())
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return fmt.Errorf("ping database: %w", err)
+	}
+
+	m.db = db
+
+	// Page splits are counted by an InnoDB monitor counter that is off by default
+	if _, err := m.db.Exec("SET GLOBAL innodb_monitor_enable = 'index_page_splits'"); err != nil {
+		return fmt.Errorf("enable index_page_splits counter: %w", err)
+	}
+
+	if err := m.ensureFunctions(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// uuidFunctions generate uuidv4 and uuidv7 server-side, as MySQL only has UUID() (v1).
+// uuidv7 ids within the same millisecond are random, not monotonic.
+var uuidFunctions = map[string]string{
+	"gen_uuidv4": `
+		CREATE FUNCTION gen_uuidv4() RETURNS BINARY(16) NOT DETERMINISTIC NO SQL
+		BEGIN
+			DECLARE b BINARY(16) DEFAULT RANDOM_BYTES(16);
+			RETURN CONCAT(SUBSTR(b, 1, 6),
+				CHAR((ASCII(SUBSTR(b, 7, 1)) & 0x0F) | 0x40), SUBSTR(b, 8, 1),
+				CHAR((ASCII(SUBSTR(b, 9, 1)) & 0x3F) | 0x80), SUBSTR(b, 10, 7));
+		END`,
+	"gen_uuidv7": `
+		CREATE FUNCTION gen_uuidv7() RETURNS BINARY(16) NOT DETERMINISTIC NO SQL
+		BEGIN
+			DECLARE ms BIGINT DEFAULT FLOOR(UNIX_TIMESTAMP(NOW(3)) * 1000);
+			DECLARE b BINARY(10) DEFAULT RANDOM_BYTES(10);
+			RETURN CONCAT(UNHEX(LPAD(HEX(ms), 12, '0')),
+				CHAR((ASCII(SUBSTR(b, 1, 1)) & 0x0F) | 0x70), SUBSTR(b, 2, 1),
+				CHAR((ASCII(SUBSTR(b, 3, 1)) & 0x3F) | 0x80), SUBSTR(b, 4, 7));
+		END`,
+}
+
+// ensureFunctions (re)creates the uuid generator functions
+func (m *MySQLBenchmarker) ensureFunctions() error {
+	for name, createSQL := range uuidFunctions {
+		if _, err := m.db.Exec("DROP FUNCTION IF EXISTS " + name); err != nil {
+			return fmt.Errorf("drop %s function: %w", name, err)
+		}
+		if _, err := m.db.Exec(createSQL); err != nil {
+			return fmt.Errorf("create %s function: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// idExpression returns the SQL expression generating a new id of the key type, or
+// empty for bigserial, whose id comes from AUTO_INCREMENT
+func idExpression(keyType string) string {
+	switch keyType {
+	case "uuidv4":
+		return "gen_uuidv4()"
+	case "uuidv7":
+		return "gen_uuidv7()"
+	case "uuidv1":
+		// Standard byte order: the low timestamp bits lead, so ids are not time-ordered
+		return "UUID_TO_BIN(UUID())"
+	}
+	return ""
+}
+
+func (m *MySQLBenchmarker) CreateTable(keyType string) error {
+	m.keyType = keyType
+	m.tableName = fmt.Sprintf("bench_%s", keyType)
+
+	if _, err := m.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", m.tableName)); err != nil {
+		return fmt.Errorf("drop table: %w", err)
+	}
+
+	var idType string
+	switch keyType {
+	case "bigserial":
+		idType = "BIGINT NOT NULL AUTO_INCREMENT"
+	case "uuidv4", "uuidv7", "uuidv1":
+		idType = "BINARY(16) NOT NULL"
+	default:
+		return fmt.Errorf("key type %s is not supported on MySQL (supported: %v)", keyType, KeyTypes)
+	}
+
+	createSQL := fmt.Sprintf(`
+		CREATE TABLE %s (
+			id %s PRIMARY KEY,
+			data TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		) ENGINE=InnoDB
+	`, m.tableName, idType)
+
+	if _, err := m.db.Exec(createSQL); err != nil {
+		return fmt.Errorf("create table: %w", err)
+	}
+
+	splits, err := m.pageSplitCounter()
+	if err != nil {
+		return err
+	}
+	m.pageSplitsBefore = splits
+
+	return nil
+}
+
+func (m *MySQLBenchmarker) Close() error {
+	if m.db != nil {
+		return m.db.Close()
+	}
+	return nil
+}
+
+// WaitForReady waits until MySQL accepts connections; the first start initializes the
+// data directory, which takes considerably longer than PostgreSQL's
+func WaitForReady() error {
+	timeout := 120 * time.Second
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		db, err := sql.Open("mysql", connString())
+		if err == nil {
+			if err := db.Ping(); err == nil {
+				db.Close()
+				return nil
+			}
+			db.Close()
+		}
+		time.Sleep(time.Second)
+	}
+
+	return fmt.Errorf("timeout waiting for MySQL after %v", timeout)
+}
+
+// ResetForReuse drops every benchmark table of a kept container, so the next key type
+// starts from an empty schema
+func ResetForReuse() error {
+	db, err := sql.Open("mysql", connString())
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT TABLE_NAME FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME LIKE 'bench\\_%'", dbName)
+	if err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan table name: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("list tables: %w", err)
+	}
+
+	for _, table := range tables {
+		if _, err := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", table)); err != nil {
+			return fmt.Errorf("drop table %s: %w", table, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+)
+
+// InsertRecords inserts numRecords rows as multi-row INSERTs of batchSize rows, one
+// autocommitted transaction each, spread across connections. Ids are generated
+// server-side, so the clustered index sees them in generation order.
+func (m *MySQLBenchmarker) InsertRecords(numRecords, batchSize, connections int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	batchSize = max(batchSize, 1)
+	connections = max(connections, 1)
+	conns, err := m.openConns(connections)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConns(conns)
+
+	// Rows are claimed batch by batch, so connections stay busy until the last one
+	var next atomic.Int64
+	claim := func() (first, rows int) {
+		end := int(next.Add(int64(batchSize)))
+		first = end - batchSize
+		if first >= numRecords {
+			return 0, 0
+		}
+		return first, min(batchSize, numRecords-first)
+	}
+
+	var wg sync.WaitGroup
+	latencies := make([][]time.Duration, connections)
+	errs := make([]error, connections)
+
+	start := time.Now()
+	for c := 0; c < connections; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+
+			conn := conns[c]
+			for {
+				first, rows := claim()
+				if rows == 0 {
+					return
+				}
+
+				query, args := m.insertStatement(first, rows)
+				txStart := time.Now()
+				if _, err := conn.ExecContext(context.Background(), query, args...); err != nil {
+					errs[c] = fmt.Errorf("insert batch: %w", err)
+					return
+				}
+				latencies[c] = append(latencies[c], time.Since(txStart))
+			}
+		}(c)
+	}
+	wg.Wait()
+	duration := time.Since(start)
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return concurrentResult(duration, numRecords, latencies), nil
+}
+
+// openConns sizes the pool to n connections, kept open between runs, and opens all of
+// them before a run starts its clock, so connection setup is not timed and every
+// worker has its own connection from the first statement
+func (m *MySQLBenchmarker) openConns(n int) ([]*sql.Conn, error) {
+	m.db.SetMaxOpenConns(n)
+	m.db.SetMaxIdleConns(n)
+
+	conns := make([]*sql.Conn, 0, n)
+	for i := 0; i < n; i++ {
+		conn, err := m.db.Conn(context.Background())
+		if err != nil {
+			closeConns(conns)
+			return nil, fmt.Errorf("open connection: %w", err)
+		}
+		conns = append(conns, conn)
+	}
+	return conns, nil
+}
+
+// closeConns returns the connections to the pool
+func closeConns(conns []*sql.Conn) {
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// insertStatement builds the multi-row INSERT of rows first+1..first+n, writing the
+// same 'test_data_<n>' values, padded to PayloadBytes, as the PostgreSQL scripts
+func (m *MySQLBenchmarker) insertStatement(first, n int) (string, []any) {
+	idExpr := idExpression(m.keyType)
+
+	var query strings.Builder
+	if idExpr == "" {
+		fmt.Fprintf(&query, "INSERT INTO %s (data) VALUES ", m.tableName)
+	} else {
+		fmt.Fprintf(&query, "INSERT INTO %s (id, data) VALUES ", m.tableName)
+	}
+
+	args := make([]any, n)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		if idExpr == "" {
+			query.WriteString("(?)")
+		} else {
+			fmt.Fprintf(&query, "(%s, ?)", idExpr)
+		}
+		args[i] = m.pad(fmt.Sprintf("test_data_%d", first+i+1))
+	}
+
+	return query.String(), args
+}
+
+// pad widens a data value to PayloadBytes, if set; InnoDB does not compress it
+func (m *MySQLBenchmarker) pad(value string) string {
+	if len(value) >= m.opts.PayloadBytes {
+		return value
+	}
+	return value + strings.Repeat("x", m.opts.PayloadBytes-len(value))
+}
+
+// ReadRecords runs numReads primary key point lookups of random existing rows from a
+// single connection
+func (m *MySQLBenchmarker) ReadRecords(numReads int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	query := fmt.Sprintf("SELECT id, data FROM %s WHERE id = ?", m.tableName)
+	return m.runPointOps(numReads, func(conn *sql.Conn, id any, _ int) error {
+		rows, err := conn.QueryContext(context.Background(), query, id)
+		if err != nil {
+			return fmt.Errorf("read row: %w", err)
+		}
+		for rows.Next() {
+		}
+		rows.Close()
+		return rows.Err()
+	})
+}
+
+// UpdateRecords runs numUpdates single-row updates of random existing rows' data from
+// a single connection. InnoDB updates in place where the new value fits, so unlike
+// PostgreSQL no new row version enters the primary key.
+func (m *MySQLBenchmarker) UpdateRecords(numUpdates int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	query := fmt.Sprintf("UPDATE %s SET data = ? WHERE id = ?", m.tableName)
+	return m.runPointOps(numUpdates, func(conn *sql.Conn, id any, n int) error {
+		if _, err := conn.ExecContext(context.Background(), query, m.pad(fmt.Sprintf("updated_%d", n)), id); err != nil {
+			return fmt.Errorf("update row: %w", err)
+		}
+		return nil
+	})
+}
+
+// runPointOps runs op numOps times, each on a random id from PrepareIDs, timing every
+// call as one transaction
+func (m *MySQLBenchmarker) runPointOps(numOps int, op func(conn *sql.Conn, id any, n int) error) (*benchmark.ConcurrentBenchmarkResult, error) {
+	ids := m.ids
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no ids of %s to pick from: PrepareIDs must run after the load", m.tableName)
+	}
+
+	conn, err := m.db.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("open connection: %w", err)
+	}
+	defer conn.Close()
+
+	rng := m.rng()
+	latencies := make([]time.Duration, 0, numOps)
+
+	start := time.Now()
+	for n := 1; n <= numOps; n++ {
+		id := ids[rng.Intn(len(ids))]
+		opStart := time.Now()
+		if err := op(conn, id, n); err != nil {
+			return nil, err
+		}
+		latencies = append(latencies, time.Since(opStart))
+	}
+	duration := time.Since(start)
+
+	return concurrentResult(duration, numOps, [][]time.Duration{latencies}), nil
+}
+
+// PrepareIDs reads every id of the loaded table for ReadRecords and UpdateRecords to
+// sample from; ids are not dense for the uuid key types, so they cannot be picked by
+// number. The read scans the whole clustered index, so it must run before any counters
+// of the measured operations are captured.
+func (m *MySQLBenchmarker) PrepareIDs() error {
+	ids, err := m.loadIDs()
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("table %s is empty", m.tableName)
+	}
+	m.ids = ids
+	return nil
+}
+
+// loadIDs reads every id of the table
+func (m *MySQLBenchmarker) loadIDs() ([]any, error) {
+	rows, err := m.db.Query(fmt.Sprintf("SELECT id FROM %s", m.tableName))
+	if err != nil {
+		return nil, fmt.Errorf("load ids: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []any
+	for rows.Next() {
+		if m.keyType == "bigserial" {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				return nil, fmt.Errorf("scan id: %w", err)
+			}
+			ids = append(ids, id)
+			continue
+		}
+		var id []byte
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("load ids: %w", err)
+	}
+	return ids, nil
+}
+
+// rng returns the generator picking rows, seeded with Options.Seed if set
+func (m *MySQLBenchmarker) rng() *rand.Rand {
+	seed := m.opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return rand.New(rand.NewSource(seed))
+}
+
+// concurrentResult summarizes a run from its wall time and per-connection latencies
+func concurrentResult(duration time.Duration, totalOps int, latencies [][]time.Duration) *benchmark.ConcurrentBenchmarkResult {
+	var all []time.Duration
+	for _, l := range latencies {
+		all = append(all, l...)
+	}
+
+	return &benchmark.ConcurrentBenchmarkResult{
+		Duration:     duration,
+		TotalOps:     totalOps,
+		Throughput:   float64(totalOps) / duration.Seconds(),
+		Latency:      benchmark.CalculatePercentiles(all, benchmark.Percentiles),
+		SuccessCount: len(all),
+	}
+}
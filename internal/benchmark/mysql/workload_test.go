@@ -0,0 +1,113 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// barrierDriver blocks every statement until want statements run at once, recording
+// the most that ever did; a pool too small to reach want times out instead
+type barrierDriver struct {
+	want int
+
+	mu      sync.Mutex
+	active  int
+	peak    int
+	opened  int
+	reached chan struct{} // Closed once want statements ran at once
+	closed  bool
+}
+
+// reset arms the barrier for another run
+func (d *barrierDriver) reset() {
+	d.reached = make(chan struct{})
+	d.closed = false
+}
+
+func (d *barrierDriver) Open(string) (driver.Conn, error) {
+	d.mu.Lock()
+	d.opened++
+	d.mu.Unlock()
+	return &barrierConn{d: d}, nil
+}
+
+type barrierConn struct {
+	d *barrierDriver
+}
+
+func (c *barrierConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *barrierConn) Close() error { return nil }
+
+func (c *barrierConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *barrierConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	d := c.d
+	d.mu.Lock()
+	d.active++
+	d.peak = max(d.peak, d.active)
+	if d.active == d.want && !d.closed {
+		close(d.reached)
+		d.closed = true
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.reached:
+	case <-time.After(2 * time.Second):
+	}
+
+	d.mu.Lock()
+	d.active--
+	d.mu.Unlock()
+	return driver.RowsAffected(1), nil
+}
+
+func TestInsertRecordsRunsEveryConnectionConcurrently(t *testing.T) {
+	for _, connections := range []int{1, 4, 16} {
+		d := &barrierDriver{want: connections}
+		d.reset()
+		db := sql.OpenDB(connector{d})
+		m := &MySQLBenchmarker{db: db, keyType: "bigserial", tableName: "bench_bigserial"}
+
+		result, err := m.InsertRecords(connections*10, 1, connections)
+		if err != nil {
+			t.Fatalf("connections=%d: %v", connections, err)
+		}
+		if d.peak != connections {
+			t.Errorf("connections=%d: peak of %d concurrent statements", connections, d.peak)
+		}
+		if result.SuccessCount != connections*10 {
+			t.Errorf("connections=%d: %d statements succeeded, want %d", connections, result.SuccessCount, connections*10)
+		}
+
+		// A second run reuses the idle connections instead of opening new ones
+		opened := d.opened
+		d.reset()
+		if _, err := m.InsertRecords(connections, 1, connections); err != nil {
+			t.Fatalf("connections=%d, second run: %v", connections, err)
+		}
+		if d.opened != opened {
+			t.Errorf("connections=%d: second run opened %d new connections", connections, d.opened-opened)
+		}
+		db.Close()
+	}
+}
+
+// connector opens barrierDriver connections without registering the driver
+type connector struct {
+	d *barrierDriver
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+
+func (c connector) Driver() driver.Driver { return c.d }
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
+)
+
+// connectScript does no query work, so a run's cost is connection handling alone
+const connectScript = "SELECT 1;"
+
+// ConnectPgbench runs transactions trivial transactions on each of clients connections,
+// reconnecting for every transaction when reconnect is set (pgbench -C), and returns
+// pgbench's parsed output: the initial connection time without reconnect, the average
+// connection time and connections per second (TPS) with it
+func (p *PostgresBenchmarker) ConnectPgbench(clients, transactions int, reconnect bool) (*pgbench.PgbenchResult, error) {
+	containerPath, err := p.copyScript(connectScript, "connect.sql")
+	if err != nil {
+		return nil, fmt.Errorf("copy script to container: %w", err)
+	}
+
+	execCfg := p.execConfig(clients, transactions, containerPath)
+	execCfg.Reconnect = reconnect
+
+	execResult, err := pgbench.Execute(execCfg)
+	if err != nil {
+		return nil, fmt.Errorf("execute pgbench: %w", err)
+	}
+
+	if execResult.ExitCode != 0 {
+		return nil, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
+	}
+
+	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
+	if err != nil {
+		return nil, fmt.Errorf("parse pgbench output: %w", err)
+	}
+	p.checkTransactions(parsed)
+
+	return parsed, nil
+}
@@ -3,20 +3,89 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
 )
 
+// Config is the PostgreSQL server the benchmark connects to and the container pgbench
+// runs in
+type Config struct {
+	Host      string
+	Port      string
+	User      string
+	Password  string
+	DBName    string
+	SSLMode   string // libpq sslmode: disable, require, verify-ca or verify-full
+	Container string // Runs pgbench; its cgroup supplies I/O, CPU and memory stats unless the server is External
+}
+
+// DefaultConfig is the server started by docker/docker-compose.postgres.yml
+var DefaultConfig = Config{
+	Host:      "localhost",
+	Port:      "5432",
+	User:      "benchmark",
+	Password:  "benchmark123",
+	DBName:    "uuid_benchmark",
+	SSLMode:   "disable",
+	Container: "uuid-bench-postgres",
+}
+
+// Connection is the server and pgbench container every benchmarker uses; set from the CLI
+var Connection = DefaultConfig
+
+// External reports whether the server is not the container's published port, so the
+// container's cgroup stats, restarts and resets do not describe or reach it
+func (c Config) External() bool {
+	return c.Host != DefaultConfig.Host || c.Port != DefaultConfig.Port
+}
+
+// connString returns the libpq connection string for the server listening on port
+func (c Config) connString(port string) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		quoteConnValue(c.Host), quoteConnValue(port), quoteConnValue(c.User),
+		quoteConnValue(c.Password), quoteConnValue(c.DBName), quoteConnValue(c.SSLMode))
+}
+
+// quoteConnValue quotes a connection string value, which may hold spaces or quotes
+func quoteConnValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// PgBouncer as published by docker-compose.pgbouncer.yml, and as pgbench reaches it
+// from inside the postgres container
 const (
-	dbHost     = "localhost"
-	dbPort     = "5432"
-	dbUser     = "benchmark"
-	dbPassword = "benchmark123"
-	dbName     = "uuid_benchmark"
+	pgbouncerPort        = "6432"
+	pgbouncerNetworkHost = "pgbouncer"
+	pgbouncerNetworkPort = "5432"
 )
 
+// connString returns the benchmark's own connection string, through PgBouncer when
+// ViaPgBouncer is set
+func (p *PostgresBenchmarker) connString() string {
+	port := Connection.Port
+	if p.opts.ViaPgBouncer {
+		port = pgbouncerPort
+	}
+	return Connection.connString(port)
+}
+
+// pgbenchHost returns the host and port pgbench connects to, an empty host for the
+// container's local socket
+func (p *PostgresBenchmarker) pgbenchHost() (string, string) {
+	if p.opts.ViaPgBouncer {
+		return pgbouncerNetworkHost, pgbouncerNetworkPort
+	}
+	if Connection.External() {
+		return Connection.Host, Connection.Port
+	}
+	return "", ""
+}
+
 func (p *PostgresBenchmarker) Connect() error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", dbHost, dbPort, dbUser, dbPassword, dbName)
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", p.connString())
 	if err != nil {
 		return fmt.Errorf("open database: %w", err)
 	}
@@ -28,26 +97,51 @@ func (p *PostgresBenchmarker) Connect() error {
 
 	p.db = db
 
-	_, err = p.db.Exec("CREATE EXTENSION IF NOT EXISTS pgstattuple")
-	if err != nil {
-		return fmt.Errorf("enable pgstattuple extension: %w", err)
+	extensionsStart := time.Now()
+
+	// pgstattuple: fragmentation, pg_walinspect: page splits, uuid-ossp: uuidv1
+	for _, extension := range []string{"pgstattuple", "pg_walinspect", "uuid-ossp"} {
+		start := time.Now()
+		if _, err := p.db.Exec(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s", pq.QuoteIdentifier(extension))); err != nil {
+			return fmt.Errorf("enable %s extension: %w", extension, err)
+		}
+		if p.opts.IncludeDDLTiming {
+			fmt.Printf("  CREATE EXTENSION %s: %s\n", extension, time.Since(start).Round(time.Microsecond))
+		}
 	}
 
-	_, err = p.db.Exec("CREATE EXTENSION IF NOT EXISTS pg_walinspect")
-	if err != nil {
-		return fmt.Errorf("enable pg_walinspect extension: %w", err)
+	// Only fatal for the uuidv7 key type, which CreateTable checks
+	if err := p.ensureUUIDv7Function(); err != nil {
+		fmt.Printf("Warning: uuidv7 generation unavailable: %v\n", err)
 	}
 
-	_, err = p.db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`)
-	if err != nil {
-		return fmt.Errorf("enable uuid-ossp extension: %w", err)
+	// Only fatal for the uuidv6 key type, which CreateTable checks
+	if err := p.ensureUUIDv6Function(); err != nil {
+		fmt.Printf("Warning: uuidv6 generation unavailable: %v\n", err)
 	}
 
-	_, err = p.db.Exec("CREATE EXTENSION IF NOT EXISTS pgx_ulid")
-	if err != nil {
-		return fmt.Errorf("enable pgx_ulid extension: %w", err)
+	// Only fatal for the uuidv8 key type, which CreateTable checks
+	if err := p.ensureUUIDv8Function(); err != nil {
+		fmt.Printf("Warning: uuidv8 generation unavailable: %v\n", err)
+	}
+
+	// Only fatal for the ksuid key type, which CreateTable checks
+	if err := p.ensureKSUIDFunction(); err != nil {
+		fmt.Printf("Warning: KSUID generation unavailable: %v\n", err)
+	}
+
+	// Only fatal for the snowflake key type, which CreateTable checks
+	if err := p.ensureSnowflakeFunction(); err != nil {
+		fmt.Printf("Warning: Snowflake generation unavailable: %v\n", err)
+	}
+
+	// Only fatal for the ULID key types, which CreateTable checks
+	if err := p.ensureULIDFunctions(); err != nil {
+		fmt.Printf("Warning: ULID generation unavailable: %v\n", err)
 	}
 
+	p.setup.Extensions = time.Since(extensionsStart)
+
 	return nil
 }
 
@@ -55,76 +149,209 @@ func (p *PostgresBenchmarker) CreateTable(keyType string) error {
 	p.keyType = keyType
 	p.tableName = fmt.Sprintf("bench_%s", keyType)
 	p.indexName = fmt.Sprintf("%s_pkey", p.tableName)
+	p.expectedRows = 0
 
+	dropStart := time.Now()
 	dropSQL := fmt.Sprintf("DROP TABLE IF EXISTS %s", p.tableName)
 	_, err := p.db.Exec(dropSQL)
 	if err != nil {
 		return fmt.Errorf("drop table: %w", err)
 	}
+	p.setup.DropTable = time.Since(dropStart)
 
-	var createSQL string
+	var idType string
 	switch keyType {
 	case "bigserial":
-		createSQL = fmt.Sprintf(`
-			CREATE TABLE %s (
-				id BIGSERIAL PRIMARY KEY,
-				data TEXT,
-				created_at TIMESTAMP DEFAULT NOW()
-			)
-		`, p.tableName)
+		idType = "BIGSERIAL"
 	case "uuidv4":
-		createSQL = fmt.Sprintf(`
-			CREATE TABLE %s (
-				id UUID PRIMARY KEY,
-				data TEXT,
-				created_at TIMESTAMP DEFAULT NOW()
-			)
-		`, p.tableName)
+		idType = "UUID"
 	case "uuidv7":
-		createSQL = fmt.Sprintf(`
-			CREATE TABLE %s (
-				id UUID PRIMARY KEY,
-				data TEXT,
-				created_at TIMESTAMP DEFAULT NOW()
-			)
-		`, p.tableName)
-	case "ulid":
-		createSQL = fmt.Sprintf(`
-			CREATE TABLE %s (
-				id ulid PRIMARY KEY,
-				data TEXT,
-				created_at TIMESTAMP DEFAULT NOW()
-			)
-		`, p.tableName)
-	case "ulid_monotonic":
-		createSQL = fmt.Sprintf(`
-			CREATE TABLE %s (
-				id ulid PRIMARY KEY,
-				data TEXT,
-				created_at TIMESTAMP DEFAULT NOW()
-			)
-		`, p.tableName)
+		available, err := p.functionExists("uuidv7")
+		if err != nil {
+			return fmt.Errorf("check uuidv7 function: %w", err)
+		}
+		if !available {
+			return fmt.Errorf("uuidv7() is not available: requires PostgreSQL 18+ or the pg_uuidv7 extension")
+		}
+		idType = "UUID"
+	case "ulid", "ulid_monotonic":
+		var ok bool
+		idType, ok = p.ulidTypes[keyType]
+		if !ok {
+			return fmt.Errorf("%s() is not available: requires a ULID extension such as pgx_ulid", ulidGenerators[keyType])
+		}
 	case "uuidv1":
-		createSQL = fmt.Sprintf(`
-			CREATE TABLE %s (
-				id UUID PRIMARY KEY,
-				data TEXT,
-				created_at TIMESTAMP DEFAULT NOW()
-			)
-		`, p.tableName)
+		idType = "UUID"
+	case "uuidv6":
+		available, err := p.functionExists("gen_uuidv6")
+		if err != nil {
+			return fmt.Errorf("check gen_uuidv6 function: %w", err)
+		}
+		if !available {
+			return fmt.Errorf("gen_uuidv6() is not available: requires the uuid-ossp extension")
+		}
+		idType = "UUID"
+	case "uuidv8":
+		var available bool
+		if err := p.db.QueryRow("SELECT to_regprocedure('gen_uuidv8(integer)') IS NOT NULL").Scan(&available); err != nil {
+			return fmt.Errorf("check gen_uuidv8 function: %w", err)
+		}
+		if !available {
+			return fmt.Errorf("gen_uuidv8(integer) is not available: creating it at connect time failed")
+		}
+		idType = "UUID"
+	case "snowflake":
+		var available bool
+		if err := p.db.QueryRow("SELECT to_regprocedure('gen_snowflake(integer)') IS NOT NULL").Scan(&available); err != nil {
+			return fmt.Errorf("check gen_snowflake function: %w", err)
+		}
+		if !available {
+			return fmt.Errorf("gen_snowflake(integer) is not available: creating it at connect time failed")
+		}
+		idType = "BIGINT"
+	case "ksuid":
+		available, err := p.functionExists("gen_ksuid")
+		if err != nil {
+			return fmt.Errorf("check gen_ksuid function: %w", err)
+		}
+		if !available {
+			return fmt.Errorf("gen_ksuid() is not available: creating it at connect time failed")
+		}
+		// Byte order matches KSUID order only under the C collation; the database
+		// default would sort the base62 digits case-insensitively
+		idType = `TEXT COLLATE "C"`
 	default:
 		return fmt.Errorf("unknown key type: %s", keyType)
 	}
 
+	createSQL := fmt.Sprintf(`
+		CREATE TABLE %s (
+			%s %s PRIMARY KEY%s
+		)
+	`, p.tableName, p.idColumn(), idType, p.opts.DataColumn.Definition())
+
+	createStart := time.Now()
+	defer func() {
+		p.setup.CreateTable = time.Since(createStart)
+	}()
+
 	_, err = p.db.Exec(createSQL)
 	if err != nil {
 		return fmt.Errorf("create table: %w", err)
 	}
 
+	if p.opts.DisableAutovacuum {
+		_, err = p.db.Exec(fmt.Sprintf("ALTER TABLE %s SET (autovacuum_enabled = false, toast.autovacuum_enabled = false)", p.tableName))
+		if err != nil {
+			return fmt.Errorf("disable autovacuum: %w", err)
+		}
+	}
+
+	if err := p.applyTimestampSkew(keyType); err != nil {
+		return err
+	}
+
+	if err := p.applyInsertOrder(keyType); err != nil {
+		return err
+	}
+
+	if err := p.loadReplay(keyType); err != nil {
+		return err
+	}
+
+	if p.jsonbPayload {
+		_, err = p.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN payload JSONB", p.tableName))
+		if err != nil {
+			return fmt.Errorf("add payload column: %w", err)
+		}
+
+		_, err = p.db.Exec(fmt.Sprintf("CREATE INDEX %s ON %s USING GIN (payload)", p.GinIndexName(), p.tableName))
+		if err != nil {
+			return fmt.Errorf("create payload GIN index: %w", err)
+		}
+	}
+
+	for i := 0; i < p.opts.ExtraIndexes; i++ {
+		columns := extraIndexColumns[i%len(extraIndexColumns)]
+		_, err = p.db.Exec(fmt.Sprintf("CREATE INDEX %s_extra_%d ON %s %s", p.tableName, i+1, p.tableName, fmt.Sprintf(columns, p.idColumn())))
+		if err != nil {
+			return fmt.Errorf("create extra index %d: %w", i+1, err)
+		}
+	}
+
 	return nil
 }
 
+// applyTimestampSkew checks that uuidv7 accepts the shift argument the skewed insert
+// scripts pass it (native PostgreSQL 18, not the pg_uuidv7 wrapper)
+func (p *PostgresBenchmarker) applyTimestampSkew(keyType string) error {
+	if !p.opts.TimestampSkew.Enabled() {
+		return nil
+	}
+
+	switch keyType {
+	case "uuidv7":
+		if _, err := p.db.Exec("SELECT uuidv7(interval '-1 millisecond')"); err != nil {
+			return fmt.Errorf("timestamp skew needs uuidv7(shift) from PostgreSQL 18: %w", err)
+		}
+	case "ulid", "ulid_monotonic", "uuidv1", "uuidv6", "uuidv8", "ksuid", "snowflake":
+		fmt.Printf("Warning: timestamp skew is not supported for %s (its generator reads the clock itself), running unskewed\n", keyType)
+	}
+
+	return nil
+}
+
+// applyInsertOrder prepares the timestamp progression of generated ids, restarting the
+// reverse-order sequence so every table starts from the present
+func (p *PostgresBenchmarker) applyInsertOrder(keyType string) error {
+	if p.opts.InsertOrder == "" || p.opts.InsertOrder == "forward" {
+		return nil
+	}
+	if p.opts.TimestampSkew.Enabled() {
+		return fmt.Errorf("insert order %s cannot be combined with timestamp skew", p.opts.InsertOrder)
+	}
+
+	switch keyType {
+	case "uuidv7":
+	case "ulid", "ulid_monotonic", "uuidv1", "uuidv6", "uuidv8", "ksuid", "snowflake":
+		fmt.Printf("Warning: insert order %s is not supported for %s (its generator reads the clock itself), inserting forward\n", p.opts.InsertOrder, keyType)
+		return nil
+	default:
+		return nil // uuidv4 and bigserial ids have no timestamp to order
+	}
+	if _, err := p.db.Exec("SELECT uuidv7(interval '-1 millisecond')"); err != nil {
+		return fmt.Errorf("insert order needs uuidv7(shift) from PostgreSQL 18: %w", err)
+	}
+
+	for _, statement := range []string{
+		"DROP SEQUENCE IF EXISTS " + pgbench.InsertOrderSequence,
+		"CREATE SEQUENCE " + pgbench.InsertOrderSequence,
+	} {
+		if _, err := p.db.Exec(statement); err != nil {
+			return fmt.Errorf("reset insert order sequence: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// extraIndexColumns are the secondary index definitions created by -extra-indexes, used
+// in order and repeated beyond five. Each includes id so every index pays the key
+// type's insert-order cost, as foreign-key and covering indexes on real tables do.
+// %[1]s is the id column.
+var extraIndexColumns = []string{
+	"(%[1]s, created_at)",
+	"(created_at, %[1]s)",
+	"(data, %[1]s)",
+	"(%[1]s DESC)",
+	"(%[1]s) INCLUDE (data)",
+}
+
 func (p *PostgresBenchmarker) Close() error {
+	if err := p.removeScripts(); err != nil {
+		fmt.Printf("Warning: Failed to clean up pgbench scripts: %v\n", err)
+	}
+
 	if p.db != nil {
 		return p.db.Close()
 	}
@@ -132,7 +359,22 @@ func (p *PostgresBenchmarker) Close() error {
 }
 
 func WaitForReady() error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", dbHost, dbPort, dbUser, dbPassword, dbName)
+	return waitForPort(Connection.Port)
+}
+
+// WaitForPgBouncer waits until PostgreSQL is ready and accepts connections through PgBouncer
+func WaitForPgBouncer() error {
+	if err := WaitForReady(); err != nil {
+		return err
+	}
+	if err := waitForPort(pgbouncerPort); err != nil {
+		return fmt.Errorf("pgbouncer: %w", err)
+	}
+	return nil
+}
+
+func waitForPort(port string) error {
+	connStr := Connection.connString(port)
 	timeout := 30 * time.Second
 	deadline := time.Now().Add(timeout)
 
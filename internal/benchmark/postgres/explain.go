@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ReadPlan summarizes how the planner executes the read script's lookup
+type ReadPlan struct {
+	TopNode    string // Node type at the root of the plan (e.g. "Nested Loop", "Index Scan")
+	IndexBased bool   // Whether the primary key index is used for the id lookup
+}
+
+type planNode struct {
+	NodeType  string     `json:"Node Type"`
+	IndexName string     `json:"Index Name"`
+	Plans     []planNode `json:"Plans"`
+}
+
+// ExplainRead runs the read script's SELECT once under EXPLAIN (FORMAT JSON), with its
+// pgbench variables bound to a mid-table row, and reports whether the lookup is indexed
+func (p *PostgresBenchmarker) ExplainRead(keyType string, numRecords int) (*ReadPlan, error) {
+	target := numRecords / 2
+	query := bindScriptVariables(p.layout().GenerateSelectScript(keyType, p.tableName), map[string]int{
+		"id":     target + 1,
+		"offset": target,
+	})
+
+	var raw []byte
+	if err := p.db.QueryRow("EXPLAIN (FORMAT JSON) " + query).Scan(&raw); err != nil {
+		return nil, fmt.Errorf("explain read query: %w", err)
+	}
+
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		return nil, fmt.Errorf("parse explain output: %w", err)
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("explain returned no plan")
+	}
+
+	root := plans[0].Plan
+	return &ReadPlan{
+		TopNode:    root.NodeType,
+		IndexBased: usesIndex(root, p.indexName),
+	}, nil
+}
+
+// usesIndex reports whether any node in the plan scans the given index
+func usesIndex(node planNode, indexName string) bool {
+	switch node.NodeType {
+	case "Index Scan", "Index Only Scan", "Bitmap Index Scan":
+		if node.IndexName == indexName {
+			return true
+		}
+	}
+
+	for _, child := range node.Plans {
+		if usesIndex(child, indexName) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindScriptVariables drops pgbench meta-commands from a script and substitutes its
+// :variables, leaving plain SQL
+func bindScriptVariables(script string, vars map[string]int) string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), `\`) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	query := strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";")
+	for name, value := range vars {
+		query = strings.ReplaceAll(query, ":"+name, strconv.Itoa(value))
+	}
+	return query
+}
@@ -0,0 +1,304 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// functionExists reports whether a zero-argument function with the given name is visible
+func (p *PostgresBenchmarker) functionExists(name string) (bool, error) {
+	var exists bool
+	err := p.db.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM pg_proc
+			WHERE proname = $1 AND pronargs = 0 AND pg_function_is_visible(oid)
+		)
+	`, name).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("query pg_proc for %s: %w", name, err)
+	}
+	return exists, nil
+}
+
+// ensureUUIDv7Function makes uuidv7() available to the pgbench scripts. PostgreSQL 18
+// provides it natively; on older versions the pg_uuidv7 extension names it
+// uuid_generate_v7(), so a uuidv7() wrapper delegating to it is created instead.
+func (p *PostgresBenchmarker) ensureUUIDv7Function() error {
+	native, err := p.functionExists("uuidv7")
+	if err != nil {
+		return err
+	}
+	if native {
+		return nil
+	}
+
+	// Best effort: the extension is only present on images that ship it
+	p.db.Exec("CREATE EXTENSION IF NOT EXISTS pg_uuidv7")
+
+	fallback, err := p.functionExists("uuid_generate_v7")
+	if err != nil {
+		return err
+	}
+	if !fallback {
+		return fmt.Errorf("neither uuidv7() (PostgreSQL 18+) nor uuid_generate_v7() (pg_uuidv7 extension) is available")
+	}
+
+	_, err = p.db.Exec(`
+		CREATE OR REPLACE FUNCTION uuidv7() RETURNS uuid
+		LANGUAGE sql VOLATILE
+		AS 'SELECT uuid_generate_v7()'
+	`)
+	if err != nil {
+		return fmt.Errorf("create uuidv7() wrapper: %w", err)
+	}
+
+	fmt.Println("Native uuidv7() not found, using uuid_generate_v7() from pg_uuidv7")
+	return nil
+}
+
+// ensureUUIDv6Function makes gen_uuidv6() available to the pgbench scripts. UUIDv6
+// (RFC 9562) is UUIDv1 with its timestamp fields reordered most significant first, so
+// the wrapper takes uuid-ossp's uuid_generate_v1() and moves time_hi and time_mid in
+// front of time_low, keeping the clock sequence and node. An existing gen_uuidv6() (e.g.
+// from an extension) is used as is.
+func (p *PostgresBenchmarker) ensureUUIDv6Function() error {
+	exists, err := p.functionExists("gen_uuidv6")
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	source, err := p.functionExists("uuid_generate_v1")
+	if err != nil {
+		return err
+	}
+	if !source {
+		return fmt.Errorf("uuid_generate_v1() (uuid-ossp extension) is not available")
+	}
+
+	// ts is v1's 60-bit timestamp in hex, time_hi | time_mid | time_low
+	_, err = p.db.Exec(`
+		CREATE OR REPLACE FUNCTION gen_uuidv6() RETURNS uuid
+		LANGUAGE sql VOLATILE
+		AS $$
+			SELECT (substr(ts, 1, 12) || '6' || substr(ts, 13, 3) || substr(hex, 17))::uuid
+			FROM (
+				SELECT hex, substr(hex, 14, 3) || substr(hex, 9, 4) || substr(hex, 1, 8) AS ts
+				FROM (SELECT replace(uuid_generate_v1()::text, '-', '') AS hex) v1
+			) fields
+		$$
+	`)
+	if err != nil {
+		return fmt.Errorf("create gen_uuidv6() function: %w", err)
+	}
+
+	return nil
+}
+
+// ensureUUIDv8Function creates gen_uuidv8(time_bits), a UUIDv8 (RFC 9562's custom
+// format) laid out like UUIDv7 but keeping only the high time_bits of the 48-bit
+// millisecond timestamp: gen_random_uuid() supplies the random bits and variant, the
+// truncated timestamp overwrites the first six bytes and the version nibble becomes 8.
+func (p *PostgresBenchmarker) ensureUUIDv8Function() error {
+	_, err := p.db.Exec(`
+		CREATE OR REPLACE FUNCTION gen_uuidv8(time_bits integer) RETURNS uuid
+		LANGUAGE sql VOLATILE
+		AS $$
+			SELECT encode(set_byte(bytes, 6, (get_byte(bytes, 6) & 15) | 128), 'hex')::uuid
+			FROM (
+				SELECT overlay(uuid_send(gen_random_uuid())
+					PLACING substring(int8send(
+						(floor(extract(epoch FROM clock_timestamp()) * 1000)::bigint >> (48 - time_bits)) << (48 - time_bits)
+					) FROM 3)
+					FROM 1 FOR 6) AS bytes
+			) v8
+		$$
+	`)
+	if err != nil {
+		return fmt.Errorf("create gen_uuidv8() function: %w", err)
+	}
+	return nil
+}
+
+// ensureKSUIDFunction creates gen_ksuid(), a KSUID as its canonical 27-character base62
+// text: a 32-bit timestamp in seconds since the KSUID epoch (1400000000) followed by 128
+// random bits. The one-second resolution leaves ids generated within the same second
+// in random order.
+func (p *PostgresBenchmarker) ensureKSUIDFunction() error {
+	_, err := p.db.Exec(`
+		CREATE OR REPLACE FUNCTION gen_ksuid() RETURNS text
+		LANGUAGE plpgsql VOLATILE
+		AS $$
+		DECLARE
+			alphabet constant text := '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+			value numeric := floor(extract(epoch FROM clock_timestamp())) - 1400000000;
+			encoded text := '';
+		BEGIN
+			FOR i IN 1..16 LOOP
+				-- numeric, since numeric + float8 resolves to float8 and keeps only 53 bits
+				value := value * 256 + floor(random() * 256)::numeric;
+			END LOOP;
+			FOR i IN 1..27 LOOP
+				encoded := substr(alphabet, mod(value, 62)::integer + 1, 1) || encoded;
+				value := div(value, 62);
+			END LOOP;
+			RETURN encoded;
+		END
+		$$
+	`)
+	if err != nil {
+		return fmt.Errorf("create gen_ksuid() function: %w", err)
+	}
+
+	// A lossy random part shows as repeated ids or constant low-order characters, which
+	// would collide within the same second; drop the function so ksuid fails up front
+	if err := p.checkKSUIDs(ksuidCheckSamples); err != nil {
+		p.db.Exec("DROP FUNCTION IF EXISTS gen_ksuid()")
+		return err
+	}
+	return nil
+}
+
+// ksuidCheckSamples is the number of ids checkKSUIDs draws; with 62^5 possible
+// five-character suffixes, a chance suffix collision among them is about 1 in 2000
+const ksuidCheckSamples = 1000
+
+// checkKSUIDs generates n ids in one statement, so nearly all share the timestamp, and
+// requires both the ids and their last five characters to be distinct
+func (p *PostgresBenchmarker) checkKSUIDs(n int) error {
+	var ids, suffixes int
+	err := p.db.QueryRow(`
+		SELECT count(DISTINCT id), count(DISTINCT right(id, 5))
+		FROM (SELECT gen_ksuid() AS id FROM generate_series(1, $1)) s
+	`, n).Scan(&ids, &suffixes)
+	if err != nil {
+		return fmt.Errorf("check gen_ksuid(): %w", err)
+	}
+	if ids != n || suffixes != n {
+		return fmt.Errorf("gen_ksuid() is not random enough: %d ids had %d distinct values and %d distinct low-order suffixes", n, ids, suffixes)
+	}
+	return nil
+}
+
+// ensureSnowflakeFunction creates gen_snowflake(machine), a Twitter Snowflake id: 41 bits
+// of milliseconds since the Twitter epoch (1288834974657), a 10-bit machine id and a
+// 12-bit sequence, taken from snowflake_seq so it keeps counting across milliseconds.
+// The insert scripts pass pgbench's client id as the machine, so ids from concurrent
+// clients differ in their machine bits even within the same millisecond.
+func (p *PostgresBenchmarker) ensureSnowflakeFunction() error {
+	statements := []string{
+		"CREATE SEQUENCE IF NOT EXISTS snowflake_seq",
+		`CREATE OR REPLACE FUNCTION gen_snowflake(machine integer) RETURNS bigint
+		LANGUAGE sql VOLATILE
+		AS $$
+			SELECT ((floor(extract(epoch FROM clock_timestamp()) * 1000)::bigint - 1288834974657) << 22)
+				| ((machine % 1024)::bigint << 12)
+				| (nextval('snowflake_seq') % 4096)
+		$$`,
+	}
+	for _, statement := range statements {
+		if _, err := p.db.Exec(statement); err != nil {
+			return fmt.Errorf("create gen_snowflake() function: %w", err)
+		}
+	}
+	return nil
+}
+
+// ulidGenerators is the function the pgbench scripts call for each ULID key type
+var ulidGenerators = map[string]string{
+	"ulid":           "gen_ulid",
+	"ulid_monotonic": "gen_monotonic_ulid",
+}
+
+// ulidAlternatives are the names other ULID extensions give each generator, in the
+// order they are tried when the pgx_ulid name is missing
+var ulidAlternatives = map[string][]string{
+	"gen_ulid":           {"ulid_generate", "gen_random_ulid", "generate_ulid", "ulid"},
+	"gen_monotonic_ulid": {"ulid_generate_monotonic", "gen_ulid_monotonic", "generate_ulid_monotonic"},
+}
+
+// functionResultType returns the return type of a visible zero-argument function, or ""
+// if there is none
+func (p *PostgresBenchmarker) functionResultType(name string) (string, error) {
+	var resultType string
+	err := p.db.QueryRow(`
+		SELECT pg_get_function_result(oid) FROM pg_proc
+		WHERE proname = $1 AND pronargs = 0 AND pg_function_is_visible(oid)
+		LIMIT 1
+	`, name).Scan(&resultType)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("query pg_proc for %s: %w", name, err)
+	}
+	return resultType, nil
+}
+
+// ensureULIDFunctions makes gen_ulid() and gen_monotonic_ulid() available to the pgbench
+// scripts. pgx_ulid provides them under those names; for ULID extensions naming them
+// differently (ulid_generate(), gen_random_ulid(), ...) a wrapper with the pgx_ulid name
+// is created. Each ULID key type's id column takes its generator's return type, so ULIDs
+// stay in the extension's native type (ulid, uuid or text).
+func (p *PostgresBenchmarker) ensureULIDFunctions() error {
+	// The extension is only present on images that ship it; without it an alternative
+	// may still do, so its error is only reported if none does
+	var extensionErr error
+	if _, err := p.db.Exec("CREATE EXTENSION IF NOT EXISTS pgx_ulid"); err != nil {
+		extensionErr = fmt.Errorf("create extension pgx_ulid: %w", err)
+	}
+
+	p.ulidTypes = make(map[string]string)
+	var missing []string
+
+	for _, keyType := range []string{"ulid", "ulid_monotonic"} {
+		name := ulidGenerators[keyType]
+
+		resultType, err := p.functionResultType(name)
+		if err != nil {
+			return err
+		}
+		if resultType != "" {
+			p.ulidTypes[keyType] = resultType
+			continue
+		}
+
+		for _, alternative := range ulidAlternatives[name] {
+			resultType, err = p.functionResultType(alternative)
+			if err != nil {
+				return err
+			}
+			if resultType == "" {
+				continue
+			}
+
+			_, err = p.db.Exec(fmt.Sprintf(`
+				CREATE OR REPLACE FUNCTION %s() RETURNS %s
+				LANGUAGE sql VOLATILE
+				AS 'SELECT %s()'
+			`, name, resultType, alternative))
+			if err != nil {
+				return fmt.Errorf("create %s() wrapper: %w", name, err)
+			}
+
+			fmt.Printf("%s() not found, using %s() returning %s\n", name, alternative, resultType)
+			p.ulidTypes[keyType] = resultType
+			break
+		}
+
+		if _, ok := p.ulidTypes[keyType]; !ok {
+			missing = append(missing, name+"()")
+		}
+	}
+
+	if len(missing) > 0 {
+		if extensionErr != nil {
+			return fmt.Errorf("no ULID extension provides %s: %w", strings.Join(missing, " or "), extensionErr)
+		}
+		return fmt.Errorf("no ULID extension provides %s", strings.Join(missing, " or "))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// InsertRecordsCopy loads numRecords rows in one transaction through COPY ... FROM
+// STDIN, the way bulk ETL loads arrive. Only the data column is streamed: ids come from
+// the key type's generator installed as the column default (BIGSERIAL's own sequence),
+// so every row gets its id server-side exactly as with the pgbench inserts.
+func (p *PostgresBenchmarker) InsertRecordsCopy(keyType string, numRecords int) (time.Duration, error) {
+	column := p.opts.DataColumn
+	if column.None() {
+		return 0, fmt.Errorf("copy insert mode needs a data column (-data-type none leaves none to stream)")
+	}
+
+	if keyType != "bigserial" {
+		expr, ok := p.layout().ColumnDefault(keyType)
+		if !ok {
+			return 0, fmt.Errorf("unknown key type: %s", keyType)
+		}
+		if _, err := p.db.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", p.tableName, p.idColumn(), expr)); err != nil {
+			return 0, fmt.Errorf("set id default: %w", err)
+		}
+	}
+
+	if err := p.checkReplayLength(p.expectedRows + int64(numRecords)); err != nil {
+		return 0, err
+	}
+
+	startLSN, err := p.getCurrentLSN()
+	if err != nil {
+		return 0, fmt.Errorf("capture start LSN: %w", err)
+	}
+	p.startLSN = startLSN
+
+	startTime := time.Now()
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin copy: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(pq.CopyIn(p.tableName, "data"))
+	if err != nil {
+		return 0, fmt.Errorf("start copy: %w", err)
+	}
+
+	for i := 1; i <= numRecords; i++ {
+		if _, err := stmt.Exec(column.CopyValue(i)); err != nil {
+			stmt.Close()
+			return 0, fmt.Errorf("copy row %d: %w", i, err)
+		}
+	}
+	// The final argument-less Exec flushes the buffered rows and ends the COPY
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return 0, fmt.Errorf("finish copy: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return 0, fmt.Errorf("close copy: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit copy: %w", err)
+	}
+
+	duration := time.Since(startTime)
+
+	endLSN, err := p.getCurrentLSN()
+	if err != nil {
+		return 0, fmt.Errorf("capture end LSN: %w", err)
+	}
+	p.endLSN = endLSN
+	p.expectedRows += int64(numRecords)
+
+	return duration, nil
+}
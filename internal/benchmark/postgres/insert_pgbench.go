@@ -8,23 +8,14 @@ import (
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
 )
 
+// InsertRecordsPgbench inserts numRecords rows over one connection and returns the
+// wall-clock time of the pgbench run, as the concurrent path does; pgbench's own TPS,
+// which excludes connection setup, stays available via LastPgbenchResult
 func (p *PostgresBenchmarker) InsertRecordsPgbench(keyType string, numRecords, batchSize int) (time.Duration, error) {
-	startLSN, err := p.getCurrentLSN()
-	if err != nil {
-		return 0, fmt.Errorf("capture start LSN: %w", err)
-	}
-	p.startLSN = startLSN
-
-	startTime := time.Now()
-	var duration time.Duration
-
-	script := pgbench.GenerateInsertScript(keyType, p.tableName)
-	if batchSize > 1 {
-		script = pgbench.GenerateMultipleInserts(keyType, p.tableName, batchSize)
-	}
+	script := p.insertScript(keyType, batchSize)
 
 	scriptName := fmt.Sprintf("insert_%s.sql", keyType)
-	containerPath, err := pgbench.CopyScriptToContainer("uuid-bench-postgres", script, scriptName)
+	containerPath, err := p.copyScript(script, scriptName)
 	if err != nil {
 		return 0, fmt.Errorf("copy script to container: %w", err)
 	}
@@ -37,12 +28,25 @@ func (p *PostgresBenchmarker) InsertRecordsPgbench(keyType string, numRecords, b
 		}
 	}
 
-	execCfg := pgbench.ExecutorConfig{
-		ContainerName: "uuid-bench-postgres",
-		Connections:   1,
-		Transactions:  transactions,
-		ScriptPath:    containerPath,
+	execCfg := p.execConfig(1, transactions, containerPath)
+
+	if err := p.checkReplayLength(p.expectedRows + int64((p.opts.PgbenchWarmup+transactions)*max(batchSize, 1))); err != nil {
+		return 0, err
+	}
+
+	// Warmup inserts are real rows, so they count towards the expected table size
+	if err := p.warmup(execCfg); err != nil {
+		return 0, err
+	}
+	p.expectedRows += int64(p.opts.PgbenchWarmup * max(batchSize, 1))
+
+	startLSN, err := p.getCurrentLSN()
+	if err != nil {
+		return 0, fmt.Errorf("capture start LSN: %w", err)
 	}
+	p.startLSN = startLSN
+
+	startTime := time.Now()
 
 	execResult, err := pgbench.Execute(execCfg)
 	if err != nil {
@@ -53,11 +57,19 @@ func (p *PostgresBenchmarker) InsertRecordsPgbench(keyType string, numRecords, b
 		return 0, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
 	}
 
+	duration := time.Since(startTime)
+
 	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
+	if err == nil {
+		p.reportThrottling(parsed)
+		p.checkTransactions(parsed)
+	}
+	p.lastPgbench = parsed
+
+	// The rounded-up last transaction inserts a full batch, so this may exceed numRecords
+	numRecords, err = p.completeOps(transactions*max(batchSize, 1), batchSize, parsed)
 	if err != nil {
-		duration = time.Since(startTime)
-	} else {
-		duration = parsed.Duration
+		return 0, err
 	}
 
 	endLSN, err := p.getCurrentLSN()
@@ -65,26 +77,16 @@ func (p *PostgresBenchmarker) InsertRecordsPgbench(keyType string, numRecords, b
 		return 0, fmt.Errorf("capture end LSN: %w", err)
 	}
 	p.endLSN = endLSN
+	p.expectedRows += int64(numRecords)
 
 	return duration, nil
 }
 
 func (p *PostgresBenchmarker) InsertRecordsPgbenchConcurrent(keyType string, numRecords, connections, batchSize int) (*benchmark.ConcurrentBenchmarkResult, error) {
-	startLSN, err := p.getCurrentLSN()
-	if err != nil {
-		return nil, fmt.Errorf("capture start LSN: %w", err)
-	}
-	p.startLSN = startLSN
-
-	startTime := time.Now()
-
-	script := pgbench.GenerateInsertScript(keyType, p.tableName)
-	if batchSize > 1 {
-		script = pgbench.GenerateMultipleInserts(keyType, p.tableName, batchSize)
-	}
+	script := p.insertScript(keyType, batchSize)
 
 	scriptName := fmt.Sprintf("insert_%s_concurrent.sql", keyType)
-	containerPath, err := pgbench.CopyScriptToContainer("uuid-bench-postgres", script, scriptName)
+	containerPath, err := p.copyScript(script, scriptName)
 	if err != nil {
 		return nil, fmt.Errorf("copy script to container: %w", err)
 	}
@@ -94,12 +96,25 @@ func (p *PostgresBenchmarker) InsertRecordsPgbenchConcurrent(keyType string, num
 		transactionsPerClient = (numRecords / batchSize) / connections
 	}
 
-	execCfg := pgbench.ExecutorConfig{
-		ContainerName: "uuid-bench-postgres",
-		Connections:   connections,
-		Transactions:  transactionsPerClient,
-		ScriptPath:    containerPath,
+	execCfg := p.execConfig(connections, transactionsPerClient, containerPath)
+	execCfg.LogLatencies = true
+
+	if err := p.checkReplayLength(p.expectedRows + int64((p.opts.PgbenchWarmup+transactionsPerClient)*connections*max(batchSize, 1))); err != nil {
+		return nil, err
+	}
+
+	if err := p.warmup(execCfg); err != nil {
+		return nil, err
 	}
+	p.expectedRows += int64(p.opts.PgbenchWarmup * connections * max(batchSize, 1))
+
+	startLSN, err := p.getCurrentLSN()
+	if err != nil {
+		return nil, fmt.Errorf("capture start LSN: %w", err)
+	}
+	p.startLSN = startLSN
+
+	startTime := time.Now()
 
 	execResult, err := pgbench.Execute(execCfg)
 	if err != nil {
@@ -110,27 +125,66 @@ func (p *PostgresBenchmarker) InsertRecordsPgbenchConcurrent(keyType string, num
 		return nil, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
 	}
 
+	duration := time.Since(startTime)
+
 	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
 	if err != nil {
 		return nil, fmt.Errorf("parse pgbench output: %w", err)
 	}
+	p.reportThrottling(parsed)
+	p.checkTransactions(parsed)
+	p.lastPgbench = parsed
+
+	// The truncated per-client transaction count may leave this short of numRecords
+	transactions := transactionsPerClient * connections
+	numRecords, err = p.completeOps(transactions*max(batchSize, 1), batchSize, parsed)
+	if err != nil {
+		return nil, err
+	}
 
 	endLSN, err := p.getCurrentLSN()
 	if err != nil {
 		return nil, fmt.Errorf("capture end LSN: %w", err)
 	}
 	p.endLSN = endLSN
+	p.expectedRows += int64(numRecords)
 
-	duration := time.Since(startTime)
+	// A time-bound run has no transaction count to fall short of
+	var errorCount int
+	if !p.timeBound() {
+		errorCount = transactions - parsed.Transactions
+	}
 
 	return &benchmark.ConcurrentBenchmarkResult{
 		Duration:     duration,
 		TotalOps:     numRecords,
 		Throughput:   parsed.TPS,
-		LatencyP50:   parsed.P50,
-		LatencyP95:   parsed.P95,
-		LatencyP99:   parsed.P99,
+		Latency:      latencyPercentiles(execResult, parsed),
 		SuccessCount: parsed.Transactions,
-		ErrorCount:   numRecords - parsed.Transactions,
+		ErrorCount:   errorCount,
+
+		InitialConnectionTime:    parsed.InitialConnectionTime,
+		ThroughputIncludingSetup: parsed.TPSIncludingSetup,
 	}, nil
 }
+
+// insertScript generates the pgbench insert script for the table's current layout. In
+// batch insert mode a transaction is one multi-row INSERT instead of batchSize single-row
+// ones, with or without RETURNING; JSONB payload inserts always use the latter.
+func (p *PostgresBenchmarker) insertScript(keyType string, batchSize int) string {
+	if p.opts.InsertMode == "batch" && !p.jsonbPayload {
+		if p.returning {
+			return p.layout().GenerateMultiRowInsertReturningScript(keyType, p.tableName, max(batchSize, 1))
+		}
+		return p.layout().GenerateMultiRowInsertScript(keyType, p.tableName, max(batchSize, 1))
+	}
+
+	statement := p.layout().GenerateInsertScript(keyType, p.tableName)
+	switch {
+	case p.jsonbPayload:
+		statement = p.layout().GenerateJSONBInsertScript(keyType, p.tableName)
+	case p.returning:
+		statement = p.layout().GenerateInsertReturningScript(keyType, p.tableName)
+	}
+	return pgbench.GenerateBatch(statement, batchSize)
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
+)
+
+// lookupIDsTable names the table of ids sampled for primary key lookups; the bench_
+// prefix makes ResetSchema drop it with the benchmark table
+func (p *PostgresBenchmarker) lookupIDsTable() string {
+	return p.tableName + "_lookup_ids"
+}
+
+// PrepareLookups samples up to n ids of the loaded table for LookupRecordsPgbench. It
+// scans the whole table, so call it before resetting the statistics the lookups are
+// measured by.
+func (p *PostgresBenchmarker) PrepareLookups(n int) error {
+	count, err := p.sampleIDs(p.lookupIDsTable(), n)
+	if err != nil {
+		return fmt.Errorf("prepare lookups: %w", err)
+	}
+	p.lookupIDs = count
+	return nil
+}
+
+// LookupRecordsPgbench runs numReads primary key lookups over one connection, each of a
+// random id sampled by PrepareLookups, returning the wall-clock duration alongside
+// pgbench's TPS and latency percentiles
+func (p *PostgresBenchmarker) LookupRecordsPgbench(numReads int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	if p.lookupIDs == 0 {
+		return nil, fmt.Errorf("no ids of %s to look up: PrepareLookups must run after the load", p.tableName)
+	}
+
+	script := p.layout().GenerateLookupScript(p.tableName, p.lookupIDsTable())
+
+	scriptWithVars := fmt.Sprintf("\\set num_ids %d\n%s", p.lookupIDs, script)
+
+	scriptName := fmt.Sprintf("lookup_%s.sql", p.keyType)
+	containerPath, err := p.copyScript(scriptWithVars, scriptName)
+	if err != nil {
+		return nil, fmt.Errorf("copy script to container: %w", err)
+	}
+
+	execCfg := p.execConfig(1, numReads, containerPath)
+	execCfg.LogLatencies = true
+
+	if err := p.warmup(execCfg); err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+
+	execResult, err := pgbench.Execute(execCfg)
+	if err != nil {
+		return nil, fmt.Errorf("execute pgbench: %w", err)
+	}
+
+	if execResult.ExitCode != 0 {
+		return nil, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
+	}
+
+	duration := time.Since(startTime)
+
+	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
+	if err != nil {
+		return nil, fmt.Errorf("parse pgbench output: %w", err)
+	}
+	p.reportThrottling(parsed)
+	p.checkTransactions(parsed)
+	p.lastPgbench = parsed
+
+	numReads, err = p.completeOps(numReads, 1, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &benchmark.ConcurrentBenchmarkResult{
+		Duration:     duration,
+		TotalOps:     numReads,
+		Throughput:   parsed.TPS,
+		Latency:      latencyPercentiles(execResult, parsed),
+		SuccessCount: parsed.Transactions,
+		ErrorCount:   numReads - parsed.Transactions,
+
+		InitialConnectionTime:    parsed.InitialConnectionTime,
+		ThroughputIncludingSetup: parsed.TPSIncludingSetup,
+	}, nil
+}
+
+// LoadedRows returns the rows the insert phases loaded, known without scanning the table
+func (p *PostgresBenchmarker) LoadedRows() int64 {
+	return p.expectedRows
+}
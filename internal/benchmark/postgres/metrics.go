@@ -1,7 +1,10 @@
 package postgres
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/moguls753/uuid-benchmark/internal/benchmark"
 )
@@ -24,25 +27,345 @@ func (p *PostgresBenchmarker) MeasureMetrics() (*benchmark.BenchmarkResult, erro
 
 	pageSplits, err := p.countPageSplits()
 	if err != nil {
+		if p.opts.Strict {
+			return nil, fmt.Errorf("count page splits: %w", err)
+		}
 		fmt.Printf("Warning: Could not count page splits: %v\n", err)
 		result.PageSplits = 0
 	} else {
 		result.PageSplits = pageSplits
 	}
 
-	bufferHitRatio, indexHitRatio, err := p.measureBufferHitRatios()
+	walBytes, fpiBytes, err := p.measureWAL()
 	if err != nil {
+		if p.opts.Strict {
+			return nil, fmt.Errorf("measure WAL volume: %w", err)
+		}
+		fmt.Printf("Warning: Could not measure WAL volume: %v\n", err)
+	} else {
+		result.WALBytes = walBytes
+		result.FPIBytes = fpiBytes
+	}
+
+	pagesDirtied, err := p.countIndexPagesDirtied()
+	if err != nil {
+		if p.opts.Strict {
+			return nil, fmt.Errorf("count dirtied index pages: %w", err)
+		}
+		fmt.Printf("Warning: Could not count dirtied index pages: %v\n", err)
+	} else {
+		result.IndexPagesDirtied = pagesDirtied
+	}
+
+	bufferHitRatio, indexHitRatio, blocksRead, err := p.measureBufferHitRatios()
+	if err != nil {
+		if p.opts.Strict {
+			return nil, fmt.Errorf("measure buffer hit ratios: %w", err)
+		}
 		fmt.Printf("Warning: Could not measure buffer hit ratios: %v\n", err)
 		result.BufferHitRatio = 0
 		result.IndexBufferHitRatio = 0
 	} else {
 		result.BufferHitRatio = bufferHitRatio
 		result.IndexBufferHitRatio = indexHitRatio
+		result.BlocksRead = blocksRead
+	}
+
+	// Full heap scan, so gated and taken after the buffer statistics above
+	if p.opts.MeasureBloat {
+		tuples, err := p.MeasureTupleStats()
+		if err != nil {
+			return nil, err
+		}
+		result.Tuples = tuples
+	}
+
+	// Checked last: the full count would otherwise pollute the buffer statistics above
+	if err := p.checkRowCount(); err != nil {
+		return nil, err
+	}
+
+	if p.opts.VerifyData {
+		verification, err := p.VerifyData()
+		if err != nil {
+			fmt.Printf("Warning: Could not verify data: %v\n", err)
+		} else {
+			verification.Report(p.keyType)
+		}
 	}
 
 	return result, nil
 }
 
+// minRowsFraction is the share of expected rows that must be present before metrics
+// are trusted; fewer rows means an insert phase silently under-delivered
+const minRowsFraction = 0.9
+
+// checkRowCount guards against reporting fragmentation for an empty or under-loaded
+// table, which would otherwise look like an excellent (near 0%) result
+func (p *PostgresBenchmarker) checkRowCount() error {
+	if p.expectedRows == 0 {
+		return nil
+	}
+
+	rows, err := p.countRows()
+	if err != nil {
+		return err
+	}
+
+	if float64(rows) < float64(p.expectedRows)*minRowsFraction {
+		return fmt.Errorf("table %s has %d rows, expected about %d: insert phase did not complete, metrics would be misleading", p.tableName, rows, p.expectedRows)
+	}
+	if rows < p.expectedRows {
+		fmt.Printf("Warning: table %s has %d rows, expected %d\n", p.tableName, rows, p.expectedRows)
+	}
+
+	return nil
+}
+
+func (p *PostgresBenchmarker) countRows() (int64, error) {
+	var rows int64
+	if err := p.db.QueryRow(fmt.Sprintf("SELECT count(*) FROM %s", p.tableName)).Scan(&rows); err != nil {
+		return 0, fmt.Errorf("count rows: %w", err)
+	}
+	return rows, nil
+}
+
+// targetRecords returns the row count to use as :num_records in read/update scripts:
+// the rows the insert phases loaded, which MeasureMetrics checked against the table.
+// Counting here instead would scan the whole heap after the stats reset and the I/O
+// snapshots, charging that scan to the measured reads and warming the cache for them.
+func (p *PostgresBenchmarker) targetRecords(requested int) (int, error) {
+	if p.expectedRows == 0 {
+		return 0, fmt.Errorf("table %s is empty, nothing to read or update", p.tableName)
+	}
+	if p.expectedRows != int64(requested) {
+		fmt.Printf("Warning: table %s was loaded with %d rows, requested %d; using the loaded count for :num_records\n", p.tableName, p.expectedRows, requested)
+	}
+
+	return int(p.expectedRows), nil
+}
+
+// BlockIO is a snapshot of the table's heap and index block counters from
+// pg_statio_user_tables
+type BlockIO struct {
+	HeapRead  int64
+	HeapHit   int64
+	IndexRead int64
+	IndexHit  int64
+}
+
+// Since returns the block reads and hits between before and b
+func (b *BlockIO) Since(before *BlockIO) *BlockIO {
+	return &BlockIO{
+		HeapRead:  b.HeapRead - before.HeapRead,
+		HeapHit:   b.HeapHit - before.HeapHit,
+		IndexRead: b.IndexRead - before.IndexRead,
+		IndexHit:  b.IndexHit - before.IndexHit,
+	}
+}
+
+// BlocksRead returns the heap and index blocks read from outside shared_buffers
+func (b *BlockIO) BlocksRead() int64 {
+	return b.HeapRead + b.IndexRead
+}
+
+// IndexHitRatio returns the share of index block accesses served from shared_buffers
+func (b *BlockIO) IndexHitRatio() float64 {
+	if b.IndexRead+b.IndexHit == 0 {
+		return 0
+	}
+	return float64(b.IndexHit) / float64(b.IndexRead+b.IndexHit)
+}
+
+// blockIO reads the table's block counters; pgbench sessions flush theirs when they exit
+func (p *PostgresBenchmarker) blockIO() (*BlockIO, error) {
+	io := &BlockIO{}
+	err := p.db.QueryRow(`
+		SELECT COALESCE(heap_blks_read, 0), COALESCE(heap_blks_hit, 0),
+			COALESCE(idx_blks_read, 0), COALESCE(idx_blks_hit, 0)
+		FROM pg_statio_user_tables
+		WHERE relid = $1::regclass
+	`, p.tableName).Scan(&io.HeapRead, &io.HeapHit, &io.IndexRead, &io.IndexHit)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("pg_statio_user_tables has no row for table %s", p.tableName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query block counters: %w", err)
+	}
+	return io, nil
+}
+
+// tableWriteCounts returns the rows inserted into and updated in the benchmark table
+// since the last stats reset; pgbench sessions flush their counters when they exit
+func (p *PostgresBenchmarker) tableWriteCounts() (inserted, updated int64, err error) {
+	err = p.db.QueryRow(`
+		SELECT n_tup_ins, n_tup_upd
+		FROM pg_stat_user_tables
+		WHERE relname = $1
+	`, p.tableName).Scan(&inserted, &updated)
+	if err != nil {
+		return 0, 0, fmt.Errorf("query table write counts: %w", err)
+	}
+	return inserted, updated, nil
+}
+
+// BufferSplit is the share of shared_buffers occupied by the benchmark table's heap and
+// its primary key index, from pg_buffercache
+type BufferSplit struct {
+	SharedBuffers int64
+	HeapBuffers   int64
+	IndexBuffers  int64
+}
+
+// MeasureBufferSplit counts the shared buffers currently caching heap pages of the
+// table and pages of its primary key index
+func (p *PostgresBenchmarker) MeasureBufferSplit() (*BufferSplit, error) {
+	if _, err := p.db.Exec("CREATE EXTENSION IF NOT EXISTS pg_buffercache"); err != nil {
+		return nil, fmt.Errorf("enable pg_buffercache extension: %w", err)
+	}
+
+	split := &BufferSplit{}
+	err := p.db.QueryRow(`
+		SELECT
+			(SELECT setting::bigint FROM pg_settings WHERE name = 'shared_buffers'),
+			count(*) FILTER (WHERE relfilenode = pg_relation_filenode($1::regclass)),
+			count(*) FILTER (WHERE relfilenode = pg_relation_filenode($2::regclass))
+		FROM pg_buffercache
+		WHERE reldatabase = (SELECT oid FROM pg_database WHERE datname = current_database())
+	`, p.tableName, p.PKIndexName()).Scan(&split.SharedBuffers, &split.HeapBuffers, &split.IndexBuffers)
+	if err != nil {
+		return nil, fmt.Errorf("query buffer cache contents: %w", err)
+	}
+
+	return split, nil
+}
+
+// Bloat is a snapshot of dead row versions and unused space in the benchmark table and
+// its primary key index
+type Bloat struct {
+	DeadTuples int64   // pg_stat_user_tables.n_dead_tup
+	Updates    int64   // Rows updated since the table was created
+	HOTUpdates int64   // Updates that needed no new index entry (heap-only tuples)
+	TableBloat float64 // Dead tuples plus free space, % of the table (pgstattuple)
+	IndexBloat float64 // Free space in primary key leaf pages, 100 - avg_leaf_density
+}
+
+// MeasureBloat samples dead tuples and free space, scanning the whole table with
+// pgstattuple; pgbench sessions flush their counters when they exit
+func (p *PostgresBenchmarker) MeasureBloat() (*Bloat, error) {
+	bloat := &Bloat{}
+	err := p.db.QueryRow(`
+		SELECT n_dead_tup, n_tup_upd, n_tup_hot_upd
+		FROM pg_stat_user_tables
+		WHERE relid = $1::regclass
+	`, p.tableName).Scan(&bloat.DeadTuples, &bloat.Updates, &bloat.HOTUpdates)
+	if err != nil {
+		return nil, fmt.Errorf("query dead tuples: %w", err)
+	}
+
+	err = p.db.QueryRow("SELECT dead_tuple_percent + free_percent FROM pgstattuple($1)", p.tableName).Scan(&bloat.TableBloat)
+	if err != nil {
+		return nil, fmt.Errorf("query table bloat: %w", err)
+	}
+
+	fragStats, err := p.measureIndexFragmentation()
+	if err != nil {
+		return nil, fmt.Errorf("query index bloat: %w", err)
+	}
+	bloat.IndexBloat = 100 - fragStats.AvgLeafDensity
+
+	return bloat, nil
+}
+
+// MeasureTupleStats returns pgstattuple's live, dead and free space breakdown of the
+// table. It reads every heap page, which is slow on large tables.
+func (p *PostgresBenchmarker) MeasureTupleStats() (*benchmark.TupleStats, error) {
+	stats := &benchmark.TupleStats{}
+	err := p.db.QueryRow("SELECT tuple_count, dead_tuple_percent, free_percent FROM pgstattuple($1)", p.tableName).
+		Scan(&stats.TupleCount, &stats.DeadTuplePercent, &stats.FreePercent)
+	if err != nil {
+		return nil, fmt.Errorf("query tuple stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Vacuum runs a plain VACUUM on the table and returns how long it took. Dead tuples
+// become free space, but neither heap nor index pages are returned to the filesystem.
+func (p *PostgresBenchmarker) Vacuum() (time.Duration, error) {
+	start := time.Now()
+	if _, err := p.db.Exec(fmt.Sprintf("VACUUM %s", p.tableName)); err != nil {
+		return 0, fmt.Errorf("vacuum %s: %w", p.tableName, err)
+	}
+	return time.Since(start), nil
+}
+
+// HeapIndexHitRatios returns the heap and index block hit ratios of the table since
+// the last stats reset
+func (p *PostgresBenchmarker) HeapIndexHitRatios() (heap, index float64, err error) {
+	err = p.db.QueryRow(`
+		SELECT
+			COALESCE(heap_blks_hit::float / NULLIF(heap_blks_hit + heap_blks_read, 0), 0),
+			COALESCE(idx_blks_hit::float / NULLIF(idx_blks_hit + idx_blks_read, 0), 0)
+		FROM pg_statio_user_tables
+		WHERE relname = $1
+	`, p.tableName).Scan(&heap, &index)
+	if err != nil {
+		return 0, 0, fmt.Errorf("query heap/index hit ratios: %w", err)
+	}
+	return heap, index, nil
+}
+
+// SharedBuffersBytes returns the size of the buffer pool in bytes
+func (p *PostgresBenchmarker) SharedBuffersBytes() (int64, error) {
+	var size int64
+	err := p.db.QueryRow(`
+		SELECT setting::bigint * current_setting('block_size')::bigint
+		FROM pg_settings WHERE name = 'shared_buffers'
+	`).Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("query shared_buffers: %w", err)
+	}
+	return size, nil
+}
+
+// DiskUsage returns the current table and total index size in bytes
+func (p *PostgresBenchmarker) DiskUsage() (tableSize, indexSize int64, err error) {
+	return p.measureDiskUsage()
+}
+
+// TempUsage is a snapshot of the database's temp file counters from pg_stat_database
+type TempUsage struct {
+	Files int64
+	Bytes int64
+}
+
+// Since returns the temp files and bytes written between before and t
+func (t *TempUsage) Since(before *TempUsage) (files, bytes int64) {
+	return t.Files - before.Files, t.Bytes - before.Bytes
+}
+
+// TempUsage reads pg_stat_database.temp_files/temp_bytes for the benchmark database.
+// Sorts and index builds that exceed work_mem/maintenance_work_mem spill to temp files.
+func (p *PostgresBenchmarker) TempUsage() (*TempUsage, error) {
+	// Flush this session's pending stats (e.g. from REINDEX) before reading
+	if _, err := p.db.Exec("SELECT pg_stat_force_next_flush()"); err != nil {
+		return nil, fmt.Errorf("flush stats: %w", err)
+	}
+
+	usage := &TempUsage{}
+	err := p.db.QueryRow(`
+		SELECT temp_files, temp_bytes
+		FROM pg_stat_database
+		WHERE datname = current_database()
+	`).Scan(&usage.Files, &usage.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("query temp file stats: %w", err)
+	}
+
+	return usage, nil
+}
+
 func (p *PostgresBenchmarker) measureDiskUsage() (tableSize, indexSize int64, err error) {
 	err = p.db.QueryRow("SELECT pg_table_size($1)", p.tableName).Scan(&tableSize)
 	if err != nil {
@@ -112,32 +435,92 @@ func (p *PostgresBenchmarker) countPageSplits() (int, error) {
 	return count, nil
 }
 
-func (p *PostgresBenchmarker) measureBufferHitRatios() (float64, float64, error) {
+// measureWAL returns the WAL bytes written over the measured insert range and the
+// full-page image bytes among them. Random keys dirty more distinct pages between
+// checkpoints, so they log more full-page images; wal_compression shrinks those.
+func (p *PostgresBenchmarker) measureWAL() (walBytes, fpiBytes int64, err error) {
+	if p.startLSN == "" || p.endLSN == "" {
+		return 0, 0, fmt.Errorf("LSN range not captured (startLSN=%q, endLSN=%q)", p.startLSN, p.endLSN)
+	}
+
+	err = p.db.QueryRow(`
+		SELECT
+			pg_wal_lsn_diff($2::pg_lsn, $1::pg_lsn)::bigint,
+			(SELECT COALESCE(SUM(fpi_size), 0)::bigint FROM pg_get_wal_stats($1::pg_lsn, $2::pg_lsn))
+	`, p.startLSN, p.endLSN).Scan(&walBytes, &fpiBytes)
+	if err != nil {
+		return 0, 0, fmt.Errorf("query WAL volume (LSN %s to %s): %w", p.startLSN, p.endLSN, err)
+	}
+
+	return walBytes, fpiBytes, nil
+}
+
+// countIndexPagesDirtied counts the distinct B-tree pages of the table's indexes that WAL
+// records modified over the measured insert range (pg_get_wal_block_info, PostgreSQL 16+).
+// Every page change is WAL-logged, so this is exactly the set of index pages dirtied.
+func (p *PostgresBenchmarker) countIndexPagesDirtied() (int64, error) {
+	if p.startLSN == "" || p.endLSN == "" {
+		return 0, fmt.Errorf("LSN range not captured (startLSN=%q, endLSN=%q)", p.startLSN, p.endLSN)
+	}
+
+	var count int64
+	err := p.db.QueryRow(`
+		SELECT COUNT(DISTINCT (relfilenode, relblocknumber))
+		FROM pg_get_wal_block_info($1::pg_lsn, $2::pg_lsn)
+		WHERE resource_manager = 'Btree'
+			AND relforknumber = 0
+			AND relfilenode IN (
+				SELECT pg_relation_filenode(indexrelid) FROM pg_index WHERE indrelid = $3::regclass
+			)
+	`, p.startLSN, p.endLSN, p.tableName).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("query WAL block references (LSN %s to %s): %w", p.startLSN, p.endLSN, err)
+	}
+
+	return count, nil
+}
+
+// measureBufferHitRatios reads the hit ratios of the database and table this
+// benchmarker is connected to, resolved by the server rather than by name literals
+func (p *PostgresBenchmarker) measureBufferHitRatios() (float64, float64, int64, error) {
 	var bufferHitRatio float64
 	bufferQuery := `
 		SELECT
 			COALESCE(blks_hit::float / NULLIF(blks_hit + blks_read, 0), 0) AS cache_hit_ratio
 		FROM pg_stat_database
-		WHERE datname = 'uuid_benchmark'
+		WHERE datname = current_database()
 	`
 	err := p.db.QueryRow(bufferQuery).Scan(&bufferHitRatio)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, 0, fmt.Errorf("pg_stat_database has no row for the connected database")
+	}
 	if err != nil {
-		return 0, 0, fmt.Errorf("query buffer hit ratio: %w", err)
+		return 0, 0, 0, fmt.Errorf("query buffer hit ratio: %w", err)
 	}
 
 	var indexHitRatio float64
+	var blocksRead int64
 	indexQuery := `
 		SELECT
-			COALESCE(idx_blks_hit::float / NULLIF(idx_blks_hit + idx_blks_read, 0), 0) AS index_hit_ratio
+			COALESCE(idx_blks_hit::float / NULLIF(idx_blks_hit + idx_blks_read, 0), 0) AS index_hit_ratio,
+			COALESCE(idx_blks_read, 0) + COALESCE(heap_blks_read, 0) AS blocks_read
 		FROM pg_statio_user_tables
-		WHERE relname = $1
+		WHERE relid = $1::regclass
 	`
-	err = p.db.QueryRow(indexQuery, p.tableName).Scan(&indexHitRatio)
+	err = p.db.QueryRow(indexQuery, p.tableName).Scan(&indexHitRatio, &blocksRead)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("pg_statio_user_tables has no row for table %s", p.tableName)
+	}
 	if err != nil {
+		if p.opts.Strict {
+			return 0, 0, 0, fmt.Errorf("query index hit ratio: %w", err)
+		}
+		fmt.Printf("Warning: Could not measure index hit ratio: %v\n", err)
 		indexHitRatio = 0
+		blocksRead = 0
 	}
 
-	return bufferHitRatio, indexHitRatio, nil
+	return bufferHitRatio, indexHitRatio, blocksRead, nil
 }
 
 func (p *PostgresBenchmarker) ResetStats() error {
@@ -147,3 +530,70 @@ func (p *PostgresBenchmarker) ResetStats() error {
 	}
 	return nil
 }
+
+// Checkpoint flushes all dirty buffers, so a following phase does not pay for writing
+// back pages dirtied before it
+func (p *PostgresBenchmarker) Checkpoint() error {
+	if _, err := p.db.Exec("CHECKPOINT"); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	return nil
+}
+
+// MeasureCorrelation analyzes the table and returns pg_stats.correlation for the id
+// column: the statistical correlation between physical row order and id order (1.0 =
+// perfectly clustered, 0 = random)
+func (p *PostgresBenchmarker) MeasureCorrelation() (float64, error) {
+	if _, err := p.db.Exec(fmt.Sprintf("ANALYZE %s", p.tableName)); err != nil {
+		return 0, fmt.Errorf("analyze table: %w", err)
+	}
+
+	var correlation sql.NullFloat64
+	err := p.db.QueryRow(`
+		SELECT correlation
+		FROM pg_stats
+		WHERE tablename = $1 AND attname = $2
+	`, p.tableName, p.idColumn()).Scan(&correlation)
+	if err != nil {
+		return 0, fmt.Errorf("query id correlation: %w", err)
+	}
+	if !correlation.Valid {
+		return 0, fmt.Errorf("no correlation statistic for %s.%s", p.tableName, p.idColumn())
+	}
+
+	return correlation.Float64, nil
+}
+
+// MeasureIndexSize returns the on-disk size of a single index
+func (p *PostgresBenchmarker) MeasureIndexSize(indexName string) (int64, error) {
+	var size int64
+	err := p.db.QueryRow("SELECT pg_relation_size($1::regclass)", indexName).Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("query size of %s: %w", indexName, err)
+	}
+	return size, nil
+}
+
+// IndexFragmentation returns pgstatindex statistics for the primary key index
+func (p *PostgresBenchmarker) IndexFragmentation() (benchmark.IndexFragmentationStats, error) {
+	return p.measureIndexFragmentation()
+}
+
+// ReindexConcurrently rebuilds an index with REINDEX INDEX CONCURRENTLY, which does not
+// block writes, and returns how long the rebuild took
+func (p *PostgresBenchmarker) ReindexConcurrently(indexName string) (time.Duration, error) {
+	start := time.Now()
+	if _, err := p.db.Exec(fmt.Sprintf("REINDEX INDEX CONCURRENTLY %s", indexName)); err != nil {
+		return 0, fmt.Errorf("reindex concurrently %s: %w", indexName, err)
+	}
+	return time.Since(start), nil
+}
+
+// ReindexDuration rebuilds an index and returns how long the rebuild took
+func (p *PostgresBenchmarker) ReindexDuration(indexName string) (time.Duration, error) {
+	start := time.Now()
+	if _, err := p.db.Exec(fmt.Sprintf("REINDEX INDEX %s", indexName)); err != nil {
+		return 0, fmt.Errorf("reindex %s: %w", indexName, err)
+	}
+	return time.Since(start), nil
+}
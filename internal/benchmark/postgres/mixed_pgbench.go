@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	iometrics "github.com/moguls753/uuid-benchmark/internal/benchmark/io"
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
 )
 
@@ -28,6 +30,47 @@ func (p *PostgresBenchmarker) RunMixedWorkloadPgbench(keyType string, initialDat
 	fmt.Printf("Running mixed workload (%d inserts, %d reads, %d updates)...\n",
 		insertOps, readOps, updateOps)
 
+	numRecords, err := p.targetRecords(initialDataset)
+	if err != nil {
+		return nil, err
+	}
+
+	// One script per operation, pgbench picking one for each transaction by weight, so
+	// its transaction log tells the operations' latencies apart
+	operations := [3]struct {
+		name   string
+		script string
+		weight int
+	}{
+		{"insert", p.layout().GenerateInsertScript(keyType, p.tableName), insertWeight},
+		{"select", p.layout().GenerateSelectScript(keyType, p.tableName), readWeight},
+		{"update", p.layout().GenerateUpdateScript(keyType, p.tableName), updateWeight},
+	}
+	var scripts []pgbench.WeightedScript
+	scriptNo := [3]int{-1, -1, -1} // pgbench's script number per operation, -1 = not run
+	for i, op := range operations {
+		if op.weight == 0 {
+			continue
+		}
+		scriptWithVars := fmt.Sprintf("\\set num_records %d\n%s", numRecords, op.script)
+		scriptName := fmt.Sprintf("mixed_%s_%s.sql", op.name, keyType)
+		containerPath, err := p.copyScript(scriptWithVars, scriptName)
+		if err != nil {
+			return nil, fmt.Errorf("copy script to container: %w", err)
+		}
+		scriptNo[i] = len(scripts)
+		scripts = append(scripts, pgbench.WeightedScript{Path: containerPath, Weight: op.weight})
+	}
+
+	execCfg := p.execConfig(connections, totalOps/connections, scripts[0].Path)
+	execCfg.Scripts = scripts
+	execCfg.LogName = p.logName(fmt.Sprintf("mixed_%s_%d_%d_%d", keyType, insertWeight, readWeight, updateWeight))
+	execCfg.LogLatencies = true
+
+	if err := p.warmup(execCfg); err != nil {
+		return nil, err
+	}
+
 	startLSN, err := p.getCurrentLSN()
 	if err != nil {
 		return nil, fmt.Errorf("capture start LSN: %w", err)
@@ -36,23 +79,19 @@ func (p *PostgresBenchmarker) RunMixedWorkloadPgbench(keyType string, initialDat
 
 	startTime := time.Now()
 
-	// pgbench supports weighted mixed workloads via multiple -f flags with @weight
-	// but for simplicity, we use the conditional script approach
-	script := pgbench.GenerateMixedScript(keyType, p.tableName, insertWeight, readWeight, updateWeight)
-
-	scriptWithVars := fmt.Sprintf("\\set num_records %d\n%s", initialDataset, script)
-
-	scriptName := fmt.Sprintf("mixed_%s_%d_%d_%d.sql", keyType, insertWeight, readWeight, updateWeight)
-	containerPath, err := pgbench.CopyScriptToContainer("uuid-bench-postgres", scriptWithVars, scriptName)
-	if err != nil {
-		return nil, fmt.Errorf("copy script to container: %w", err)
+	// An external server's CPU and memory are not the pgbench container's
+	var sampler *iometrics.ResourceSampler
+	if !Connection.External() {
+		sampler, err = iometrics.StartResourceSampler(Connection.Container, 250*time.Millisecond)
+		if err != nil {
+			fmt.Printf("Warning:Failed to start CPU/memory sampling: %v\n", err)
+		}
 	}
+	defer sampler.Stop()
 
-	execCfg := pgbench.ExecutorConfig{
-		ContainerName: "uuid-bench-postgres",
-		Connections:   connections,
-		Transactions:  totalOps / connections,
-		ScriptPath:    containerPath,
+	tempBefore, err := p.TempUsage()
+	if err != nil {
+		fmt.Printf("Warning: Failed to capture temp file stats before mixed workload: %v\n", err)
 	}
 
 	execResult, err := pgbench.Execute(execCfg)
@@ -65,11 +104,22 @@ func (p *PostgresBenchmarker) RunMixedWorkloadPgbench(keyType string, initialDat
 	}
 
 	duration := time.Since(startTime)
+	usage := sampler.Stop()
+
+	var tempFiles, tempBytes int64
+	tempAfter, err := p.TempUsage()
+	if err != nil {
+		fmt.Printf("Warning: Failed to capture temp file stats after mixed workload: %v\n", err)
+	} else if tempBefore != nil {
+		tempFiles, tempBytes = tempAfter.Since(tempBefore)
+	}
 
 	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
 	if err != nil {
 		return nil, fmt.Errorf("parse pgbench output: %w", err)
 	}
+	p.reportThrottling(parsed)
+	p.checkTransactions(parsed)
 
 	endLSN, err := p.getCurrentLSN()
 	if err != nil {
@@ -83,27 +133,81 @@ func (p *PostgresBenchmarker) RunMixedWorkloadPgbench(keyType string, initialDat
 		return nil, fmt.Errorf("measure metrics: %w", err)
 	}
 
-	return &benchmark.MixedWorkloadResult{
-		KeyType:           keyType,
-		NumRecords:        initialDataset,
-		TotalOps:          totalOps,
-		InsertOps:         insertOps,
-		ReadOps:           readOps,
-		UpdateOps:         updateOps,
-		Duration:          duration,
-		OverallThroughput: parsed.TPS,
-		// NOTE: pgbench mixed workloads only report OverallThroughput.
-		// Per-operation throughput metrics (InsertThroughput, ReadThroughput, UpdateThroughput)
-		// are set to 0 because pgbench doesn't separate throughput by operation type in mixed mode.
-		// To measure per-operation throughput, run separate scenarios (insert-performance,
-		// read-after-fragmentation, update-performance) instead of mixed workloads.
-		InsertThroughput:    0,
-		ReadThroughput:      0,
-		UpdateThroughput:    0,
+	var observed [3]int
+	var latencies [3]map[float64]time.Duration
+	for i, no := range scriptNo {
+		if no < 0 {
+			continue
+		}
+		observed[i] = scriptTransactions(parsed, no)
+		latencies[i] = benchmark.CalculatePercentiles(execResult.ScriptLatencies[no], benchmark.Percentiles)
+	}
+	checkMixedDistribution(parsed.Transactions, observed, [3]int{insertWeight, readWeight, updateWeight})
+
+	result := &benchmark.MixedWorkloadResult{
+		KeyType:             keyType,
+		NumRecords:          initialDataset,
+		TotalOps:            totalOps,
+		InsertOps:           insertOps,
+		ReadOps:             readOps,
+		UpdateOps:           updateOps,
+		ObservedInsertOps:   observed[0],
+		ObservedReadOps:     observed[1],
+		ObservedUpdateOps:   observed[2],
+		InsertLatency:       latencies[0],
+		ReadLatency:         latencies[1],
+		UpdateLatency:       latencies[2],
+		Duration:            duration,
+		OverallThroughput:   parsed.TPS,
+		TPSIncludingSetup:   parsed.TPSIncludingSetup,
+		ConnectionTime:      parsed.InitialConnectionTime,
 		BufferHitRatio:      metrics.BufferHitRatio,
 		IndexBufferHitRatio: metrics.IndexBufferHitRatio,
 		Fragmentation:       metrics.Fragmentation,
 		TableSize:           metrics.TableSize,
 		IndexSize:           metrics.IndexSize,
-	}, nil
+		TempFiles:           tempFiles,
+		TempBytes:           tempBytes,
+		AvgCPUPercent:       usage.AvgCPUPercent,
+		PeakCPUPercent:      usage.PeakCPUPercent,
+		AvgRSSMB:            usage.AvgRSSMB,
+		PeakRSSMB:           usage.PeakRSSMB,
+	}
+	result.SetOperationThroughputs()
+
+	return result, nil
+}
+
+// maxWeightDeviation is how far (in percentage points) an operation's observed share of
+// a mixed workload may drift from its configured weight before the run is flagged
+const maxWeightDeviation = 5.0
+
+// scriptTransactions returns how many transactions ran script number no. pgbench only
+// breaks a run down by script when it has several, so a lone script ran all of them.
+func scriptTransactions(parsed *pgbench.PgbenchResult, no int) int {
+	if len(parsed.Scripts) == 0 {
+		return parsed.Transactions
+	}
+	if no >= len(parsed.Scripts) {
+		return 0
+	}
+	return parsed.Scripts[no].Transactions
+}
+
+// checkMixedDistribution warns when the executed insert/read/update mix does not match
+// the configured weights, which pgbench only meets on average as it picks each
+// transaction's script at random
+func checkMixedDistribution(transactions int, observed, weights [3]int) {
+	if transactions == 0 {
+		return
+	}
+
+	names := [3]string{"insert", "read", "update"}
+	for i, name := range names {
+		share := float64(observed[i]) / float64(transactions) * 100
+		if math.Abs(share-float64(weights[i])) > maxWeightDeviation {
+			fmt.Printf("Warning: mixed workload ran %.1f%% %ss (%d of %d transactions), configured %d%%\n",
+				share, name, observed[i], transactions, weights[i])
+		}
+	}
 }
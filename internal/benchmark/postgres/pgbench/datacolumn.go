@@ -0,0 +1,123 @@
+package pgbench
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DataColumn describes the benchmark table's data column. Type "none" omits it along
+// with created_at, leaving an id-only table whose heap is as small as possible.
+type DataColumn struct {
+	Type    string // text, varchar(n), int or none; empty means text
+	NotNull bool
+
+	PayloadBytes int // Width text values are padded to with 'x', stored uncompressed, 0 = unpadded
+}
+
+// MinPayloadBytes is the smallest padded width: 'test_data_<n>' and 'updated_<n>' stay
+// shorter, so padding never truncates them
+const MinPayloadBytes = 16
+
+var varcharType = regexp.MustCompile(`^varchar\([1-9][0-9]*\)$`)
+
+// ValidateDataType checks a -data-type value
+func ValidateDataType(dataType string) error {
+	switch {
+	case dataType == "text", dataType == "int", dataType == "none":
+		return nil
+	case varcharType.MatchString(dataType):
+		return nil
+	}
+	return fmt.Errorf("unknown data type %q (valid: text, varchar(n), int, none)", dataType)
+}
+
+// None reports whether the table has no data (and no created_at) column
+func (c DataColumn) None() bool {
+	return c.Type == "none"
+}
+
+// TextLike reports whether data holds the 'test_data_<n>' strings as written
+func (c DataColumn) TextLike() bool {
+	return c.Type == "" || c.Type == "text"
+}
+
+// Definition returns the CREATE TABLE column list following the id column, starting
+// with a comma, or nothing for an id-only table
+func (c DataColumn) Definition() string {
+	if c.None() {
+		return ""
+	}
+
+	dataType := strings.ToUpper(c.Type)
+	if c.Type == "" {
+		dataType = "TEXT"
+	}
+	if c.PayloadBytes > 0 {
+		// The 'x' padding would compress to almost nothing under the default EXTENDED
+		// storage, leaving the heap as small as without it
+		dataType += " STORAGE EXTERNAL"
+	}
+	if c.NotNull {
+		dataType += " NOT NULL"
+	}
+
+	return fmt.Sprintf(",\n\t\t\t\tdata %s,\n\t\t\t\tcreated_at TIMESTAMP DEFAULT NOW()", dataType)
+}
+
+// pad widens a text value to PayloadBytes, if set
+func (c DataColumn) pad(value string) string {
+	if c.PayloadBytes <= 0 {
+		return value
+	}
+	return fmt.Sprintf("rpad(%s, %d, 'x')", value, c.PayloadBytes)
+}
+
+// insertValue is the SQL expression inserted into data
+func (c DataColumn) insertValue() string {
+	switch {
+	case c.Type == "int":
+		return ":client_id"
+	case varcharType.MatchString(c.Type):
+		// The explicit cast truncates instead of failing for short lengths
+		return fmt.Sprintf("('test_data_' || :client_id)::%s", c.Type)
+	}
+	return c.pad("'test_data_' || :client_id")
+}
+
+// updateSet is the SET clause of update scripts. Without a data column the row is
+// rewritten unchanged, setting idColumn to itself, which still creates a new tuple version.
+func (c DataColumn) updateSet(idColumn string) string {
+	switch {
+	case c.None():
+		return idColumn + " = " + idColumn
+	case c.Type == "int":
+		return "data = :client_id + 1"
+	case varcharType.MatchString(c.Type):
+		return fmt.Sprintf("data = ('updated_' || :client_id)::%s", c.Type)
+	}
+	return "data = " + c.pad("'updated_' || :client_id")
+}
+
+// CopyValue returns the data value of the n-th row streamed by COPY, matching what the
+// insert scripts write: 'test_data_<n>' padded to PayloadBytes, truncated to a varchar's
+// length, or n itself for int
+func (c DataColumn) CopyValue(n int) any {
+	if c.Type == "int" {
+		return n
+	}
+
+	value := fmt.Sprintf("test_data_%d", n)
+	if varcharType.MatchString(c.Type) {
+		var length int
+		fmt.Sscanf(c.Type, "varchar(%d)", &length)
+		if len(value) > length {
+			value = value[:length]
+		}
+		return value
+	}
+	if len(value) < c.PayloadBytes {
+		value += strings.Repeat("x", c.PayloadBytes-len(value))
+	}
+	return value
+}
@@ -6,45 +6,93 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
+// WeightedScript is one of several scripts a run picks from for each transaction, in
+// proportion to Weight (-f path@weight)
+type WeightedScript struct {
+	Path   string
+	Weight int
+}
+
 type ExecutorConfig struct {
 	ContainerName string
+	User          string
+	Password      string // Sent as PGPASSWORD with Host, the trusted local socket needs none
+	DBName        string
+	SSLMode       string // PGSSLMODE with Host, empty = libpq default
 	Connections   int
 	Transactions  int
 	ScriptPath    string
+	Scripts       []WeightedScript // Run instead of ScriptPath, each transaction picking one by weight
 	Duration      int
+	Rate          float64 // -R target transactions/sec across all clients, 0 = unthrottled
+	LatencyLimit  float64 // --latency-limit in ms; late transactions are counted, and skipped under -R
+	LogDir        string  // If set, raw stdout/stderr are written here as timestamped files
+	LogName       string  // Identifies the run in log file names (scenario, key type, script)
+	QueryMode     string  // -M simple, extended or prepared; empty = pgbench default (simple)
+	Host          string  // Server pgbench connects to over TCP (e.g. a pooler); empty = the container's local socket
+	Port          string  // Port of Host
+	LogLatencies  bool    // Write pgbench's per-transaction log (-l) and return every latency
+	Reconnect     bool    // -C: open a new connection for every transaction
+	RandomSeed    int64   // --random-seed for the scripts' random(), 0 = pgbench default (seeded from the clock)
 }
 
 type ExecuteResult struct {
-	Stdout   string
-	Stderr   string
-	ExitCode int
+	Stdout    string
+	Stderr    string
+	ExitCode  int
+	Latencies []time.Duration // Per-transaction latencies, with LogLatencies
+
+	ScriptLatencies map[int][]time.Duration // Latencies by the 0-based script that ran the transaction
 }
 
 func Execute(cfg ExecutorConfig) (*ExecuteResult, error) {
 	if cfg.ContainerName == "" {
 		return nil, fmt.Errorf("container name is required")
 	}
-	if cfg.ScriptPath == "" {
+	if cfg.User == "" || cfg.DBName == "" {
+		return nil, fmt.Errorf("user and database are required")
+	}
+	if cfg.ScriptPath == "" && len(cfg.Scripts) == 0 {
 		return nil, fmt.Errorf("script path is required")
 	}
 	if cfg.Transactions == 0 && cfg.Duration == 0 {
 		return nil, fmt.Errorf("either transactions (-t) or duration (-T) must be specified")
 	}
 
-	args := []string{
-		"exec",
+	args := []string{"exec"}
+	if cfg.Host != "" {
+		// TCP connections authenticate by password, unlike the trusted local socket
+		args = append(args, "-e", "PGPASSWORD="+cfg.Password)
+		if cfg.SSLMode != "" {
+			args = append(args, "-e", "PGSSLMODE="+cfg.SSLMode)
+		}
+	}
+	args = append(args,
 		cfg.ContainerName,
 		"pgbench",
-		"-U", "benchmark",
-		"-d", "uuid_benchmark",
+		"-U", cfg.User,
+		"-d", cfg.DBName,
+	)
+	if cfg.Host != "" {
+		args = append(args, "-h", cfg.Host, "-p", cfg.Port)
+	}
+	args = append(args,
 		"-n",
 		"-c", fmt.Sprintf("%d", cfg.Connections),
 		"-j", fmt.Sprintf("%d", cfg.Connections),
-		"-f", cfg.ScriptPath,
-		"--progress=1",
+	)
+	if len(cfg.Scripts) > 0 {
+		for _, script := range cfg.Scripts {
+			args = append(args, "-f", fmt.Sprintf("%s@%d", script.Path, script.Weight))
+		}
+	} else {
+		args = append(args, "-f", cfg.ScriptPath)
 	}
+	args = append(args, "--progress=1")
 
 	if cfg.Transactions > 0 {
 		args = append(args, "-t", fmt.Sprintf("%d", cfg.Transactions))
@@ -52,6 +100,29 @@ func Execute(cfg ExecutorConfig) (*ExecuteResult, error) {
 		args = append(args, "-T", fmt.Sprintf("%d", cfg.Duration))
 	}
 
+	if cfg.QueryMode != "" {
+		args = append(args, "-M", cfg.QueryMode)
+	}
+
+	if cfg.Rate > 0 {
+		args = append(args, "-R", fmt.Sprintf("%g", cfg.Rate))
+	}
+	if cfg.LatencyLimit > 0 {
+		args = append(args, fmt.Sprintf("--latency-limit=%g", cfg.LatencyLimit))
+	}
+
+	if cfg.LogLatencies {
+		args = append(args, "-l", "--log-prefix="+latencyLogPrefix)
+	}
+
+	if cfg.Reconnect {
+		args = append(args, "-C")
+	}
+
+	if cfg.RandomSeed != 0 {
+		args = append(args, fmt.Sprintf("--random-seed=%d", cfg.RandomSeed))
+	}
+
 	cmd := exec.Command("docker", args...)
 
 	var stdout, stderr bytes.Buffer
@@ -74,9 +145,75 @@ func Execute(cfg ExecutorConfig) (*ExecuteResult, error) {
 		}
 	}
 
+	if cfg.LogLatencies {
+		latencies, byScript, err := collectLatencies(cfg.ContainerName)
+		if err != nil {
+			fmt.Printf("Warning: failed to read pgbench transaction log: %v\n", err)
+		}
+		result.Latencies = latencies
+		result.ScriptLatencies = byScript
+	}
+
+	if cfg.LogDir != "" {
+		if err := writeRunLog(cfg, result); err != nil {
+			fmt.Printf("Warning: failed to write pgbench log: %v\n", err)
+		}
+	}
+
 	return result, nil
 }
 
+// writeRunLog saves the raw pgbench output as <timestamp>_<name>.stdout.log and
+// <timestamp>_<name>.stderr.log in cfg.LogDir
+func writeRunLog(cfg ExecutorConfig, result *ExecuteResult) error {
+	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+		return fmt.Errorf("create log dir: %w", err)
+	}
+
+	name := cfg.LogName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(cfg.ScriptPath), filepath.Ext(cfg.ScriptPath))
+	}
+	base := filepath.Join(cfg.LogDir, fmt.Sprintf("%s_%s", time.Now().Format("20060102T150405.000"), name))
+
+	if err := os.WriteFile(base+".stdout.log", []byte(result.Stdout), 0644); err != nil {
+		return fmt.Errorf("write stdout log: %w", err)
+	}
+	if err := os.WriteFile(base+".stderr.log", []byte(result.Stderr), 0644); err != nil {
+		return fmt.Errorf("write stderr log: %w", err)
+	}
+
+	return nil
+}
+
+// Warmup runs a throwaway, unthrottled pgbench invocation of the given number of
+// transactions per client against the same script, discarding its output
+func Warmup(cfg ExecutorConfig, transactions int) error {
+	cfg.Transactions = transactions
+	cfg.Duration = 0
+	cfg.Rate = 0
+	cfg.LatencyLimit = 0
+	cfg.LogLatencies = false
+	// A different fixed seed, so warmup doesn't pre-touch exactly the ids the measured
+	// run will visit
+	if cfg.RandomSeed != 0 {
+		cfg.RandomSeed++
+	}
+	if cfg.LogName != "" {
+		cfg.LogName += "_warmup"
+	}
+
+	result, err := Execute(cfg)
+	if err != nil {
+		return err
+	}
+	if result.ExitCode != 0 {
+		return fmt.Errorf("warmup pgbench failed with exit code %d: %s", result.ExitCode, result.Stderr)
+	}
+
+	return nil
+}
+
 func CopyScriptToContainer(containerName, scriptContent, scriptName string) (string, error) {
 	tmpFile, err := os.CreateTemp("", scriptName)
 	if err != nil {
@@ -104,6 +241,25 @@ func CopyScriptToContainer(containerName, scriptContent, scriptName string) (str
 	return containerPath, nil
 }
 
+// RemoveScriptsFromContainer deletes previously copied scripts from the container
+func RemoveScriptsFromContainer(containerName string, containerPaths ...string) error {
+	if len(containerPaths) == 0 {
+		return nil
+	}
+
+	args := append([]string{"exec", containerName, "rm", "-f"}, containerPaths...)
+	cmd := exec.Command("docker", args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to remove scripts from container: %w (stderr: %s)", err, stderr.String())
+	}
+
+	return nil
+}
+
 func ExecuteSQL(containerName, sql string) error {
 	cmd := exec.Command("docker", "exec", containerName,
 		"psql", "-U", "benchmark", "-d", "uuid_benchmark", "-c", sql)
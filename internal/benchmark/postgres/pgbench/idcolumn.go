@@ -0,0 +1,43 @@
+package pgbench
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+var identifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// reservedKeywords are PostgreSQL's reserved and type/function name keywords, which an
+// unquoted column name cannot be
+var reservedKeywords = []string{
+	"all", "analyse", "analyze", "and", "any", "array", "as", "asc", "asymmetric", "both",
+	"case", "cast", "check", "collate", "column", "constraint", "create", "current_catalog",
+	"current_date", "current_role", "current_time", "current_timestamp", "current_user",
+	"default", "deferrable", "desc", "distinct", "do", "else", "end", "except", "false",
+	"fetch", "for", "foreign", "from", "grant", "group", "having", "in", "initially",
+	"intersect", "into", "lateral", "leading", "limit", "localtime", "localtimestamp", "not",
+	"null", "offset", "on", "only", "or", "order", "placing", "primary", "references",
+	"returning", "select", "session_user", "some", "symmetric", "system_user", "table",
+	"then", "to", "trailing", "true", "union", "unique", "user", "using", "variadic", "when",
+	"where", "window", "with",
+	"authorization", "binary", "collation", "concurrently", "cross", "current_schema",
+	"freeze", "full", "ilike", "inner", "is", "isnull", "join", "left", "like", "natural",
+	"notnull", "outer", "overlaps", "right", "similar", "tablesample", "verbose",
+}
+
+// ValidateIDColumn checks a -id-column value: a lowercase SQL identifier other than a
+// reserved keyword, so it needs no quoting, that does not collide with the table's other
+// columns
+func ValidateIDColumn(name string) error {
+	if !identifier.MatchString(name) || len(name) > 63 {
+		return fmt.Errorf("%q is not a lowercase SQL identifier (letters, digits and _, at most 63 characters)", name)
+	}
+	if slices.Contains(reservedKeywords, name) {
+		return fmt.Errorf("%q is a reserved SQL keyword", name)
+	}
+	if slices.Contains([]string{"data", "created_at", "payload"}, name) {
+		return fmt.Errorf("%q is already a column of the benchmark table", name)
+	}
+	return nil
+}
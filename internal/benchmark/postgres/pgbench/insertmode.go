@@ -0,0 +1,70 @@
+package pgbench
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InsertModes are the ways -insert-mode loads rows: pgbench runs -batch-size single-row
+// INSERTs per transaction, batch one multi-row INSERT per transaction and copy streams
+// every row through one COPY FROM STDIN
+var InsertModes = []string{"pgbench", "batch", "copy"}
+
+// ValidateInsertMode checks an -insert-mode value
+func ValidateInsertMode(mode string) error {
+	for _, m := range InsertModes {
+		if mode == m {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown insert mode %q (valid: %s)", mode, strings.Join(InsertModes, ", "))
+}
+
+// GenerateMultiRowInsertScript inserts rows rows with a single INSERT ... SELECT over
+// generate_series, so the generator and data expressions run once per row
+func (l Layout) GenerateMultiRowInsertScript(keyType, tableName string, rows int) string {
+	from := fmt.Sprintf("generate_series(1, %d)", rows)
+
+	var columns, values []string
+	if keyType != "bigserial" {
+		idExpr, ok := l.idExpression(keyType)
+		if !ok {
+			return fmt.Sprintf(`-- Unknown key type: %s`, keyType)
+		}
+		if l.Replay {
+			// idExpression's uncorrelated claim would run once per statement and give
+			// every row the same id; claim one seq per row instead, inserting in claim order
+			idExpr = "(SELECT id FROM replay_ops WHERE seq = claim.seq)"
+			from = fmt.Sprintf("(SELECT nextval('replay_cursor') AS seq FROM %s) AS claim ORDER BY claim.seq", from)
+		}
+		columns, values = []string{l.idColumn()}, []string{idExpr}
+	}
+	if !l.DataColumn.None() {
+		columns, values = append(columns, "data"), append(values, l.DataColumn.insertValue())
+	}
+
+	if len(columns) == 0 {
+		// Every column has a default; an empty select list still yields one row each
+		return fmt.Sprintf(`INSERT INTO %s SELECT FROM %s;`, tableName, from)
+	}
+	return fmt.Sprintf(`INSERT INTO %s (%s) SELECT %s FROM %s;`,
+		tableName, strings.Join(columns, ", "), strings.Join(values, ", "), from)
+}
+
+// GenerateMultiRowInsertReturningScript is GenerateMultiRowInsertScript returning every
+// generated id to the client
+func (l Layout) GenerateMultiRowInsertReturningScript(keyType, tableName string, rows int) string {
+	statement := l.GenerateMultiRowInsertScript(keyType, tableName, rows)
+	if strings.HasPrefix(statement, "--") {
+		return statement
+	}
+	return strings.TrimSuffix(statement, ";") + " RETURNING " + l.idColumn() + ";"
+}
+
+// ColumnDefault returns the id generator as a column default, for loads that do not go
+// through pgbench scripts (COPY). Snowflake ids all come from machine 0, since there is
+// a single loading client.
+func (l Layout) ColumnDefault(keyType string) (string, bool) {
+	expr, ok := l.idExpression(keyType)
+	return strings.ReplaceAll(expr, ":client_id", "0"), ok
+}
@@ -0,0 +1,63 @@
+package pgbench
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// latencyLogPrefix is where pgbench -l writes its per-transaction logs inside the
+// container, one file per thread: <prefix>.<pid>[.<thread>]
+const latencyLogPrefix = "/tmp/pgbench_latency"
+
+// collectLatencies reads and removes the per-transaction logs of the last run. Each line
+// is "client_id transaction_no time script_no time_epoch time_us [...]", where time is
+// the latency in microseconds, or "skipped"/"failed" for transactions that did not run.
+// Latencies are returned in log order and grouped by script_no.
+func collectLatencies(containerName string) ([]time.Duration, map[int][]time.Duration, error) {
+	script := fmt.Sprintf("cat %[1]s.* 2>/dev/null; rm -f %[1]s.*", latencyLogPrefix)
+	cmd := exec.Command("docker", "exec", containerName, "sh", "-c", script)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("open transaction log: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("read transaction log: %w", err)
+	}
+
+	var latencies []time.Duration
+	byScript := make(map[int][]time.Duration)
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		us, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		latency := time.Duration(us) * time.Microsecond
+		latencies = append(latencies, latency)
+		if script, err := strconv.Atoi(fields[3]); err == nil {
+			byScript[script] = append(byScript[script], latency)
+		}
+	}
+	scanErr := scanner.Err()
+
+	if err := cmd.Wait(); err != nil {
+		return nil, nil, fmt.Errorf("read transaction log: %w (stderr: %s)", err, stderr.String())
+	}
+	if scanErr != nil {
+		return nil, nil, fmt.Errorf("parse transaction log: %w", scanErr)
+	}
+
+	return latencies, byScript, nil
+}
@@ -10,15 +10,27 @@ import (
 
 // PgbenchResult contains parsed metrics from pgbench output
 type PgbenchResult struct {
-	TPS               float64       // Transactions per second (excluding connections establishing)
-	TPSIncludingSetup float64       // Transactions per second (including connection time)
-	LatencyAvg        time.Duration // Average latency
-	LatencyStdDev     time.Duration // Latency standard deviation
-	P50               time.Duration // 50th percentile latency
-	P95               time.Duration // 95th percentile latency
-	P99               time.Duration // 99th percentile latency
-	Transactions      int           // Number of actually processed transactions
-	Duration          time.Duration // Total duration
+	TPS                   float64                   // Transactions per second (excluding connections establishing)
+	TPSIncludingSetup     float64                   // Transactions per second (including connection time)
+	LatencyAvg            time.Duration             // Average latency; under -R measured from each transaction's scheduled start
+	LatencyStdDev         time.Duration             // Latency standard deviation
+	Percentiles           map[float64]time.Duration // Latency per percentile, from "percentile N = ..." lines
+	Transactions          int                       // Number of actually processed transactions
+	ExpectedTransactions  int                       // Transactions requested (clients × -t), from "processed: N/M"
+	Duration              time.Duration             // Total duration
+	InitialConnectionTime time.Duration             // Time spent establishing client connections
+	AvgConnectionTime     time.Duration             // Mean time to open a connection, with -C (reconnect per transaction)
+	Skipped               int                       // Transactions skipped under -R because they started too late
+	LatencyLimitExceeded  int                       // Transactions above --latency-limit
+	ScheduleLagAvg        time.Duration             // Under -R, mean delay between scheduled and actual transaction start
+	ScheduleLagMax        time.Duration             // Under -R, largest such delay
+	Scripts               []ScriptResult            // Per-script breakdown, printed when a run has several scripts
+}
+
+// ScriptResult is one script's share of a run with several weighted scripts
+type ScriptResult struct {
+	Transactions int     // Transactions that ran this script
+	TPS          float64 // This script's transactions per second
 }
 
 // ParsePgbenchOutput parses the stdout from pgbench and extracts metrics
@@ -49,14 +61,38 @@ func ParsePgbenchOutput(output string) (*PgbenchResult, error) {
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
-		// Parse number of transactions actually processed
+		// Parse number of transactions actually processed; time-bound (-T) runs print
+		// only the count, without the requested total
 		if strings.Contains(line, "number of transactions actually processed") {
-			re := regexp.MustCompile(`(\d+)/\d+`)
+			re := regexp.MustCompile(`processed:\s*(\d+)(?:/(\d+))?`)
 			matches := re.FindStringSubmatch(line)
-			if len(matches) >= 2 {
+			if len(matches) >= 3 {
 				if val, err := strconv.Atoi(matches[1]); err == nil {
 					result.Transactions = val
 				}
+				if val, err := strconv.Atoi(matches[2]); err == nil {
+					result.ExpectedTransactions = val
+				}
+			}
+		}
+
+		// Parse "number of transactions skipped: 12 (0.120%)"
+		if strings.HasPrefix(line, "number of transactions skipped") {
+			re := regexp.MustCompile(`skipped:\s*(\d+)`)
+			if matches := re.FindStringSubmatch(line); len(matches) >= 2 {
+				if val, err := strconv.Atoi(matches[1]); err == nil {
+					result.Skipped = val
+				}
+			}
+		}
+
+		// Parse "number of transactions above the 5.0 ms latency limit: 34/10000 (0.340%)"
+		if strings.Contains(line, "latency limit:") {
+			re := regexp.MustCompile(`latency limit:\s*(\d+)`)
+			if matches := re.FindStringSubmatch(line); len(matches) >= 2 {
+				if val, err := strconv.Atoi(matches[1]); err == nil {
+					result.LatencyLimitExceeded = val
+				}
 			}
 		}
 
@@ -68,6 +104,19 @@ func ParsePgbenchOutput(output string) (*PgbenchResult, error) {
 			}
 		}
 
+		// Parse "rate limit schedule lag: avg 0.123 (max 4.567) ms", printed under -R
+		if strings.HasPrefix(line, "rate limit schedule lag") {
+			re := regexp.MustCompile(`avg\s*([0-9.]+)\s*\(max\s*([0-9.]+)\)\s*ms`)
+			if matches := re.FindStringSubmatch(line); len(matches) >= 3 {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					result.ScheduleLagAvg = time.Duration(val * float64(time.Millisecond))
+				}
+				if val, err := strconv.ParseFloat(matches[2], 64); err == nil {
+					result.ScheduleLagMax = time.Duration(val * float64(time.Millisecond))
+				}
+			}
+		}
+
 		// Parse latency stddev
 		if strings.HasPrefix(line, "latency stddev") {
 			val, err := parseLatency(line)
@@ -76,6 +125,22 @@ func ParsePgbenchOutput(output string) (*PgbenchResult, error) {
 			}
 		}
 
+		// Parse initial connection time
+		if strings.HasPrefix(line, "initial connection time") {
+			val, err := parseLatency(line)
+			if err == nil {
+				result.InitialConnectionTime = val
+			}
+		}
+
+		// Parse average connection time, printed instead of the initial one under -C
+		if strings.HasPrefix(line, "average connection time") {
+			val, err := parseLatency(line)
+			if err == nil {
+				result.AvgConnectionTime = val
+			}
+		}
+
 		// Parse TPS (excluding connection time)
 		if strings.HasPrefix(line, "tps") && strings.Contains(line, "without") {
 			re := regexp.MustCompile(`tps\s*=\s*([0-9.]+)`)
@@ -98,32 +163,52 @@ func ParsePgbenchOutput(output string) (*PgbenchResult, error) {
 			}
 		}
 
+		// Parse "SQL script 2: /tmp/read.sql", which opens that script's block
+		if strings.HasPrefix(line, "SQL script") {
+			result.Scripts = append(result.Scripts, ScriptResult{})
+		}
+
+		// Parse " - 3012 transactions (30.1% of total, tps = 1234.567890)" of the open block
+		if matches := scriptTransactionsLine.FindStringSubmatch(line); len(matches) >= 3 && len(result.Scripts) > 0 {
+			script := &result.Scripts[len(result.Scripts)-1]
+			if val, err := strconv.Atoi(matches[1]); err == nil {
+				script.Transactions = val
+			}
+			if val, err := strconv.ParseFloat(matches[2], 64); err == nil {
+				script.TPS = val
+			}
+		}
+
 		// Parse percentiles
-		if strings.Contains(line, "percentile") {
-			if strings.Contains(line, "50") {
-				val, err := parseLatency(line)
-				if err == nil {
-					result.P50 = val
-				}
-			} else if strings.Contains(line, "95") {
-				val, err := parseLatency(line)
-				if err == nil {
-					result.P95 = val
-				}
-			} else if strings.Contains(line, "99") {
-				val, err := parseLatency(line)
-				if err == nil {
-					result.P99 = val
+		if matches := percentileLine.FindStringSubmatch(line); len(matches) >= 2 {
+			percentile, err := strconv.ParseFloat(matches[1], 64)
+			if err == nil {
+				if val, err := parseLatency(line); err == nil {
+					if result.Percentiles == nil {
+						result.Percentiles = make(map[float64]time.Duration)
+					}
+					result.Percentiles[percentile] = val
 				}
 			}
 		}
 	}
 
+	// Under -C every transaction opens its own connection, and pgbench only reports
+	// "tps = N (including reconnection times)"
+	if result.TPS == 0 && result.AvgConnectionTime > 0 {
+		result.TPS = result.TPSIncludingSetup
+	}
+
 	// Calculate duration from TPS and transactions
 	if result.TPS > 0 && result.Transactions > 0 {
 		result.Duration = time.Duration(float64(result.Transactions)/result.TPS*1000) * time.Millisecond
 	}
 
+	// pgbench 14+ only reports TPS without connection time; derive the other figure
+	if result.TPSIncludingSetup == 0 && result.Duration > 0 && result.InitialConnectionTime > 0 {
+		result.TPSIncludingSetup = float64(result.Transactions) / (result.Duration + result.InitialConnectionTime).Seconds()
+	}
+
 	// Validation
 	if result.TPS == 0 {
 		return nil, fmt.Errorf("failed to parse TPS from pgbench output")
@@ -135,6 +220,12 @@ func ParsePgbenchOutput(output string) (*PgbenchResult, error) {
 	return result, nil
 }
 
+// scriptTransactionsLine matches a script block's "- 3012 transactions (30.1% of total, tps = 1234.5)"
+var scriptTransactionsLine = regexp.MustCompile(`^- (\d+) transactions \(.*tps\s*=\s*([0-9.]+)\)`)
+
+// percentileLine matches "percentile 99.9 = 3.500 ms"
+var percentileLine = regexp.MustCompile(`^percentile\s+([0-9.]+)\s*=`)
+
 // parseLatency parses a latency value from a line like "latency average = 1.234 ms"
 func parseLatency(line string) (time.Duration, error) {
 	// Match patterns like "= 1.234 ms" or "= 1234.567 us"
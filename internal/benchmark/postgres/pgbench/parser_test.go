@@ -0,0 +1,85 @@
+package pgbench
+
+import (
+	"testing"
+	"time"
+)
+
+// rateLimitedOutput is pgbench 16 output of a run with -R 1000 --latency-limit 5
+const rateLimitedOutput = `pgbench (16.4 (Debian 16.4-1.pgdg120+1))
+transaction type: /tmp/insert_uuidv7.sql
+scaling factor: 1
+query mode: simple
+number of clients: 4
+number of threads: 4
+maximum number of tries: 1
+number of transactions per client: 2500
+number of transactions actually processed: 9966/10000
+number of failed transactions: 0 (0.000%)
+number of transactions skipped: 34 (0.340%)
+number of transactions above the 5.0 ms latency limit: 12/9966 (0.120%)
+latency average = 0.412 ms
+latency stddev = 0.391 ms
+rate limit schedule lag: avg 0.121 (max 6.234) ms
+initial connection time = 2.631 ms
+tps = 999.612345 (without initial connection time)
+`
+
+func TestParsePgbenchOutputRateLimited(t *testing.T) {
+	result, err := ParsePgbenchOutput(rateLimitedOutput)
+	if err != nil {
+		t.Fatalf("ParsePgbenchOutput: %v", err)
+	}
+
+	durations := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"LatencyAvg", result.LatencyAvg, 412 * time.Microsecond},
+		{"LatencyStdDev", result.LatencyStdDev, 391 * time.Microsecond},
+		{"ScheduleLagAvg", result.ScheduleLagAvg, 121 * time.Microsecond},
+		{"ScheduleLagMax", result.ScheduleLagMax, 6234 * time.Microsecond},
+		{"InitialConnectionTime", result.InitialConnectionTime, 2631 * time.Microsecond},
+	}
+	for _, d := range durations {
+		if d.got != d.want {
+			t.Errorf("%s = %v, want %v", d.name, d.got, d.want)
+		}
+	}
+
+	counts := []struct {
+		name      string
+		got, want int
+	}{
+		{"Transactions", result.Transactions, 9966},
+		{"ExpectedTransactions", result.ExpectedTransactions, 10000},
+		{"Skipped", result.Skipped, 34},
+		{"LatencyLimitExceeded", result.LatencyLimitExceeded, 12},
+	}
+	for _, c := range counts {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+
+	if result.TPS != 999.612345 {
+		t.Errorf("TPS = %v, want 999.612345", result.TPS)
+	}
+}
+
+func TestParsePgbenchOutputUnthrottled(t *testing.T) {
+	output := `number of transactions actually processed: 10000/10000
+latency average = 0.250 ms
+initial connection time = 2.000 ms
+tps = 4000.000000 (without initial connection time)
+`
+	result, err := ParsePgbenchOutput(output)
+	if err != nil {
+		t.Fatalf("ParsePgbenchOutput: %v", err)
+	}
+	if result.ScheduleLagAvg != 0 || result.ScheduleLagMax != 0 || result.Skipped != 0 {
+		t.Errorf("got lag avg %v, max %v, %d skipped; want none without -R",
+			result.ScheduleLagAvg, result.ScheduleLagMax, result.Skipped)
+	}
+}
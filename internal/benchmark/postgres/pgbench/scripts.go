@@ -2,6 +2,7 @@ package pgbench
 
 import (
 	"fmt"
+	"strings"
 )
 
 type ScriptType string
@@ -12,117 +13,287 @@ const (
 	ScriptUpdate ScriptType = "update"
 )
 
-func GenerateInsertScript(keyType, tableName string) string {
-	switch keyType {
-	case "bigserial":
-		return fmt.Sprintf(`INSERT INTO %s (data) VALUES ('test_data_' || :client_id);`, tableName)
+// idExpressions maps each key type to the server-side function generating its ids.
+// BIGSERIAL ids come from the column default and are omitted from inserts.
+var idExpressions = map[string]string{
+	"uuidv4":         "gen_random_uuid()",
+	"uuidv7":         "uuidv7()",
+	"uuidv1":         "uuid_generate_v1()",
+	"uuidv6":         "gen_uuidv6()",
+	"ulid":           "gen_ulid()",
+	"ulid_monotonic": "gen_monotonic_ulid()",
+	"ksuid":          "gen_ksuid()",
+}
+
+// TimestampSkew simulates an imperfect clock for time-ordered id generation: each id's
+// timestamp is shifted back by a random 0..MaxMs milliseconds with probability Rate
+type TimestampSkew struct {
+	MaxMs float64
+	Rate  float64
+}
+
+// Enabled reports whether any skew is injected
+func (s TimestampSkew) Enabled() bool {
+	return s.MaxMs > 0 && s.Rate > 0
+}
+
+// InsertOrders are the timestamp progressions -insert-order can give generated ids
+var InsertOrders = []string{"forward", "reverse", "random"}
 
-	case "uuidv4":
-		return fmt.Sprintf(`INSERT INTO %s (id, data) VALUES (gen_random_uuid(), 'test_data_' || :client_id);`, tableName)
+// ValidateInsertOrder checks an -insert-order value
+func ValidateInsertOrder(order string) error {
+	for _, o := range InsertOrders {
+		if order == o {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown insert order %q (valid: %s)", order, strings.Join(InsertOrders, ", "))
+}
 
-	case "uuidv7":
-		return fmt.Sprintf(`INSERT INTO %s (id, data) VALUES (uuidv7(), 'test_data_' || :client_id);`, tableName)
+// InsertOrderSequence numbers reverse-order inserts; each one steps a second further back
+const InsertOrderSequence = "insert_order_seq"
 
-	case "uuidv1":
-		return fmt.Sprintf(`INSERT INTO %s (id, data) VALUES (uuid_generate_v1(), 'test_data_' || :client_id);`, tableName)
+// Layout is the benchmark table and id generation that generated scripts follow. Zero
+// fields take the defaults: an id column named id, a text data column, ids from the clock.
+type Layout struct {
+	IDColumn   string     // Name of the primary key column, empty = id
+	DataColumn DataColumn // Type and nullability of the data column, or an id-only table
 
-	case "ulid":
-		return fmt.Sprintf(`INSERT INTO %s (id, data) VALUES (gen_ulid(), 'test_data_' || :client_id);`, tableName)
+	// TimestampSkew shifts uuidv7 timestamps back at random. Only uuidv7 supports it:
+	// PostgreSQL 18's uuidv7(shift) takes a timestamp offset, while the ULID and UUIDv1
+	// generators read the clock themselves.
+	TimestampSkew TimestampSkew
 
-	case "ulid_monotonic":
-		return fmt.Sprintf(`INSERT INTO %s (id, data) VALUES (gen_monotonic_ulid(), 'test_data_' || :client_id);`, tableName)
+	// InsertOrder is the timestamp progression of uuidv7 ids: forward (the clock),
+	// reverse (each id a second before the previous, as when backfilling history newest
+	// first) or random (timestamps spread over the past year); empty = forward. Like
+	// timestamp skew it relies on uuidv7(shift); other generators read the clock.
+	InsertOrder string
 
-	default:
-		return fmt.Sprintf(`-- Unknown key type: %s`, keyType)
+	// UUIDv8TimeBits is how many high bits of the 48-bit millisecond timestamp uuidv8 ids
+	// keep. The dropped low bits are zeroed, coarsening the timestamp to 2^(48-bits) ms
+	// buckets (38 bits is about one second) whose ids are ordered only by the random bits
+	// that follow; 0 bits leaves the ids fully random.
+	UUIDv8TimeBits int
+
+	// Replay makes inserts take recorded ids instead of generating them: each insert takes
+	// the replay_ops row whose seq is the next value of the replay_cursor sequence. The
+	// uncorrelated subquery runs nextval once per insert.
+	Replay bool
+}
+
+// idColumn returns the primary key column name
+func (l Layout) idColumn() string {
+	if l.IDColumn == "" {
+		return "id"
+	}
+	return l.IDColumn
+}
+
+// idExpression returns the SQL generating an id for keyType, with timestamp skew applied
+func (l Layout) idExpression(keyType string) (string, bool) {
+	if l.Replay && keyType != "bigserial" {
+		return "(SELECT id FROM replay_ops WHERE seq = (SELECT nextval('replay_cursor')))", true
 	}
+	if keyType == "uuidv7" && l.InsertOrder == "reverse" {
+		return fmt.Sprintf("uuidv7(-nextval('%s') * interval '1 second')", InsertOrderSequence), true
+	}
+	if keyType == "uuidv7" && l.InsertOrder == "random" {
+		return "uuidv7(-(random() * 365) * interval '1 day')", true
+	}
+	if keyType == "uuidv7" && l.TimestampSkew.Enabled() {
+		return fmt.Sprintf("uuidv7(CASE WHEN random() < %g THEN -(random() * %g) * interval '1 millisecond' ELSE interval '0' END)",
+			l.TimestampSkew.Rate, l.TimestampSkew.MaxMs), true
+	}
+	if keyType == "snowflake" {
+		// Each pgbench client is its own machine, so concurrent clients never collide
+		return "gen_snowflake(:client_id)", true
+	}
+	if keyType == "uuidv8" {
+		return fmt.Sprintf("gen_uuidv8(%d)", l.UUIDv8TimeBits), true
+	}
+	expr, ok := idExpressions[keyType]
+	return expr, ok
 }
 
-func GenerateSelectScript(keyType, tableName string) string {
+func (l Layout) GenerateInsertScript(keyType, tableName string) string {
+	var columns, values []string
+	if !l.DataColumn.None() {
+		columns, values = []string{"data"}, []string{l.DataColumn.insertValue()}
+	}
+	return l.buildInsert(keyType, tableName, columns, values)
+}
+
+// GenerateJSONBInsertScript inserts a row with a small JSON document in the payload column
+func (l Layout) GenerateJSONBInsertScript(keyType, tableName string) string {
+	payload := `jsonb_build_object(
+    'client', :client_id,
+    'score', (random() * 1000)::int,
+    'tags', jsonb_build_array('tag_' || (random() * 50)::int, 'tag_' || (random() * 50)::int)
+  )`
+	columns, values := []string{"payload"}, []string{payload}
+	if !l.DataColumn.None() {
+		columns, values = []string{"data", "payload"}, []string{l.DataColumn.insertValue(), payload}
+	}
+	return l.buildInsert(keyType, tableName, columns, values)
+}
+
+// GenerateInsertReturningScript is GenerateInsertScript returning the generated id to
+// the client, as applications do to learn a server-assigned key
+func (l Layout) GenerateInsertReturningScript(keyType, tableName string) string {
+	statement := l.GenerateInsertScript(keyType, tableName)
+	if strings.HasPrefix(statement, "--") {
+		return statement
+	}
+	return strings.TrimSuffix(statement, ";") + " RETURNING " + l.idColumn() + ";"
+}
+
+// buildInsert generates a single-row INSERT, prepending the generated id for key
+// types that are not assigned by a column default
+func (l Layout) buildInsert(keyType, tableName string, columns, values []string) string {
+	if keyType != "bigserial" {
+		idExpr, ok := l.idExpression(keyType)
+		if !ok {
+			return fmt.Sprintf(`-- Unknown key type: %s`, keyType)
+		}
+		columns = append([]string{l.idColumn()}, columns...)
+		values = append([]string{idExpr}, values...)
+	}
+
+	if len(columns) == 0 {
+		return fmt.Sprintf(`INSERT INTO %s DEFAULT VALUES;`, tableName)
+	}
+	return fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s);`, tableName, strings.Join(columns, ", "), strings.Join(values, ", "))
+}
+
+func (l Layout) GenerateSelectScript(keyType, tableName string) string {
 	switch keyType {
 	case "bigserial":
 		return fmt.Sprintf(`\set id random(1, :num_records)
-SELECT * FROM %s WHERE id = :id;`, tableName)
+SELECT * FROM %[1]s WHERE %[2]s = :id;`, tableName, l.idColumn())
 
-	case "uuidv4", "uuidv7", "uuidv1":
+	case "uuidv4", "uuidv7", "uuidv1", "uuidv6", "uuidv8", "snowflake":
 		return fmt.Sprintf(`\set offset random(0, :num_records - 1)
 SELECT * FROM (
-  SELECT id FROM %s OFFSET :offset LIMIT 1
-) AS random_id, %s
-WHERE %s.id = random_id.id;`, tableName, tableName, tableName)
+  SELECT %[2]s FROM %[1]s OFFSET :offset LIMIT 1
+) AS random_id, %[1]s
+WHERE %[1]s.%[2]s = random_id.%[2]s;`, tableName, l.idColumn())
 
-	case "ulid", "ulid_monotonic":
+	case "ulid", "ulid_monotonic", "ksuid":
 		return fmt.Sprintf(`\set offset random(0, :num_records - 1)
 SELECT * FROM (
-  SELECT id FROM %s OFFSET :offset LIMIT 1
-) AS random_id, %s
-WHERE %s.id = random_id.id;`, tableName, tableName, tableName)
+  SELECT %[2]s FROM %[1]s OFFSET :offset LIMIT 1
+) AS random_id, %[1]s
+WHERE %[1]s.%[2]s = random_id.%[2]s;`, tableName, l.idColumn())
 
 	default:
 		return fmt.Sprintf(`-- Unknown key type: %s`, keyType)
 	}
 }
 
-func GenerateUpdateScript(keyType, tableName string) string {
+// GenerateRangeScanScript reads the :range_size rows following a random start id in key
+// order. Start ids come from startsTable, numbered 1..:num_starts, so picking one does
+// not scan the benchmark table itself and its block counters only see the range scan.
+func (l Layout) GenerateRangeScanScript(tableName, startsTable string) string {
+	return fmt.Sprintf(`\set n random(1, :num_starts)
+SELECT * FROM %[1]s
+WHERE %[3]s >= (SELECT %[3]s FROM %[2]s WHERE n = :n)
+ORDER BY %[3]s
+LIMIT :range_size;`, tableName, startsTable, l.idColumn())
+}
+
+// GenerateLookupScript reads one row by primary key, its id picked at random from
+// idsTable, numbered 1..:num_ids, so every lookup is an index probe of the benchmark
+// table rather than a walk to a random offset
+func (l Layout) GenerateLookupScript(tableName, idsTable string) string {
+	return fmt.Sprintf(`\set n random(1, :num_ids)
+SELECT * FROM %[1]s WHERE %[3]s = (SELECT %[3]s FROM %[2]s WHERE n = :n);`, tableName, idsTable, l.idColumn())
+}
+
+func (l Layout) GenerateUpdateScript(keyType, tableName string) string {
 	switch keyType {
 	case "bigserial":
 		return fmt.Sprintf(`\set id random(1, :num_records)
-UPDATE %s SET data = 'updated_' || :client_id WHERE id = :id;`, tableName)
+UPDATE %[1]s SET %[2]s WHERE %[3]s = :id;`, tableName, l.DataColumn.updateSet(l.idColumn()), l.idColumn())
 
-	case "uuidv4", "uuidv7", "uuidv1":
+	case "uuidv4", "uuidv7", "uuidv1", "uuidv6", "uuidv8", "snowflake":
 		return fmt.Sprintf(`\set offset random(0, :num_records - 1)
-UPDATE %s SET data = 'updated_' || :client_id
-WHERE id = (SELECT id FROM %s OFFSET :offset LIMIT 1);`, tableName, tableName)
+UPDATE %[1]s SET %[2]s
+WHERE %[3]s = (SELECT %[3]s FROM %[1]s OFFSET :offset LIMIT 1);`, tableName, l.DataColumn.updateSet(l.idColumn()), l.idColumn())
 
-	case "ulid", "ulid_monotonic":
+	case "ulid", "ulid_monotonic", "ksuid":
 		return fmt.Sprintf(`\set offset random(0, :num_records - 1)
-UPDATE %s SET data = 'updated_' || :client_id
-WHERE id = (SELECT id FROM %s OFFSET :offset LIMIT 1);`, tableName, tableName)
+UPDATE %[1]s SET %[2]s
+WHERE %[3]s = (SELECT %[3]s FROM %[1]s OFFSET :offset LIMIT 1);`, tableName, l.DataColumn.updateSet(l.idColumn()), l.idColumn())
 
 	default:
 		return fmt.Sprintf(`-- Unknown key type: %s`, keyType)
 	}
 }
 
-// pgbench doesn't support weighted random selection, so we use conditional logic
-func GenerateMixedScript(keyType, tableName string, insertWeight, readWeight, updateWeight int) string {
-	if insertWeight+readWeight+updateWeight != 100 {
-		return fmt.Sprintf(`-- Error: Weights must sum to 100 (got %d)`, insertWeight+readWeight+updateWeight)
+// GenerateUpsertScript upserts one row with INSERT ... ON CONFLICT DO UPDATE. With
+// probability :conflict_ratio percent the row reuses an existing id (picked like the
+// update script does), so the conflict path updates it; otherwise a new id is generated
+// and the row is inserted. Expects :num_records and :conflict_ratio to be set.
+func (l Layout) GenerateUpsertScript(keyType, tableName string) string {
+	set := fmt.Sprintf("%[1]s = EXCLUDED.%[1]s", l.idColumn())
+	columns, values := []string{l.idColumn()}, []string{}
+	if !l.DataColumn.None() {
+		set = "data = EXCLUDED.data"
+		columns = append(columns, "data")
+		values = append(values, l.DataColumn.insertValue())
 	}
+	onConflict := fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", l.idColumn(), set)
 
-	insertScript := GenerateInsertScript(keyType, tableName)
-	selectScript := GenerateSelectScript(keyType, tableName)
-	updateScript := GenerateUpdateScript(keyType, tableName)
-
-	readThreshold := insertWeight
-	updateThreshold := insertWeight + readWeight
-
-	return fmt.Sprintf(`\set rand random(1, 100)
-\set insert_threshold %d
-\set update_threshold %d
+	// New rows: the generated id, or BIGSERIAL's column default
+	var insertNew string
+	if keyType == "bigserial" {
+		if len(values) == 0 {
+			insertNew = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES %s;", tableName, onConflict)
+		} else {
+			insertNew = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s;", tableName, strings.Join(columns[1:], ", "), strings.Join(values, ", "), onConflict)
+		}
+	} else {
+		idExpr, ok := l.idExpression(keyType)
+		if !ok {
+			return fmt.Sprintf(`-- Unknown key type: %s`, keyType)
+		}
+		insertNew = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s;", tableName, strings.Join(columns, ", "), strings.Join(append([]string{idExpr}, values...), ", "), onConflict)
+	}
 
--- Execute based on random value
--- 1-%d: INSERT
--- %d-%d: SELECT
--- %d-100: UPDATE
+	// Existing rows: an id already in the table
+	var insertExisting string
+	if keyType == "bigserial" {
+		insertExisting = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s;", tableName, strings.Join(columns, ", "), strings.Join(append([]string{":id"}, values...), ", "), onConflict)
+	} else {
+		insertExisting = fmt.Sprintf("INSERT INTO %[1]s (%[2]s) SELECT %[3]s FROM %[1]s OFFSET :offset LIMIT 1 %[4]s;",
+			tableName, strings.Join(columns, ", "), strings.Join(append([]string{l.idColumn()}, values...), ", "), onConflict)
+	}
 
-\if :rand <= :insert_threshold
-  %s
-\elif :rand <= :update_threshold
-  %s
+	return fmt.Sprintf(`\set conflict random(1, 100)
+\set id random(1, :num_records)
+\set offset random(0, :num_records - 1)
+\if :conflict <= :conflict_ratio
+%s
 \else
-  %s
-\endif`, readThreshold, updateThreshold, readThreshold, readThreshold+1, updateThreshold, updateThreshold+1, insertScript, selectScript, updateScript)
+%s
+\endif`, insertExisting, insertNew)
 }
 
 // pgbench executes one SQL statement per transaction by default
-func GenerateMultipleInserts(keyType, tableName string, batchSize int) string {
+func (l Layout) GenerateMultipleInserts(keyType, tableName string, batchSize int) string {
+	return GenerateBatch(l.GenerateInsertScript(keyType, tableName), batchSize)
+}
+
+// GenerateBatch repeats statement batchSize times inside a single transaction
+func GenerateBatch(statement string, batchSize int) string {
 	if batchSize <= 1 {
-		return GenerateInsertScript(keyType, tableName)
+		return statement
 	}
 
 	script := "BEGIN;\n"
 	for i := 0; i < batchSize; i++ {
-		script += GenerateInsertScript(keyType, tableName) + "\n"
+		script += statement + "\n"
 	}
 	script += "COMMIT;"
 
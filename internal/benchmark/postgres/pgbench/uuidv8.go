@@ -0,0 +1,15 @@
+package pgbench
+
+import "fmt"
+
+// MaxUUIDv8TimeBits is the width of the millisecond Unix timestamp at the front of a
+// uuidv8 id, as in UUIDv7; with all of it kept, uuidv8 ids order like UUIDv7
+const MaxUUIDv8TimeBits = 48
+
+// ValidateUUIDv8TimeBits checks a -uuidv8-time-bits value
+func ValidateUUIDv8TimeBits(bits int) error {
+	if bits < 0 || bits > MaxUUIDv8TimeBits {
+		return fmt.Errorf("%d is outside 0..%d", bits, MaxUUIDv8TimeBits)
+	}
+	return nil
+}
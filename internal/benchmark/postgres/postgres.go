@@ -2,19 +2,331 @@ package postgres
 
 import (
 	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
 )
 
+// Options holds benchmark-wide settings applied to every benchmarker
+type Options struct {
+	PgbenchWarmup int     // Throwaway pgbench transactions per client before each measured run
+	Rate          float64 // pgbench -R target transactions/sec across all clients, 0 = unthrottled
+	LatencyLimit  float64 // pgbench --latency-limit in ms, only meaningful with Rate
+	PgbenchLogDir string  // Directory for raw pgbench stdout/stderr logs, empty = disabled
+	QueryMode     string  // pgbench -M protocol (simple, extended, prepared), empty = simple
+	Seed          int64   // pgbench --random-seed for every measured run, 0 = unseeded
+	Duration      int     // Seconds each time-bound run lasts (pgbench -T), 0 = transaction-count bound
+	ViaPgBouncer  bool    // Route pgbench and the benchmark's own connection through PgBouncer
+
+	IncludeDDLTiming bool // Report extension/table setup time on results
+	ExtraIndexes     int  // Secondary indexes created alongside the primary key
+	Preload          int  // Rows loaded before insert-performance's measured inserts, 0 = start empty
+	VerifyData       bool // Check sampled rows and ids against the generation pattern after each load
+
+	CompareQueryModes bool // Repeat the read phase under simple and prepared protocols
+
+	Strict bool // Fail instead of warning when I/O stats, page splits or buffer ratios cannot be collected
+
+	DisableAutovacuum bool // Turn autovacuum off for the benchmark table, isolating the workload's direct cost
+
+	MeasureBloat bool // Scan the heap with pgstattuple after each workload, and VACUUM after updates
+
+	Remeasure int // Times insert-performance measures the loaded table, > 1 reports measurement-only CV
+
+	SizeSampling int // Milliseconds between table and index size samples during insert-performance's inserts, 0 = off
+
+	IDColumn      string                // Name of the primary key column, empty = id
+	DataColumn    pgbench.DataColumn    // Type and nullability of the data column, or an id-only table
+	TimestampSkew pgbench.TimestampSkew // Random backward clock jumps injected into uuidv7 generation
+	InsertOrder   string                // Timestamp progression of uuidv7 ids: forward, reverse or random; empty = forward
+	InsertMode    string                // How insert-performance loads rows: pgbench, batch or copy; empty = pgbench
+
+	UUIDv8TimeBits int // High bits of uuidv8 ids holding the millisecond timestamp, 0..48
+
+	RecordPath string              // Operation log the inserted ids are appended to, empty = disabled
+	ReplayOps  map[string][]string // Recorded ids per key type inserted instead of generated ones, nil = generate
+}
+
 type PostgresBenchmarker struct {
+	opts      Options
+	scenario  string // Scenario name used to label pgbench logs
 	db        *sql.DB
 	keyType   string
 	tableName string
 	indexName string
 	startLSN  string // WAL LSN at start of insert operation
 	endLSN    string // WAL LSN at end of insert operation
+
+	expectedRows int64 // Rows the insert phases should have produced so far
+	jsonbPayload bool  // Add a GIN-indexed JSONB payload column to the table
+	returning    bool  // Inserts return the generated id (INSERT ... RETURNING id)
+	timed        bool  // Runs last Options.Duration instead of a transaction count
+	replay       bool  // Inserts into the current table take recorded ids, see loadReplay
+
+	lastPgbench *pgbench.PgbenchResult    // Parsed output of the most recent pgbench run
+	lastLatency map[float64]time.Duration // Latency percentiles of the most recent update or upsert run
+	lastOps     int                       // Operations the most recent insert, read or update run completed
+	lastReadIO  *BlockIO                  // Block counters of the most recent measured read run, nil if unknown
+
+	setup benchmark.SetupTiming // Time spent in Connect and the latest CreateTable
+
+	scripts map[string]bool // Container paths of copied pgbench scripts, removed on Close
+
+	ulidTypes map[string]string // Column type per ULID key type, from its generator's return type
+
+	lookupIDs int // Ids sampled by PrepareLookups, 0 = none
+
+	sizeSampler *sizeSampler // Table and index size sampling started by StartSizeSampler
+}
+
+func New(opts Options) *PostgresBenchmarker {
+	return &PostgresBenchmarker{opts: opts}
+}
+
+// execConfig builds the pgbench configuration for a measured run in the pgbench
+// container against the benchmark server, applying the benchmark-wide rate limit
+func (p *PostgresBenchmarker) execConfig(connections, transactions int, scriptPath string) pgbench.ExecutorConfig {
+	host, port := p.pgbenchHost()
+	cfg := pgbench.ExecutorConfig{
+		ContainerName: Connection.Container,
+		User:          Connection.User,
+		Password:      Connection.Password,
+		DBName:        Connection.DBName,
+		SSLMode:       Connection.SSLMode,
+		Connections:   connections,
+		Transactions:  transactions,
+		ScriptPath:    scriptPath,
+		Rate:          p.opts.Rate,
+		LatencyLimit:  p.opts.LatencyLimit,
+		LogDir:        p.opts.PgbenchLogDir,
+		LogName:       p.logName(scriptPath),
+		QueryMode:     p.opts.QueryMode,
+		Host:          host,
+		Port:          port,
+		RandomSeed:    p.opts.Seed,
+	}
+	if p.timeBound() {
+		cfg.Transactions = 0
+		cfg.Duration = p.opts.Duration
+	}
+	return cfg
+}
+
+// SetTimeBound makes subsequent runs last Options.Duration seconds, if set, instead of
+// their transaction count; loads that must reach a fixed size leave it off
+func (p *PostgresBenchmarker) SetTimeBound(enabled bool) {
+	p.timed = enabled
+}
+
+// timeBound reports whether runs are currently bounded by Options.Duration
+func (p *PostgresBenchmarker) timeBound() bool {
+	return p.timed && p.opts.Duration > 0
+}
+
+// completeOps records the operations a run got through as LastOps: the transactions
+// pgbench processed times those in each, so a count-bound run reports the rows its
+// rounded transaction count really inserted rather than the requested number. Without
+// parsed output it falls back to requested, the operations the run was scheduled for,
+// which a time-bound run does not have.
+func (p *PostgresBenchmarker) completeOps(requested, perTransaction int, parsed *pgbench.PgbenchResult) (int, error) {
+	switch {
+	case parsed != nil:
+		p.lastOps = parsed.Transactions * max(perTransaction, 1)
+	case p.timeBound():
+		return 0, fmt.Errorf("time-bound run: pgbench output could not be parsed, completed operations unknown")
+	default:
+		p.lastOps = requested
+	}
+	return p.lastOps, nil
+}
+
+// idColumn returns the name of the table's primary key column
+func (p *PostgresBenchmarker) idColumn() string {
+	if p.opts.IDColumn == "" {
+		return "id"
+	}
+	return p.opts.IDColumn
+}
+
+// layout returns the table layout and id generation the pgbench scripts follow
+func (p *PostgresBenchmarker) layout() pgbench.Layout {
+	return pgbench.Layout{
+		IDColumn:       p.opts.IDColumn,
+		DataColumn:     p.opts.DataColumn,
+		TimestampSkew:  p.opts.TimestampSkew,
+		InsertOrder:    p.opts.InsertOrder,
+		UUIDv8TimeBits: p.opts.UUIDv8TimeBits,
+		Replay:         p.replay,
+	}
+}
+
+// latencyPercentiles computes the configured percentiles from the run's per-transaction
+// log, falling back to any percentiles pgbench printed itself (parsed may be nil)
+func latencyPercentiles(execResult *pgbench.ExecuteResult, parsed *pgbench.PgbenchResult) map[float64]time.Duration {
+	if len(execResult.Latencies) > 0 {
+		return benchmark.CalculatePercentiles(execResult.Latencies, benchmark.Percentiles)
+	}
+	if parsed == nil {
+		return nil
+	}
+	return parsed.Percentiles
+}
+
+// copyScript copies a pgbench script into the container's /tmp under its fixed name,
+// so re-runs overwrite it, and records it for removal on Close
+func (p *PostgresBenchmarker) copyScript(script, scriptName string) (string, error) {
+	containerPath, err := pgbench.CopyScriptToContainer(Connection.Container, script, scriptName)
+	if err != nil {
+		return "", err
+	}
+
+	if p.scripts == nil {
+		p.scripts = make(map[string]bool)
+	}
+	p.scripts[containerPath] = true
+
+	return containerPath, nil
+}
+
+// removeScripts deletes every script copied by this benchmarker from the container
+func (p *PostgresBenchmarker) removeScripts() error {
+	paths := make([]string, 0, len(p.scripts))
+	for path := range p.scripts {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	if err := pgbench.RemoveScriptsFromContainer(Connection.Container, paths...); err != nil {
+		return err
+	}
+	p.scripts = nil
+	return nil
+}
+
+// SetQueryMode switches the pgbench query protocol for subsequent runs
+func (p *PostgresBenchmarker) SetQueryMode(mode string) {
+	p.opts.QueryMode = mode
+}
+
+// SetupTiming returns the DDL/setup time of Connect and the latest CreateTable, or a
+// zero value unless Options.IncludeDDLTiming is set
+func (p *PostgresBenchmarker) SetupTiming() benchmark.SetupTiming {
+	if !p.opts.IncludeDDLTiming {
+		return benchmark.SetupTiming{}
+	}
+	return p.setup
+}
+
+// PKIndexName returns the name of the primary key index of the current table
+func (p *PostgresBenchmarker) PKIndexName() string {
+	return p.indexName
+}
+
+// SetScenario labels the pgbench logs written by this benchmarker
+func (p *PostgresBenchmarker) SetScenario(scenario string) {
+	p.scenario = scenario
+}
+
+// logName names a pgbench log after the scenario and the script, whose name carries
+// the operation and key type (e.g. "read-after-fragmentation_select_uuidv4")
+func (p *PostgresBenchmarker) logName(scriptPath string) string {
+	script := strings.TrimSuffix(filepath.Base(scriptPath), filepath.Ext(scriptPath))
+	if p.scenario == "" {
+		return script
+	}
+	return p.scenario + "_" + script
+}
+
+// reportThrottling prints the latency at the configured offered load, and how many
+// transactions pgbench skipped or completed late under the rate and latency limit
+func (p *PostgresBenchmarker) reportThrottling(parsed *pgbench.PgbenchResult) {
+	if p.opts.Rate <= 0 {
+		return
+	}
+
+	fmt.Printf("Rate limit %.0f tps: achieved %.0f tps, latency avg %s (schedule lag avg %s, max %s)\n",
+		p.opts.Rate, parsed.TPS, parsed.LatencyAvg.Round(time.Microsecond), parsed.ScheduleLagAvg.Round(time.Microsecond), parsed.ScheduleLagMax.Round(time.Microsecond))
+	fmt.Printf("Rate limit %.0f tps: %d skipped, %d over latency limit\n", p.opts.Rate, parsed.Skipped, parsed.LatencyLimitExceeded)
+	if parsed.Skipped > 0 {
+		fmt.Printf("Warning: pgbench skipped %d transactions that could not start on schedule\n", parsed.Skipped)
+	}
+}
+
+// minProcessedFraction is the share of requested transactions pgbench must process
+// before a run counts as complete
+const minProcessedFraction = 0.99
+
+// checkTransactions warns when pgbench processed significantly fewer transactions than
+// requested, e.g. because clients aborted, so throughput would describe a partial run
+func (p *PostgresBenchmarker) checkTransactions(parsed *pgbench.PgbenchResult) {
+	if parsed.ExpectedTransactions == 0 {
+		return
+	}
+
+	if float64(parsed.Transactions) < float64(parsed.ExpectedTransactions)*minProcessedFraction {
+		fmt.Printf("Warning: pgbench processed %d of %d requested transactions (%.1f%%)\n",
+			parsed.Transactions, parsed.ExpectedTransactions, float64(parsed.Transactions)/float64(parsed.ExpectedTransactions)*100)
+	}
+}
+
+// warmup runs the configured number of throwaway transactions against the script in
+// cfg so the measured run starts with warm caches
+func (p *PostgresBenchmarker) warmup(cfg pgbench.ExecutorConfig) error {
+	if p.opts.PgbenchWarmup <= 0 {
+		return nil
+	}
+
+	fmt.Printf("Warming up (%d transactions per connection)...\n", p.opts.PgbenchWarmup)
+	if err := pgbench.Warmup(cfg, p.opts.PgbenchWarmup); err != nil {
+		return fmt.Errorf("warmup: %w", err)
+	}
+
+	return nil
+}
+
+// LastPgbenchResult returns the parsed output of the most recent insert, read or update
+// pgbench run, or nil if it could not be parsed
+func (p *PostgresBenchmarker) LastPgbenchResult() *pgbench.PgbenchResult {
+	return p.lastPgbench
+}
+
+// LastOps returns the operations the most recent insert, read or update run completed:
+// the requested count, or what a time-bound run got through
+func (p *PostgresBenchmarker) LastOps() int {
+	return p.lastOps
+}
+
+// LastReadIO returns the table's block reads and hits during the measured pgbench run of
+// the most recent ReadRecordsPgbench, excluding warmup, or nil if they could not be read
+func (p *PostgresBenchmarker) LastReadIO() *BlockIO {
+	return p.lastReadIO
+}
+
+// LastLatency returns the latency percentiles of the most recent single-connection update
+// or upsert run, or nil if none were collected; reads return theirs with the result
+func (p *PostgresBenchmarker) LastLatency() map[float64]time.Duration {
+	return p.lastLatency
+}
+
+// EnableJSONBPayload makes CreateTable add a JSONB payload column with a GIN index,
+// and the insert paths populate it with small JSON documents
+func (p *PostgresBenchmarker) EnableJSONBPayload() {
+	p.jsonbPayload = true
+}
+
+// SetReturning makes subsequent inserts return the generated id to pgbench
+func (p *PostgresBenchmarker) SetReturning(enabled bool) {
+	p.returning = enabled
 }
 
-func New() *PostgresBenchmarker {
-	return &PostgresBenchmarker{}
+// GinIndexName returns the name of the payload GIN index created in JSONB payload mode
+func (p *PostgresBenchmarker) GinIndexName() string {
+	return fmt.Sprintf("%s_payload_gin", p.tableName)
 }
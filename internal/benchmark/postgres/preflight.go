@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// requirement is something the server must provide before a key type can run: one of
+// the functions must already exist, or the extension must be installable
+type requirement struct {
+	name      string   // Shown in the preflight report
+	functions []string // Zero-argument functions that satisfy the requirement on their own
+	extension string   // Extension providing the requirement, checked in pg_available_extensions
+	preload   string   // Library that must also be in shared_preload_libraries
+	hint      string   // How to get it
+}
+
+// commonRequirements are needed by every key type: Connect creates them for the metrics
+var commonRequirements = []requirement{
+	{name: "pgstattuple", extension: "pgstattuple", hint: "contrib module shipped with the official postgres images"},
+	{name: "pg_walinspect", extension: "pg_walinspect", hint: "contrib module of PostgreSQL 15+, used to count page splits"},
+}
+
+// pgxULIDHint explains how to get the ULID extension the benchmark image builds
+const pgxULIDHint = "build the benchmark image (docker compose -f docker/docker-compose.postgres.yml build), which compiles pgx_ulid from github.com/pksunkara/pgx_ulid"
+
+// keyTypeRequirements lists what each key type's id generation needs beyond core PostgreSQL
+var keyTypeRequirements = map[string][]requirement{
+	"uuidv7": {{
+		name:      "uuidv7()",
+		functions: []string{"uuidv7", "uuid_generate_v7"},
+		extension: "pg_uuidv7",
+		hint:      "native in PostgreSQL 18+, otherwise install the pg_uuidv7 extension (github.com/fboulnois/pg_uuidv7)",
+	}},
+	"uuidv1": {{
+		name:      "uuid_generate_v1()",
+		functions: []string{"uuid_generate_v1"},
+		extension: "uuid-ossp",
+		hint:      "uuid-ossp contrib module, shipped with the official postgres images",
+	}},
+	"uuidv6": {{
+		name:      "gen_uuidv6()",
+		functions: []string{"gen_uuidv6", "uuid_generate_v1"},
+		extension: "uuid-ossp",
+		hint:      "created at connect time from uuid-ossp's uuid_generate_v1(), shipped with the official postgres images",
+	}},
+	"ulid": {{
+		name:      "gen_ulid()",
+		functions: append([]string{"gen_ulid"}, ulidAlternatives["gen_ulid"]...),
+		extension: "pgx_ulid",
+		hint:      pgxULIDHint,
+	}},
+	"ulid_monotonic": {{
+		name:      "gen_monotonic_ulid()",
+		functions: append([]string{"gen_monotonic_ulid"}, ulidAlternatives["gen_monotonic_ulid"]...),
+		extension: "pgx_ulid",
+		preload:   "pgx_ulid",
+		hint:      pgxULIDHint + "; monotonic ULIDs also need shared_preload_libraries=pgx_ulid, set by the compose file",
+	}},
+}
+
+// Preflight checks, before any workload starts, that the server provides every extension
+// and function the given key types need, returning one error that lists everything
+// missing and how to get it
+func Preflight(keyTypes []string) error {
+	connStr := Connection.connString(Connection.Port)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	var preloaded string
+	if err := db.QueryRow("SELECT current_setting('shared_preload_libraries')").Scan(&preloaded); err != nil {
+		return fmt.Errorf("query shared_preload_libraries: %w", err)
+	}
+
+	var missing []string
+	check := func(req requirement, users string) error {
+		ok, err := requirementMet(db, req, preloaded)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			missing = append(missing, fmt.Sprintf("  %s (needed by %s): %s", req.name, users, req.hint))
+		}
+		return nil
+	}
+
+	for _, req := range commonRequirements {
+		if err := check(req, "all key types"); err != nil {
+			return err
+		}
+	}
+	for _, keyType := range keyTypes {
+		for _, req := range keyTypeRequirements[keyType] {
+			if err := check(req, keyType); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing server requirements:\n%s", strings.Join(missing, "\n"))
+	}
+	return nil
+}
+
+// requirementMet reports whether one of req's functions exists, or its extension is
+// installable (and preloaded, if it must be)
+func requirementMet(db *sql.DB, req requirement, preloaded string) (bool, error) {
+	for _, function := range req.functions {
+		var exists bool
+		err := db.QueryRow(`
+			SELECT EXISTS (
+				SELECT 1 FROM pg_proc
+				WHERE proname = $1 AND pronargs = 0 AND pg_function_is_visible(oid)
+			)
+		`, function).Scan(&exists)
+		if err != nil {
+			return false, fmt.Errorf("query pg_proc for %s: %w", function, err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+
+	var available bool
+	err := db.QueryRow("SELECT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = $1)", req.extension).Scan(&available)
+	if err != nil {
+		return false, fmt.Errorf("query pg_available_extensions for %s: %w", req.extension, err)
+	}
+	if !available {
+		return false, nil
+	}
+
+	if req.preload != "" {
+		libraries := strings.Split(preloaded, ",")
+		for i := range libraries {
+			libraries[i] = strings.TrimSpace(libraries[i])
+		}
+		return slices.Contains(libraries, req.preload), nil
+	}
+	return true, nil
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
+)
+
+// rangeStartsTable names the table of sampled range scan start ids; the bench_ prefix
+// makes ResetSchema drop it with the benchmark table
+func (p *PostgresBenchmarker) rangeStartsTable() string {
+	return p.tableName + "_range_starts"
+}
+
+// prepareRangeStarts samples up to n ids of the loaded table into the start id table,
+// returning how many it holds
+func (p *PostgresBenchmarker) prepareRangeStarts(n int) (int, error) {
+	count, err := p.sampleIDs(p.rangeStartsTable(), n)
+	if err != nil {
+		return 0, fmt.Errorf("prepare range starts: %w", err)
+	}
+	return count, nil
+}
+
+// sampleIDs samples up to n ids of the loaded table, in random order, into table
+// numbered from 1, returning how many it holds. Picking a sampled id by number costs
+// one primary key lookup in the small sample table instead of a walk of the benchmark
+// table. The sampling itself scans the whole table, so it must run before any counters
+// of the measured phase are captured.
+func (p *PostgresBenchmarker) sampleIDs(table string, n int) (int, error) {
+	sample := pq.QuoteIdentifier(table)
+	statements := []string{
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", sample),
+		fmt.Sprintf(`CREATE TABLE %[1]s AS
+			SELECT row_number() OVER () AS n, %[2]s
+			FROM (SELECT %[2]s FROM %[3]s ORDER BY random() LIMIT %[4]d) AS sampled`, sample, p.idColumn(), p.tableName, n),
+		fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (n)", sample),
+		fmt.Sprintf("ANALYZE %s", sample),
+	}
+	for _, statement := range statements {
+		if _, err := p.db.Exec(statement); err != nil {
+			return 0, fmt.Errorf("sample ids: %w", err)
+		}
+	}
+
+	var count int
+	if err := p.db.QueryRow(fmt.Sprintf("SELECT count(*) FROM %s", sample)).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count sampled ids: %w", err)
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("table %s is empty, nothing to sample", p.tableName)
+	}
+	return count, nil
+}
+
+// RangeScanPgbench runs numScans range scans over one connection, each reading the
+// rangeSize rows that follow a random existing id in key order. Alongside the run it
+// returns the benchmark table's read counters accumulated by the measured scans alone.
+func (p *PostgresBenchmarker) RangeScanPgbench(numScans, rangeSize int) (*benchmark.ConcurrentBenchmarkResult, *ScanStats, error) {
+	numStarts, err := p.prepareRangeStarts(numScans)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	script := p.layout().GenerateRangeScanScript(p.tableName, p.rangeStartsTable())
+
+	scriptWithVars := fmt.Sprintf("\\set num_starts %d\n\\set range_size %d\n%s", numStarts, rangeSize, script)
+
+	scriptName := fmt.Sprintf("range_scan_%s.sql", p.keyType)
+	containerPath, err := p.copyScript(scriptWithVars, scriptName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("copy script to container: %w", err)
+	}
+
+	execCfg := p.execConfig(1, numScans, containerPath)
+	execCfg.LogLatencies = true
+
+	if err := p.warmup(execCfg); err != nil {
+		return nil, nil, err
+	}
+
+	// Warmup scans hit the same counters, so measure from after them
+	before, err := p.scanStats()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	startTime := time.Now()
+
+	execResult, err := pgbench.Execute(execCfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execute pgbench: %w", err)
+	}
+
+	if execResult.ExitCode != 0 {
+		return nil, nil, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
+	}
+
+	duration := time.Since(startTime)
+
+	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse pgbench output: %w", err)
+	}
+	p.reportThrottling(parsed)
+	p.checkTransactions(parsed)
+	p.lastPgbench = parsed
+
+	numScans, err = p.completeOps(numScans, 1, parsed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	after, err := p.scanStats()
+	if err != nil {
+		return nil, nil, err
+	}
+	stats := &ScanStats{
+		RowsFetched: after.RowsFetched - before.RowsFetched,
+		BlocksHit:   after.BlocksHit - before.BlocksHit,
+		BlocksRead:  after.BlocksRead - before.BlocksRead,
+	}
+
+	return &benchmark.ConcurrentBenchmarkResult{
+		Duration:     duration,
+		TotalOps:     numScans,
+		Throughput:   parsed.TPS,
+		Latency:      latencyPercentiles(execResult, parsed),
+		SuccessCount: parsed.Transactions,
+		ErrorCount:   numScans - parsed.Transactions,
+
+		InitialConnectionTime:    parsed.InitialConnectionTime,
+		ThroughputIncludingSetup: parsed.TPSIncludingSetup,
+	}, stats, nil
+}
+
+// ScanStats are read counters of the benchmark table
+type ScanStats struct {
+	RowsFetched int64 // Live rows fetched by index scans (idx_tup_fetch)
+	BlocksHit   int64 // Heap and index blocks found in shared buffers
+	BlocksRead  int64 // Heap and index blocks read from outside shared buffers
+}
+
+// scanStats reads the benchmark table's cumulative index scan and block counters from
+// pg_stat_user_tables and pg_statio_user_tables
+func (p *PostgresBenchmarker) scanStats() (*ScanStats, error) {
+	stats := &ScanStats{}
+	err := p.db.QueryRow(`
+		SELECT
+			COALESCE(s.idx_tup_fetch, 0),
+			COALESCE(io.heap_blks_hit, 0) + COALESCE(io.idx_blks_hit, 0),
+			COALESCE(io.heap_blks_read, 0) + COALESCE(io.idx_blks_read, 0)
+		FROM pg_stat_user_tables s
+		JOIN pg_statio_user_tables io USING (relid)
+		WHERE s.relid = $1::regclass
+	`, p.tableName).Scan(&stats.RowsFetched, &stats.BlocksHit, &stats.BlocksRead)
+	if err != nil {
+		return nil, fmt.Errorf("query scan stats: %w", err)
+	}
+	return stats, nil
+}
@@ -8,66 +8,120 @@ import (
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
 )
 
-func (p *PostgresBenchmarker) ReadRecordsPgbench(keyType string, numTotalRecords, numReads int) (time.Duration, error) {
-	script := pgbench.GenerateSelectScript(keyType, p.tableName)
+// ReadRecordsPgbench runs numReads point lookups over one connection, returning the
+// wall-clock duration alongside pgbench's TPS and latency percentiles
+func (p *PostgresBenchmarker) ReadRecordsPgbench(keyType string, numTotalRecords, numReads int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	numTotalRecords, err := p.targetRecords(numTotalRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	script := p.layout().GenerateSelectScript(keyType, p.tableName)
+
+	scriptWithVars := fmt.Sprintf("\\set num_records %d\n%s", numTotalRecords, script)
 
 	scriptName := fmt.Sprintf("select_%s.sql", keyType)
-	containerPath, err := pgbench.CopyScriptToContainer("uuid-bench-postgres", script, scriptName)
+	containerPath, err := p.copyScript(scriptWithVars, scriptName)
 	if err != nil {
-		return 0, fmt.Errorf("copy script to container: %w", err)
+		return nil, fmt.Errorf("copy script to container: %w", err)
 	}
 
-	execCfg := pgbench.ExecutorConfig{
-		ContainerName: "uuid-bench-postgres",
-		Connections:   1,
-		Transactions:  numReads,
-		ScriptPath:    containerPath,
+	execCfg := p.execConfig(1, numReads, containerPath)
+	execCfg.LogLatencies = true
+
+	if err := p.warmup(execCfg); err != nil {
+		return nil, err
 	}
 
-	scriptWithVars := fmt.Sprintf("\\set num_records %d\n%s", numTotalRecords, script)
-	containerPath, err = pgbench.CopyScriptToContainer("uuid-bench-postgres", scriptWithVars, scriptName)
+	// Counted around the measured run only, so warmup reads do not inflate read
+	// amplification
+	p.lastReadIO = nil
+	ioBefore, err := p.blockIO()
 	if err != nil {
-		return 0, fmt.Errorf("copy script with vars to container: %w", err)
+		if p.opts.Strict {
+			return nil, err
+		}
+		fmt.Printf("Warning: Could not measure read amplification: %v\n", err)
 	}
 
 	startTime := time.Now()
 
 	execResult, err := pgbench.Execute(execCfg)
 	if err != nil {
-		return 0, fmt.Errorf("execute pgbench: %w", err)
+		return nil, fmt.Errorf("execute pgbench: %w", err)
 	}
 
 	if execResult.ExitCode != 0 {
-		return 0, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
+		return nil, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
 	}
 
 	duration := time.Since(startTime)
 
-	return duration, nil
+	if ioBefore != nil {
+		ioAfter, err := p.blockIO()
+		if err != nil {
+			if p.opts.Strict {
+				return nil, err
+			}
+			fmt.Printf("Warning: Could not measure read amplification: %v\n", err)
+		} else {
+			p.lastReadIO = ioAfter.Since(ioBefore)
+		}
+	}
+
+	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
+	if err != nil {
+		return nil, fmt.Errorf("parse pgbench output: %w", err)
+	}
+	p.reportThrottling(parsed)
+	p.checkTransactions(parsed)
+	p.lastPgbench = parsed
+
+	numReads, err = p.completeOps(numReads, 1, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &benchmark.ConcurrentBenchmarkResult{
+		Duration:     duration,
+		TotalOps:     numReads,
+		Throughput:   parsed.TPS,
+		Latency:      latencyPercentiles(execResult, parsed),
+		SuccessCount: parsed.Transactions,
+		ErrorCount:   numReads - parsed.Transactions,
+
+		InitialConnectionTime:    parsed.InitialConnectionTime,
+		ThroughputIncludingSetup: parsed.TPSIncludingSetup,
+	}, nil
 }
 
 func (p *PostgresBenchmarker) ReadRecordsPgbenchConcurrent(keyType string, numTotalRecords, numReads, connections int) (*benchmark.ConcurrentBenchmarkResult, error) {
-	script := pgbench.GenerateSelectScript(keyType, p.tableName)
+	numTotalRecords, err := p.targetRecords(numTotalRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	script := p.layout().GenerateSelectScript(keyType, p.tableName)
 
 	scriptWithVars := fmt.Sprintf("\\set num_records %d\n%s", numTotalRecords, script)
 
 	scriptName := fmt.Sprintf("select_%s_concurrent.sql", keyType)
-	containerPath, err := pgbench.CopyScriptToContainer("uuid-bench-postgres", scriptWithVars, scriptName)
+	containerPath, err := p.copyScript(scriptWithVars, scriptName)
 	if err != nil {
 		return nil, fmt.Errorf("copy script to container: %w", err)
 	}
 
 	transactionsPerClient := numReads / connections
 
-	startTime := time.Now()
+	execCfg := p.execConfig(connections, transactionsPerClient, containerPath)
+	execCfg.LogLatencies = true
 
-	execCfg := pgbench.ExecutorConfig{
-		ContainerName: "uuid-bench-postgres",
-		Connections:   connections,
-		Transactions:  transactionsPerClient,
-		ScriptPath:    containerPath,
+	if err := p.warmup(execCfg); err != nil {
+		return nil, err
 	}
 
+	startTime := time.Now()
+
 	execResult, err := pgbench.Execute(execCfg)
 	if err != nil {
 		return nil, fmt.Errorf("execute pgbench: %w", err)
@@ -81,6 +135,14 @@ func (p *PostgresBenchmarker) ReadRecordsPgbenchConcurrent(keyType string, numTo
 	if err != nil {
 		return nil, fmt.Errorf("parse pgbench output: %w", err)
 	}
+	p.reportThrottling(parsed)
+	p.checkTransactions(parsed)
+	p.lastPgbench = parsed
+
+	numReads, err = p.completeOps(numReads, 1, parsed)
+	if err != nil {
+		return nil, err
+	}
 
 	duration := time.Since(startTime)
 
@@ -88,10 +150,11 @@ func (p *PostgresBenchmarker) ReadRecordsPgbenchConcurrent(keyType string, numTo
 		Duration:     duration,
 		TotalOps:     numReads,
 		Throughput:   parsed.TPS,
-		LatencyP50:   parsed.P50,
-		LatencyP95:   parsed.P95,
-		LatencyP99:   parsed.P99,
+		Latency:      latencyPercentiles(execResult, parsed),
 		SuccessCount: parsed.Transactions,
 		ErrorCount:   numReads - parsed.Transactions,
+
+		InitialConnectionTime:    parsed.InitialConnectionTime,
+		ThroughputIncludingSetup: parsed.TPSIncludingSetup,
 	}, nil
 }
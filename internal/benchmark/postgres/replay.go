@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// An operation log lists, per key type, every id an insert run produced in the order
+// the rows were written:
+//
+//	# key_type uuidv4
+//	insert 0b7c...
+//	insert 5e21...
+//
+// Replaying it inserts exactly those ids in that order instead of generating new ones.
+
+// opInsert is the only operation type recorded: read and update targets are drawn by
+// pgbench's random() and are not part of the log
+const opInsert = "insert"
+
+// ReadOpLog loads an operation log into the ids to insert per key type
+func ReadOpLog(path string) (map[string][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open operation log: %w", err)
+	}
+	defer file.Close()
+
+	ops := make(map[string][]string)
+	keyType := ""
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(text, "# key_type "); ok {
+			keyType = strings.TrimSpace(name)
+			ops[keyType] = nil
+			continue
+		}
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		op, id, ok := strings.Cut(text, " ")
+		if !ok || op != opInsert || keyType == "" {
+			return nil, fmt.Errorf("operation log %s line %d: expected %q under a key_type header, got %q", path, line, opInsert+" <id>", text)
+		}
+		ops[keyType] = append(ops[keyType], id)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read operation log: %w", err)
+	}
+
+	return ops, nil
+}
+
+// RecordOps appends the table's ids to the operation log at path, in physical (ctid)
+// order. Without updates or deletes rows are placed in the heap as they are inserted,
+// so this is the insert order; with several connections it is the order the
+// interleaved inserts reached the heap.
+func (p *PostgresBenchmarker) RecordOps(path string) error {
+	rows, err := p.db.Query(fmt.Sprintf("SELECT %s::text FROM %s ORDER BY ctid", p.idColumn(), p.tableName))
+	if err != nil {
+		return fmt.Errorf("query ids: %w", err)
+	}
+	defer rows.Close()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open operation log: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	fmt.Fprintf(writer, "# key_type %s\n", p.keyType)
+
+	count := 0
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("scan id: %w", err)
+		}
+		fmt.Fprintf(writer, "%s %s\n", opInsert, id)
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate ids: %w", err)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("write operation log: %w", err)
+	}
+
+	fmt.Printf("Recorded %d inserts for %s to %s\n", count, p.keyType, path)
+	return nil
+}
+
+// loadReplay stages the ids to replay for the current table and points the insert
+// scripts at them. Each insert claims the next id from the replay_cursor sequence, so
+// the ids are consumed in log order whatever the number of connections.
+func (p *PostgresBenchmarker) loadReplay(keyType string) error {
+	ids, ok := p.opts.ReplayOps[keyType]
+	p.replay = ok
+	if !ok {
+		if p.opts.ReplayOps != nil {
+			fmt.Printf("Warning: operation log has no %s section, generating ids\n", keyType)
+		}
+		return nil
+	}
+	if keyType == "bigserial" {
+		// Column default ids are already deterministic: 1, 2, 3, ...
+		p.replay = false
+		return nil
+	}
+
+	var idType string
+	err := p.db.QueryRow(`
+		SELECT format_type(atttypid, atttypmod) FROM pg_attribute
+		WHERE attrelid = $1::regclass AND attname = $2
+	`, p.tableName, p.idColumn()).Scan(&idType)
+	if err != nil {
+		return fmt.Errorf("look up id type: %w", err)
+	}
+
+	statements := []string{
+		"DROP TABLE IF EXISTS replay_ops",
+		"DROP SEQUENCE IF EXISTS replay_cursor",
+		fmt.Sprintf("CREATE TABLE replay_ops (seq bigint PRIMARY KEY, id %s NOT NULL)", idType),
+		"CREATE SEQUENCE replay_cursor",
+	}
+	for _, statement := range statements {
+		if _, err := p.db.Exec(statement); err != nil {
+			return fmt.Errorf("prepare replay: %w", err)
+		}
+	}
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin replay load: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(pq.CopyIn("replay_ops", "seq", "id"))
+	if err != nil {
+		return fmt.Errorf("prepare replay copy: %w", err)
+	}
+	for i, id := range ids {
+		if _, err := stmt.Exec(int64(i+1), id); err != nil {
+			stmt.Close()
+			return fmt.Errorf("copy replay id %d: %w", i+1, err)
+		}
+	}
+	if _, err := stmt.Exec(); err != nil {
+		stmt.Close()
+		return fmt.Errorf("flush replay copy: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("close replay copy: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit replay load: %w", err)
+	}
+
+	// Statistics and the visibility map keep each lookup a cheap primary key probe
+	if _, err := p.db.Exec("VACUUM ANALYZE replay_ops"); err != nil {
+		return fmt.Errorf("vacuum replay table: %w", err)
+	}
+
+	fmt.Printf("Replaying %d recorded %s inserts\n", len(ids), keyType)
+	return nil
+}
+
+// checkReplayLength fails when the operation log holds fewer ids than a run inserts
+func (p *PostgresBenchmarker) checkReplayLength(rows int64) error {
+	ids, ok := p.opts.ReplayOps[p.keyType]
+	if !ok || p.keyType == "bigserial" {
+		return nil
+	}
+	if int64(len(ids)) < rows {
+		return fmt.Errorf("operation log has %d %s inserts but this run performs %d (including warmup)", len(ids), p.keyType, rows)
+	}
+	return nil
+}
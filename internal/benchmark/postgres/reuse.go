@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os/exec"
+
+	"github.com/lib/pq"
+)
+
+// ResetSchema drops every benchmark table and the helper objects scenarios leave
+// behind (replay ids, the reverse insert order sequence), so the next key type starts
+// from the same empty schema a fresh container has. Generator functions are kept; they
+// are recreated idempotently on connect.
+func (p *PostgresBenchmarker) ResetSchema() error {
+	rows, err := p.db.Query(`
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = current_schema() AND (tablename LIKE 'bench\_%' OR tablename = 'replay_ops')
+	`)
+	if err != nil {
+		return fmt.Errorf("list benchmark tables: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan benchmark table: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("list benchmark tables: %w", err)
+	}
+
+	statements := []string{"DROP SEQUENCE IF EXISTS replay_cursor, insert_order_seq"}
+	for _, table := range tables {
+		statements = append(statements, "DROP TABLE IF EXISTS "+pq.QuoteIdentifier(table)+" CASCADE")
+	}
+	for _, statement := range statements {
+		if _, err := p.db.Exec(statement); err != nil {
+			return fmt.Errorf("reset schema: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DropCaches brings a reused server close to a freshly started one: a checkpoint writes
+// back everything the previous key type dirtied, shared_buffers is emptied with
+// pg_buffercache_evict_all() (PostgreSQL 18; skipped with a warning where missing),
+// cumulative statistics are reset and DISCARD ALL drops session state. The kernel page
+// cache is outside the container's reach and stays warm.
+func (p *PostgresBenchmarker) DropCaches() error {
+	if err := p.Checkpoint(); err != nil {
+		return err
+	}
+
+	if _, err := p.db.Exec("CREATE EXTENSION IF NOT EXISTS pg_buffercache"); err != nil {
+		return fmt.Errorf("enable pg_buffercache extension: %w", err)
+	}
+	if _, err := p.db.Exec("SELECT pg_buffercache_evict_all()"); err != nil {
+		fmt.Printf("Warning: Could not evict shared buffers (needs pg_buffercache from PostgreSQL 18): %v\n", err)
+	}
+
+	for _, statement := range []string{
+		"SELECT pg_stat_reset()",
+		"SELECT pg_stat_reset_shared('bgwriter')",
+		"SELECT pg_stat_reset_shared('wal')",
+		"DISCARD ALL",
+	} {
+		if _, err := p.db.Exec(statement); err != nil {
+			return fmt.Errorf("drop caches: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// resetTuning reverts every ALTER SYSTEM setting a previous scenario applied, restarting
+// the container when one of them only takes effect at server start (e.g. shared_buffers)
+func resetTuning(db *sql.DB) error {
+	var tuned int
+	if err := db.QueryRow("SELECT count(*) FROM pg_file_settings WHERE sourcefile LIKE '%postgresql.auto.conf'").Scan(&tuned); err != nil {
+		return fmt.Errorf("list tuning settings: %w", err)
+	}
+	if tuned == 0 {
+		return nil
+	}
+
+	if _, err := db.Exec("ALTER SYSTEM RESET ALL"); err != nil {
+		return fmt.Errorf("reset tuning: %w", err)
+	}
+	if _, err := db.Exec("SELECT pg_reload_conf()"); err != nil {
+		return fmt.Errorf("reload configuration: %w", err)
+	}
+
+	var needsRestart bool
+	if err := db.QueryRow("SELECT count(*) > 0 FROM pg_settings WHERE pending_restart").Scan(&needsRestart); err != nil {
+		return fmt.Errorf("check pending restart: %w", err)
+	}
+	if !needsRestart {
+		return nil
+	}
+
+	fmt.Println("Restarting PostgreSQL to revert tuning...")
+	cmd := exec.Command("docker", "restart", Connection.Container)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("restart container: %w (stderr: %s)", err, stderr.String())
+	}
+	return WaitForReady()
+}
+
+// ResetForReuse prepares a kept container for the next key type without recreating it:
+// the previous scenario's tuning is reverted, then ResetSchema and DropCaches run
+func ResetForReuse() error {
+	connStr := Connection.connString(Connection.Port)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	if err := resetTuning(db); err != nil {
+		db.Close()
+		return err
+	}
+	db.Close()
+
+	// A restart invalidates the first connection, so schema and caches use a new one
+	db, err = sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	p := &PostgresBenchmarker{db: db}
+	defer p.Close()
+
+	if err := p.ResetSchema(); err != nil {
+		return err
+	}
+	return p.DropCaches()
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+)
+
+// sizeSampler polls the benchmark table's heap and index sizes until stopped
+type sizeSampler struct {
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	samples []benchmark.SizeSample
+}
+
+// StartSizeSampler starts a goroutine sampling pg_table_size and pg_indexes_size of
+// the current table every intervalMs milliseconds, from a first sample taken now until
+// StopSizeSampler
+func (p *PostgresBenchmarker) StartSizeSampler(intervalMs int) error {
+	if intervalMs <= 0 {
+		return fmt.Errorf("size sampling interval must be positive, got %dms", intervalMs)
+	}
+
+	start := time.Now()
+	first, err := p.sampleSize(start)
+	if err != nil {
+		return err
+	}
+
+	s := &sizeSampler{
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+		samples: []benchmark.SizeSample{first},
+	}
+	p.sizeSampler = s
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(time.Duration(intervalMs) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				// A failed poll leaves a gap in the series rather than ending it
+				if sample, err := p.sampleSize(start); err == nil {
+					s.samples = append(s.samples, sample)
+				}
+			case <-s.stop:
+				if sample, err := p.sampleSize(start); err == nil {
+					s.samples = append(s.samples, sample)
+				}
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StopSizeSampler ends sampling, taking a last sample, and returns the series. It
+// returns nil if no sampler was started, and is safe to call more than once.
+func (p *PostgresBenchmarker) StopSizeSampler() []benchmark.SizeSample {
+	s := p.sizeSampler
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+
+	return s.samples
+}
+
+// sampleSize reads the table's current heap and index sizes
+func (p *PostgresBenchmarker) sampleSize(start time.Time) (benchmark.SizeSample, error) {
+	sample := benchmark.SizeSample{}
+	err := p.db.QueryRow("SELECT pg_table_size($1::regclass), pg_indexes_size($1::regclass)", p.tableName).
+		Scan(&sample.TableSize, &sample.IndexSize)
+	if err != nil {
+		return sample, fmt.Errorf("sample table size: %w", err)
+	}
+	sample.Elapsed = time.Since(start)
+	return sample, nil
+}
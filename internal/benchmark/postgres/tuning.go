@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+// LoadTuning reads a JSON object of PostgreSQL settings, e.g.
+// {"work_mem": "64MB", "random_page_cost": "1.1"}
+func LoadTuning(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read tuning file: %w", err)
+	}
+
+	raw := make(map[string]any)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decode tuning file: %w", err)
+	}
+
+	settings := make(map[string]string, len(raw))
+	for name, value := range raw {
+		settings[name] = fmt.Sprint(value)
+	}
+
+	return settings, nil
+}
+
+// ApplyTuning applies settings via ALTER SYSTEM and reloads the configuration,
+// restarting the container when any setting requires a server restart
+func ApplyTuning(settings map[string]string) error {
+	if len(settings) == 0 {
+		return nil
+	}
+
+	connStr := Connection.connString(Connection.Port)
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	needsRestart := false
+	for _, name := range names {
+		var context string
+		err := db.QueryRow("SELECT context FROM pg_settings WHERE name = $1", name).Scan(&context)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("unknown setting: %s", name)
+		}
+		if err != nil {
+			return fmt.Errorf("look up setting %s: %w", name, err)
+		}
+
+		alterSQL := fmt.Sprintf("ALTER SYSTEM SET %s = %s", pq.QuoteIdentifier(name), pq.QuoteLiteral(settings[name]))
+		if _, err := db.Exec(alterSQL); err != nil {
+			return fmt.Errorf("set %s: %w", name, err)
+		}
+
+		if context == "postmaster" {
+			needsRestart = true
+		}
+	}
+
+	if _, err := db.Exec("SELECT pg_reload_conf()"); err != nil {
+		return fmt.Errorf("reload configuration: %w", err)
+	}
+
+	if needsRestart {
+		db.Close()
+		fmt.Println("Restarting PostgreSQL to apply tuning...")
+
+		cmd := exec.Command("docker", "restart", Connection.Container)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("restart container: %w (stderr: %s)", err, stderr.String())
+		}
+
+		if err := WaitForReady(); err != nil {
+			return fmt.Errorf("wait after restart: %w", err)
+		}
+	}
+
+	fmt.Printf("Applied %d tuning settings\n", len(settings))
+	return nil
+}
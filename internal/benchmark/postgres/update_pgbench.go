@@ -9,21 +9,26 @@ import (
 )
 
 func (p *PostgresBenchmarker) UpdateRecordsPgbench(keyType string, numTotalRecords, numUpdates, batchSize int) (time.Duration, error) {
-	script := pgbench.GenerateUpdateScript(keyType, p.tableName)
+	numTotalRecords, err := p.targetRecords(numTotalRecords)
+	if err != nil {
+		return 0, err
+	}
+
+	script := p.layout().GenerateUpdateScript(keyType, p.tableName)
 
 	scriptWithVars := fmt.Sprintf("\\set num_records %d\n%s", numTotalRecords, script)
 
 	scriptName := fmt.Sprintf("update_%s.sql", keyType)
-	containerPath, err := pgbench.CopyScriptToContainer("uuid-bench-postgres", scriptWithVars, scriptName)
+	containerPath, err := p.copyScript(scriptWithVars, scriptName)
 	if err != nil {
 		return 0, fmt.Errorf("copy script to container: %w", err)
 	}
 
-	execCfg := pgbench.ExecutorConfig{
-		ContainerName: "uuid-bench-postgres",
-		Connections:   1,
-		Transactions:  numUpdates,
-		ScriptPath:    containerPath,
+	execCfg := p.execConfig(1, numUpdates, containerPath)
+	execCfg.LogLatencies = true
+
+	if err := p.warmup(execCfg); err != nil {
+		return 0, err
 	}
 
 	startTime := time.Now()
@@ -39,31 +44,48 @@ func (p *PostgresBenchmarker) UpdateRecordsPgbench(keyType string, numTotalRecor
 
 	duration := time.Since(startTime)
 
+	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
+	if err == nil {
+		p.reportThrottling(parsed)
+		p.checkTransactions(parsed)
+	}
+	p.lastPgbench = parsed
+	p.lastLatency = latencyPercentiles(execResult, parsed)
+
+	if _, err := p.completeOps(numUpdates, 1, parsed); err != nil {
+		return 0, err
+	}
+
 	return duration, nil
 }
 
 func (p *PostgresBenchmarker) UpdateRecordsPgbenchConcurrent(keyType string, numTotalRecords, numUpdates, connections, batchSize int) (*benchmark.ConcurrentBenchmarkResult, error) {
-	script := pgbench.GenerateUpdateScript(keyType, p.tableName)
+	numTotalRecords, err := p.targetRecords(numTotalRecords)
+	if err != nil {
+		return nil, err
+	}
+
+	script := p.layout().GenerateUpdateScript(keyType, p.tableName)
 
 	scriptWithVars := fmt.Sprintf("\\set num_records %d\n%s", numTotalRecords, script)
 
 	scriptName := fmt.Sprintf("update_%s_concurrent.sql", keyType)
-	containerPath, err := pgbench.CopyScriptToContainer("uuid-bench-postgres", scriptWithVars, scriptName)
+	containerPath, err := p.copyScript(scriptWithVars, scriptName)
 	if err != nil {
 		return nil, fmt.Errorf("copy script to container: %w", err)
 	}
 
 	transactionsPerClient := numUpdates / connections
 
-	startTime := time.Now()
+	execCfg := p.execConfig(connections, transactionsPerClient, containerPath)
+	execCfg.LogLatencies = true
 
-	execCfg := pgbench.ExecutorConfig{
-		ContainerName: "uuid-bench-postgres",
-		Connections:   connections,
-		Transactions:  transactionsPerClient,
-		ScriptPath:    containerPath,
+	if err := p.warmup(execCfg); err != nil {
+		return nil, err
 	}
 
+	startTime := time.Now()
+
 	execResult, err := pgbench.Execute(execCfg)
 	if err != nil {
 		return nil, fmt.Errorf("execute pgbench: %w", err)
@@ -77,17 +99,26 @@ func (p *PostgresBenchmarker) UpdateRecordsPgbenchConcurrent(keyType string, num
 	if err != nil {
 		return nil, fmt.Errorf("parse pgbench output: %w", err)
 	}
+	p.reportThrottling(parsed)
+	p.checkTransactions(parsed)
+	p.lastPgbench = parsed
 
 	duration := time.Since(startTime)
 
+	numUpdates, err = p.completeOps(numUpdates, 1, parsed)
+	if err != nil {
+		return nil, err
+	}
+
 	return &benchmark.ConcurrentBenchmarkResult{
 		Duration:     duration,
 		TotalOps:     numUpdates,
 		Throughput:   parsed.TPS,
-		LatencyP50:   parsed.P50,
-		LatencyP95:   parsed.P95,
-		LatencyP99:   parsed.P99,
+		Latency:      latencyPercentiles(execResult, parsed),
 		SuccessCount: parsed.Transactions,
 		ErrorCount:   numUpdates - parsed.Transactions,
+
+		InitialConnectionTime:    parsed.InitialConnectionTime,
+		ThroughputIncludingSetup: parsed.TPSIncludingSetup,
 	}, nil
 }
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres/pgbench"
+)
+
+// UpsertRecordsPgbench runs numUpserts single-row INSERT ... ON CONFLICT DO UPDATE
+// transactions against the loaded table, conflictRatio percent of them on ids that
+// already exist. It returns the wall time and how many rows the upserts inserted and
+// updated, from the table's write counters.
+func (p *PostgresBenchmarker) UpsertRecordsPgbench(keyType string, numTotalRecords, numUpserts, conflictRatio int) (duration time.Duration, inserted, updated int64, err error) {
+	numTotalRecords, err = p.targetRecords(numTotalRecords)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	script := p.layout().GenerateUpsertScript(keyType, p.tableName)
+
+	scriptWithVars := fmt.Sprintf("\\set num_records %d\n\\set conflict_ratio %d\n%s", numTotalRecords, conflictRatio, script)
+
+	scriptName := fmt.Sprintf("upsert_%s.sql", keyType)
+	containerPath, err := p.copyScript(scriptWithVars, scriptName)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("copy script to container: %w", err)
+	}
+
+	execCfg := p.execConfig(1, numUpserts, containerPath)
+	execCfg.LogLatencies = true
+
+	// Warmup upserts insert rows too, so count from before them for the row check
+	insertedBeforeWarmup, _, err := p.tableWriteCounts()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	if err := p.warmup(execCfg); err != nil {
+		return 0, 0, 0, err
+	}
+
+	insertedBefore, updatedBefore, err := p.tableWriteCounts()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	startLSN, err := p.getCurrentLSN()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("capture start LSN: %w", err)
+	}
+	p.startLSN = startLSN
+
+	startTime := time.Now()
+
+	execResult, err := pgbench.Execute(execCfg)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("execute pgbench: %w", err)
+	}
+
+	if execResult.ExitCode != 0 {
+		return 0, 0, 0, fmt.Errorf("pgbench failed with exit code %d: %s", execResult.ExitCode, execResult.Stderr)
+	}
+
+	duration = time.Since(startTime)
+
+	endLSN, err := p.getCurrentLSN()
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("capture end LSN: %w", err)
+	}
+	p.endLSN = endLSN
+
+	parsed, err := pgbench.ParsePgbenchOutput(execResult.Stdout)
+	if err == nil {
+		p.reportThrottling(parsed)
+		p.checkTransactions(parsed)
+	}
+	p.lastLatency = latencyPercentiles(execResult, parsed)
+
+	insertedAfter, updatedAfter, err := p.tableWriteCounts()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	p.expectedRows += insertedAfter - insertedBeforeWarmup
+
+	return duration, insertedAfter - insertedBefore, updatedAfter - updatedBefore, nil
+}
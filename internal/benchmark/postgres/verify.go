@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"fmt"
+)
+
+// verifySampleSize is the number of rows (and bigserial ids) checked by VerifyData
+const verifySampleSize = 1000
+
+// dataPattern matches every data value the insert and update scripts write
+const dataPattern = `^(test_data|updated)_[0-9]+x*$`
+
+// uuidVersions is the UUID version each server-generated UUID key type must carry
+var uuidVersions = map[string]int{
+	"uuidv4": 4,
+	"uuidv7": 7,
+	"uuidv1": 1,
+	"uuidv6": 6,
+	"uuidv8": 8,
+}
+
+// DataVerification is the outcome of VerifyData
+type DataVerification struct {
+	Rows         int64 // Rows in the table
+	ExpectedRows int64 // Rows the insert phases should have produced
+	SampledRows  int64 // Random rows whose data (and UUID version) was checked
+	BadData      int64 // Sampled rows whose data does not match the generation pattern
+	BadVersion   int64 // Sampled rows whose UUID carries the wrong version
+	SampledIDs   int64 // Evenly spaced bigserial ids in 1..ExpectedRows looked up
+	MissingIDs   int64 // Sampled bigserial ids not present in the table
+}
+
+// OK reports whether no mismatches or missing ids were found
+func (v *DataVerification) OK() bool {
+	return v.BadData == 0 && v.BadVersion == 0 && v.MissingIDs == 0 && v.Rows >= v.ExpectedRows
+}
+
+// VerifyData checks that the load phase produced what it claims: a random sample of
+// rows must carry data written by the scripts (and, for UUID key types, the right UUID
+// version), and for bigserial, whose ids are deterministic, sampled ids in
+// 1..expected rows must all exist. Under-inserting would otherwise make a key type look
+// artificially good.
+func (p *PostgresBenchmarker) VerifyData() (*DataVerification, error) {
+	v := &DataVerification{ExpectedRows: p.expectedRows}
+
+	rows, err := p.countRows()
+	if err != nil {
+		return nil, err
+	}
+	v.Rows = rows
+
+	// Only text data holds the pattern as written; other types are checked for ids only
+	dataCheck := "false"
+	args := []any{verifySampleSize}
+	if p.opts.DataColumn.TextLike() {
+		dataCheck = "data IS NULL OR data !~ $2"
+		args = append(args, dataPattern)
+	}
+
+	versionCheck := "false"
+	if version, ok := uuidVersions[p.keyType]; ok {
+		versionCheck = fmt.Sprintf("uuid_extract_version(%s) IS DISTINCT FROM %d", p.idColumn(), version)
+	}
+
+	err = p.db.QueryRow(fmt.Sprintf(`
+		SELECT
+			count(*),
+			count(*) FILTER (WHERE %s),
+			count(*) FILTER (WHERE %s)
+		FROM (SELECT * FROM %s ORDER BY random() LIMIT $1) sample
+	`, dataCheck, versionCheck, p.tableName), args...).Scan(&v.SampledRows, &v.BadData, &v.BadVersion)
+	if err != nil {
+		return nil, fmt.Errorf("check sampled rows: %w", err)
+	}
+
+	if p.keyType == "bigserial" && p.expectedRows > 0 {
+		step := max(p.expectedRows/verifySampleSize, 1)
+		err = p.db.QueryRow(fmt.Sprintf(`
+			SELECT count(*), count(*) FILTER (WHERE t.%[2]s IS NULL)
+			FROM generate_series(1, $1::bigint, $2::bigint) AS g(id)
+			LEFT JOIN %[1]s t ON t.%[2]s = g.id
+		`, p.tableName, p.idColumn()), p.expectedRows, step).Scan(&v.SampledIDs, &v.MissingIDs)
+		if err != nil {
+			return nil, fmt.Errorf("check sampled ids: %w", err)
+		}
+	}
+
+	return v, nil
+}
+
+// Report prints the verification outcome, with a warning line per kind of mismatch
+func (v *DataVerification) Report(keyType string) {
+	if v.OK() {
+		fmt.Printf("Data verification passed for %s: %d rows, %d sampled rows", keyType, v.Rows, v.SampledRows)
+		if v.SampledIDs > 0 {
+			fmt.Printf(", %d sampled ids", v.SampledIDs)
+		}
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("Warning: Data verification FAILED for %s\n", keyType)
+	if v.Rows < v.ExpectedRows {
+		fmt.Printf("  Rows:        %d present, %d expected (%d missing)\n", v.Rows, v.ExpectedRows, v.ExpectedRows-v.Rows)
+	}
+	if v.MissingIDs > 0 {
+		fmt.Printf("  Missing ids: %d of %d sampled ids in 1..%d\n", v.MissingIDs, v.SampledIDs, v.ExpectedRows)
+	}
+	if v.BadData > 0 {
+		fmt.Printf("  Bad data:    %d of %d sampled rows do not match %s\n", v.BadData, v.SampledRows, dataPattern)
+	}
+	if v.BadVersion > 0 {
+		fmt.Printf("  Bad version: %d of %d sampled ids are not UUIDv%d\n", v.BadVersion, v.SampledRows, uuidVersions[keyType])
+	}
+}
@@ -3,23 +3,69 @@ package benchmark
 import "time"
 
 type InsertPerformanceResult struct {
-	KeyType           string
-	NumRecords        int
-	BatchSize         int
-	Connections       int
-	Duration          time.Duration
-	Throughput        float64
-	PageSplits        int
-	TableSize         int64
-	IndexSize         int64
-	Fragmentation     IndexFragmentationStats
-	LatencyP50        time.Duration
-	LatencyP95        time.Duration
-	LatencyP99        time.Duration
-	ReadIOPS          float64
-	WriteIOPS         float64
-	ReadThroughputMB  float64
-	WriteThroughputMB float64
+	KeyType            string
+	NumRecords         int
+	Preloaded          int // Rows loaded before the measured inserts, excluded from their metrics
+	BatchSize          int
+	Connections        int
+	InsertMode         string        // pgbench, batch or copy; sqlite for the SQLite benchmarker
+	TimeBound          int           // Seconds the inserts ran for under -duration, 0 = NumRecords rows
+	Duration           time.Duration // Wall-clock time of the measured inserts, including pgbench's connection setup
+	Throughput         float64       // NumRecords / Duration
+	TPS                float64       // pgbench TPS excluding connection setup
+	TPSIncludingSetup  float64       // pgbench TPS including connection setup
+	ConnectionTime     time.Duration // pgbench initial connection time
+	Rate               float64       // pgbench -R target transactions/sec, 0 = unthrottled
+	ScheduleLagAvg     time.Duration // Under Rate, mean delay between scheduled and actual transaction start
+	ScheduleLagMax     time.Duration // Under Rate, largest such delay
+	Skipped            int           // Under Rate, transactions skipped for missing the latency limit
+	PageSplits         int
+	TableSize          int64
+	IndexSize          int64   // All indexes on the table
+	PKIndexSize        int64   // Primary key index alone
+	IndexTableRatio    float64 // IndexSize / TableSize: storage the indexes add per byte of heap
+	ExtraIndexes       int     // Secondary indexes maintained alongside the primary key
+	Fragmentation      IndexFragmentationStats
+	Tuples             *TupleStats               // Heap after the inserts, nil unless -measure-bloat
+	Latency            map[float64]time.Duration // Insert latency per percentile in Percentiles (concurrent runs)
+	ReadIOPS           float64
+	WriteIOPS          float64
+	ReadThroughputMB   float64
+	WriteThroughputMB  float64
+	WriteAmplification float64 // Bytes written to disk / logical bytes inserted
+	WALBytes           int64   // WAL generated by the measured inserts
+	FPIBytes           int64   // Full-page image bytes within WALBytes
+	IndexPagesDirtied  int64   // Distinct B-tree pages the measured inserts modified
+	TempFiles          int64   // Temp files created (sorts/hashes spilling past work_mem)
+	TempBytes          int64   // Bytes written to temp files
+	AvgCPUPercent      float64
+	PeakCPUPercent     float64
+	AvgRSSMB           float64
+	PeakRSSMB          float64
+	Setup              SetupTiming // DDL/setup phase, zero unless -include-ddl-timing
+	FilePages          int64       // Pages of the database file, SQLite only
+	FreelistPages      int64       // Pages of the database file allocated but unused, SQLite only
+
+	MeasurementCV map[string]float64 // CV (%) per metric over repeated measurements of the same table, nil unless -remeasure
+
+	SizeSamples []SizeSample // Table and index size over the measured inserts, nil unless -size-sampling
+}
+
+// SizeSample is the table's size at one point of a run
+type SizeSample struct {
+	Elapsed   time.Duration // Since sampling started
+	TableSize int64         // pg_table_size: heap, TOAST, free space and visibility maps
+	IndexSize int64         // pg_indexes_size: all indexes on the table
+}
+
+// IndexPagesDirtiedPer1k is the write locality of the inserts: distinct index pages
+// modified per 1000 rows. Ordered keys keep rewriting the same rightmost leaves, random
+// keys spread over nearly every leaf.
+func (r *InsertPerformanceResult) IndexPagesDirtiedPer1k() float64 {
+	if r.NumRecords == 0 {
+		return 0
+	}
+	return float64(r.IndexPagesDirtied) * 1000 / float64(r.NumRecords)
 }
 
 type ReadAfterFragmentationResult struct {
@@ -29,16 +75,37 @@ type ReadAfterFragmentationResult struct {
 	InsertDuration      time.Duration
 	ReadDuration        time.Duration
 	ReadThroughput      float64
+	QueryMode           string             // pgbench -M protocol of the measured read phase
+	ModeThroughput      map[string]float64 // Read ops/sec per protocol, with -compare-query-modes
+	ReadPlan            string             // Root plan node of the read query (EXPLAIN)
+	IndexScan           bool               // Whether the read query's id lookup uses the primary key index
 	Fragmentation       IndexFragmentationStats
 	BufferHitRatio      float64
 	IndexBufferHitRatio float64
-	LatencyP50          time.Duration
-	LatencyP95          time.Duration
-	LatencyP99          time.Duration
+	ReadAmplification   float64                   // Index + heap blocks read from outside shared buffers per row returned
+	Latency             map[float64]time.Duration // Read latency per percentile
 	ReadIOPS            float64
 	WriteIOPS           float64
 	ReadThroughputMB    float64
 	WriteThroughputMB   float64
+	TempFiles           int64 // Temp files created (sorts/hashes spilling past work_mem)
+	TempBytes           int64 // Bytes written to temp files
+	AvgCPUPercent       float64
+	PeakCPUPercent      float64
+	AvgRSSMB            float64
+	PeakRSSMB           float64
+	Setup               SetupTiming // DDL/setup phase, zero unless -include-ddl-timing
+}
+
+// ParsePlanShare is the fraction of per-read time spent parsing and planning, derived
+// from simple (re-parsed every op) vs prepared (parsed once per connection) throughput.
+// Zero unless both protocols were measured.
+func (r *ReadAfterFragmentationResult) ParsePlanShare() float64 {
+	simple, prepared := r.ModeThroughput["simple"], r.ModeThroughput["prepared"]
+	if simple == 0 || prepared == 0 {
+		return 0
+	}
+	return 1 - simple/prepared
 }
 
 type UpdatePerformanceResult struct {
@@ -49,13 +116,28 @@ type UpdatePerformanceResult struct {
 	UpdateDuration    time.Duration
 	UpdateThroughput  float64
 	Fragmentation     IndexFragmentationStats
-	LatencyP50        time.Duration
-	LatencyP95        time.Duration
-	LatencyP99        time.Duration
+	CorrelationBefore float64                   // pg_stats.correlation of id before the update workload
+	CorrelationAfter  float64                   // pg_stats.correlation of id after the update workload
+	CorrelationDelta  float64                   // CorrelationAfter - CorrelationBefore
+	Tuples            *TupleStats               // Heap after the updates, nil unless -measure-bloat
+	VacuumTuples      *TupleStats               // Heap after the VACUUM following the updates
+	VacuumDuration    time.Duration             // Time taken by that VACUUM
+	IndexSizeBefore   int64                     // All indexes before the VACUUM
+	IndexSizeAfter    int64                     // All indexes after the VACUUM
+	Latency           map[float64]time.Duration // Update latency per percentile
 	ReadIOPS          float64
 	WriteIOPS         float64
 	ReadThroughputMB  float64
 	WriteThroughputMB float64
+	TempFiles         int64 // Temp files created (sorts/hashes spilling past work_mem)
+	TempBytes         int64 // Bytes written to temp files
+	AvgCPUPercent     float64
+	PeakCPUPercent    float64
+	AvgRSSMB          float64
+	PeakRSSMB         float64
+	Setup             SetupTiming // DDL/setup phase, zero unless -include-ddl-timing
+	FilePages         int64       // Pages of the database file, SQLite only
+	FreelistPages     int64       // Pages of the database file allocated but unused, SQLite only
 }
 
 type MixedWorkloadResult struct {
@@ -66,10 +148,18 @@ type MixedWorkloadResult struct {
 	InsertOps           int
 	ReadOps             int
 	UpdateOps           int
+	ObservedInsertOps   int // Transactions that ran the insert script, from pgbench's per-script report
+	ObservedReadOps     int // Transactions that ran the read script
+	ObservedUpdateOps   int // Transactions that ran the update script
 	OverallThroughput   float64
-	InsertThroughput    float64
-	ReadThroughput      float64
-	UpdateThroughput    float64
+	TPSIncludingSetup   float64
+	ConnectionTime      time.Duration
+	InsertThroughput    float64                   // Observed inserts per second of Duration
+	ReadThroughput      float64                   // Observed reads per second of Duration
+	UpdateThroughput    float64                   // Observed updates per second of Duration
+	InsertLatency       map[float64]time.Duration // Insert latency per percentile, nil without inserts
+	ReadLatency         map[float64]time.Duration // Read latency per percentile, nil without reads
+	UpdateLatency       map[float64]time.Duration // Update latency per percentile, nil without updates
 	BufferHitRatio      float64
 	IndexBufferHitRatio float64
 	Fragmentation       IndexFragmentationStats
@@ -79,4 +169,224 @@ type MixedWorkloadResult struct {
 	WriteIOPS           float64
 	ReadThroughputMB    float64
 	WriteThroughputMB   float64
+	TempFiles           int64 // Temp files created (sorts/hashes spilling past work_mem)
+	TempBytes           int64 // Bytes written to temp files
+	AvgCPUPercent       float64
+	PeakCPUPercent      float64
+	AvgRSSMB            float64
+	PeakRSSMB           float64
+	Setup               SetupTiming // DDL/setup phase, zero unless -include-ddl-timing
+}
+
+// SetOperationThroughputs derives the per-operation rates from the observed operation
+// counts. Every client interleaves all three operations over the whole run, so each
+// type's window is the run's wall-clock Duration, however many clients shared it.
+func (r *MixedWorkloadResult) SetOperationThroughputs() {
+	if r.Duration <= 0 {
+		return
+	}
+	seconds := r.Duration.Seconds()
+	r.InsertThroughput = float64(r.ObservedInsertOps) / seconds
+	r.ReadThroughput = float64(r.ObservedReadOps) / seconds
+	r.UpdateThroughput = float64(r.ObservedUpdateOps) / seconds
+}
+
+type JSONBGinResult struct {
+	KeyType           string
+	NumRecords        int
+	BatchSize         int
+	Duration          time.Duration
+	Throughput        float64
+	PageSplits        int
+	TableSize         int64
+	PKIndexSize       int64
+	GinIndexSize      int64
+	GinBuildDuration  time.Duration // Time to rebuild the GIN index from scratch after loading
+	Fragmentation     IndexFragmentationStats
+	GinBuildTempBytes int64 // Temp bytes spilled while rebuilding the GIN index
+}
+
+// CacheCompetitionResult holds how shared_buffers is split between the table's heap and
+// its primary key index after a read phase under a deliberately small buffer pool
+type CacheCompetitionResult struct {
+	KeyType        string
+	NumRecords     int
+	NumReads       int
+	ReadThroughput float64
+	SharedBuffers  int64 // Buffer pool size in pages
+	HeapBuffers    int64 // Buffers holding heap pages of the table
+	IndexBuffers   int64 // Buffers holding primary key index pages
+	TableSize      int64
+	IndexSize      int64
+	HeapHitRatio   float64 // Heap block hit ratio during the read phase
+	IndexHitRatio  float64 // Index block hit ratio during the read phase
+}
+
+// WorkingSetPoint is one dataset size of the working-set sweep
+type WorkingSetPoint struct {
+	Fraction       float64 // Target table + index size as a multiple of shared_buffers
+	Rows           int64
+	TableSize      int64
+	IndexSize      int64
+	ReadThroughput float64
+	HeapHitRatio   float64 // Heap block hit ratio during the read phase
+	IndexHitRatio  float64 // Index block hit ratio during the read phase
+}
+
+// WorkingSetSweepResult holds read throughput and hit ratios as the dataset grows past
+// the buffer pool
+type WorkingSetSweepResult struct {
+	KeyType       string
+	NumReads      int
+	SharedBuffers int64 // Buffer pool size in bytes
+	Points        []WorkingSetPoint
+}
+
+// ReindexCycle is one insert burst followed by REINDEX INDEX CONCURRENTLY
+type ReindexCycle struct {
+	RowsInserted       int           // Rows inserted in this burst
+	FragmentationAfter float64       // Fragmentation (%) after the burst, before reindexing
+	FragmentationReset float64       // Fragmentation (%) right after the reindex
+	ReindexDuration    time.Duration // Time taken by REINDEX INDEX CONCURRENTLY
+}
+
+// ReindexMaintenanceResult holds how fast fragmentation re-accumulates after periodic
+// concurrent reindexes and what those reindexes cost in total
+type ReindexMaintenanceResult struct {
+	KeyType            string
+	NumRecords         int
+	BatchSize          int
+	Cycles             []ReindexCycle
+	TotalReindexTime   time.Duration
+	RegrowthPer100k    float64 // Mean fragmentation regrowth after a reindex, percentage points per 100k rows
+	FinalIndexSize     int64
+	FinalFragmentation float64
+}
+
+// UpdateChurnSample is the table's bloat after a number of update rounds
+type UpdateChurnSample struct {
+	Updates    int // Updates run so far, across all rounds
+	DeadTuples int64
+	TableBloat float64 // Dead tuples plus free space, % of the table
+	IndexBloat float64 // Free space in primary key leaf pages, %
+	TableSize  int64
+	IndexSize  int64
+}
+
+// UpdateChurnResult holds the bloat-accumulation curve of repeated update rounds over
+// the same rows, with the first sample taken right after loading
+type UpdateChurnResult struct {
+	KeyType        string
+	NumRecords     int
+	NumUpdates     int     // Updates per round
+	HOTUpdateRatio float64 // Share of updates that were heap-only, %
+	Samples        []UpdateChurnSample
+}
+
+// Final returns the sample taken after the last round
+func (r *UpdateChurnResult) Final() UpdateChurnSample {
+	return r.Samples[len(r.Samples)-1]
+}
+
+// IndexGrowth returns how much the indexes grew over the churn, in percent of their
+// size after loading
+func (r *UpdateChurnResult) IndexGrowth() float64 {
+	loaded := r.Samples[0].IndexSize
+	if loaded == 0 {
+		return 0
+	}
+	return float64(r.Final().IndexSize-loaded) / float64(loaded) * 100
+}
+
+// ConnectionScalingPoint is connection setup cost at one client count
+type ConnectionScalingPoint struct {
+	Clients               int
+	InitialConnectionTime time.Duration // Time pgbench took to open all clients' connections at once
+	AvgConnectionTime     time.Duration // Mean time to open one connection while reconnecting per transaction
+	ConnectionsPerSec     float64       // Connections opened (and used for SELECT 1) per second under -C
+	QueryTPS              float64       // SELECT 1 per second on persistent connections, for reference
+}
+
+// ConnectionScalingResult holds connection establishment rates at increasing
+// concurrency, independent of key type: the harness's connection-setup ceiling
+type ConnectionScalingResult struct {
+	Transactions int // Transactions at each client count, split across the clients
+	Points       []ConnectionScalingPoint
+}
+
+// CommitOverheadResult holds insert throughput across batch sizes and the fitted split
+// of insert cost into a fixed per-commit overhead and a per-row cost
+type CommitOverheadResult struct {
+	KeyType           string
+	NumRecords        int
+	BatchSizes        []int
+	Throughputs       []float64     // records/sec for each batch size
+	PerCommitOverhead time.Duration // Fitted fixed cost per transaction
+	PerRowCost        time.Duration // Fitted marginal cost per inserted row
+	FitRSquared       float64       // R² of time/row = overhead/batch + rowCost
+}
+
+// InsertReturningResult compares plain single-row inserts with INSERT ... RETURNING id,
+// which applications use to learn a server-generated key
+type InsertReturningResult struct {
+	KeyType             string
+	NumRecords          int
+	Connections         int
+	PlainThroughput     float64                   // records/sec without RETURNING
+	ReturningThroughput float64                   // records/sec with RETURNING id
+	PlainLatency        map[float64]time.Duration // Latency per percentile in Percentiles
+	ReturningLatency    map[float64]time.Duration
+}
+
+// ReturningOverhead is the throughput lost to RETURNING id, in percent of the plain rate
+func (r *InsertReturningResult) ReturningOverhead() float64 {
+	if r.PlainThroughput == 0 {
+		return 0
+	}
+	return (r.PlainThroughput - r.ReturningThroughput) / r.PlainThroughput * 100
+}
+
+// UpsertPerformanceResult holds single-row INSERT ... ON CONFLICT DO UPDATE performance
+// against a pre-loaded table, where ConflictRatio percent of the upserts target an
+// existing id
+type UpsertPerformanceResult struct {
+	KeyType       string
+	NumRecords    int // Rows loaded before the upserts
+	NumUpserts    int
+	ConflictRatio int // Requested share of upserts on existing ids, %
+	Duration      time.Duration
+	Throughput    float64 // upserts/sec
+	Inserted      int64   // Upserts that inserted a new row
+	Updated       int64   // Upserts that took the conflict path and updated a row
+	PageSplits    int
+	TableSize     int64
+	IndexSize     int64
+	Fragmentation IndexFragmentationStats
+	Latency       map[float64]time.Duration // Latency per percentile in Percentiles
+}
+
+// ConflictRate is the observed share of upserts that updated an existing row, in percent
+func (r *UpsertPerformanceResult) ConflictRate() float64 {
+	if r.Inserted+r.Updated == 0 {
+		return 0
+	}
+	return float64(r.Updated) / float64(r.Inserted+r.Updated) * 100
+}
+
+// RangeScanResult holds the performance of range scans reading RangeSize consecutive
+// rows in key order from random start ids of a pre-loaded table. Time-ordered keys keep
+// a range on neighbouring heap pages, random keys scatter it, which PagesPerScan shows.
+type RangeScanResult struct {
+	KeyType        string
+	NumRecords     int // Rows loaded before the scans
+	NumScans       int
+	RangeSize      int // Rows each scan asks for
+	InsertDuration time.Duration
+	ScanDuration   time.Duration
+	ScanThroughput float64 // scans/sec
+	RowsPerScan    float64 // Rows fetched through the index per scan
+	PagesPerScan   float64 // Heap and index blocks accessed per scan, hit or read
+	BufferHitRatio float64 // Share of those blocks found in shared buffers
+	Fragmentation  IndexFragmentationStats
+	Latency        map[float64]time.Duration // Scan latency per percentile
 }
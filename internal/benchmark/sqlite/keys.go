@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// KeyTypes are the key types the SQLite benchmarker can generate, in default run order
+var KeyTypes = []string{"bigserial", "uuidv4", "uuidv7", "uuidv1"}
+
+// gregorianOffset is the number of 100ns intervals from 1582-10-15, the UUIDv1 epoch,
+// to the Unix epoch
+const gregorianOffset = 0x01B21DD213814000
+
+// keyGenerator returns the next id of a key type. SQLite has no uuid functions, so ids
+// are generated in-process, following the PostgreSQL generators: uuidv7 carries a
+// 12-bit sub-millisecond fraction like PostgreSQL 18's uuidv7(), keeping ids from one
+// generator strictly increasing, and uuidv1 is in standard byte order, low timestamp
+// bits first.
+type keyGenerator struct {
+	keyType      string
+	last         uint64  // Last timestamp used, bumped to stay increasing (uuidv1, uuidv7) or the last bigserial id
+	clockSeqNode [8]byte // uuidv1 clock sequence, with the variant bits, and node
+}
+
+func newKeyGenerator(keyType string) *keyGenerator {
+	g := &keyGenerator{keyType: keyType}
+	// Random clock sequence and a multicast node, as uuid-ossp does without a MAC
+	rand.Read(g.clockSeqNode[:])
+	g.clockSeqNode[0] = g.clockSeqNode[0]&0x3F | 0x80
+	g.clockSeqNode[2] |= 0x01
+	return g
+}
+
+// next returns a new id: an int64 for bigserial, 16 bytes for the uuid key types
+func (g *keyGenerator) next() any {
+	var id [16]byte
+
+	switch g.keyType {
+	case "bigserial":
+		g.last++
+		return int64(g.last)
+
+	case "uuidv4":
+		rand.Read(id[:])
+		id[6] = id[6]&0x0F | 0x40
+
+	case "uuidv7":
+		now := uint64(time.Now().UnixNano())
+		// Milliseconds in the top 48 bits, the fraction of a millisecond in 12 more
+		ts := (now/1e6)<<12 | (now%1e6)*4096/1e6
+		g.last = max(ts, g.last+1)
+		binary.BigEndian.PutUint64(id[0:8], g.last>>12<<16|0x7000|g.last&0x0FFF)
+		rand.Read(id[8:])
+
+	case "uuidv1":
+		ts := uint64(time.Now().UnixNano()/100) + gregorianOffset
+		g.last = max(ts, g.last+1)
+		binary.BigEndian.PutUint32(id[0:4], uint32(g.last))
+		binary.BigEndian.PutUint16(id[4:6], uint16(g.last>>32))
+		binary.BigEndian.PutUint16(id[6:8], uint16(g.last>>48)&0x0FFF|0x1000)
+		copy(id[8:], g.clockSeqNode[:])
+		return id[:]
+	}
+
+	id[8] = id[8]&0x3F | 0x80
+	return id[:]
+}
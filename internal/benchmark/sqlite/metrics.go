@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"fmt"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+)
+
+// MeasureMetrics collects the table and primary key sizes and the index's leaf pages
+// from the dbstat virtual table. Fragmentation follows pgstatindex's definition: the
+// share of leaf pages stored before their predecessor in key order. SQLite keeps no
+// page split counter, so PageSplits stays zero.
+func (s *SQLiteBenchmarker) MeasureMetrics() (*benchmark.BenchmarkResult, error) {
+	result := &benchmark.BenchmarkResult{}
+
+	if err := s.db.QueryRow("SELECT COALESCE(SUM(pgsize), 0) FROM dbstat WHERE name = ?", s.tableName).Scan(&result.TableSize); err != nil {
+		return nil, fmt.Errorf("measure table size: %w", err)
+	}
+
+	if err := s.db.QueryRow("SELECT COALESCE(SUM(pgsize), 0) FROM dbstat WHERE name = ?", s.indexName).Scan(&result.IndexSize); err != nil {
+		return nil, fmt.Errorf("measure index size: %w", err)
+	}
+
+	// dbstat walks each B-tree in key order, so leaf pages come in logical order
+	rows, err := s.db.Query("SELECT pageno, pgsize, unused FROM dbstat WHERE name = ? AND pagetype = 'leaf'", s.indexName)
+	if err != nil {
+		return nil, fmt.Errorf("read index pages: %w", err)
+	}
+	defer rows.Close()
+
+	var leafPages, outOfOrder, leafBytes, usedBytes, prev int64
+	for rows.Next() {
+		var pageno, size, unused int64
+		if err := rows.Scan(&pageno, &size, &unused); err != nil {
+			return nil, fmt.Errorf("scan index page: %w", err)
+		}
+		if leafPages > 0 && pageno < prev {
+			outOfOrder++
+		}
+		prev = pageno
+		leafPages++
+		leafBytes += size
+		usedBytes += size - unused
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read index pages: %w", err)
+	}
+
+	result.Fragmentation.LeafPages = leafPages
+	if leafPages > 0 {
+		result.Fragmentation.FragmentationPercent = float64(outOfOrder) / float64(leafPages) * 100
+		result.Fragmentation.AvgLeafDensity = float64(usedBytes) / float64(leafBytes) * 100
+	}
+
+	return result, nil
+}
+
+// FileStats returns the database file's page count and how many of them are on the
+// freelist, allocated but unused
+func (s *SQLiteBenchmarker) FileStats() (pages, freePages int64, err error) {
+	if err := s.db.QueryRow("PRAGMA page_count").Scan(&pages); err != nil {
+		return 0, 0, fmt.Errorf("read page count: %w", err)
+	}
+	if err := s.db.QueryRow("PRAGMA freelist_count").Scan(&freePages); err != nil {
+		return 0, 0, fmt.Errorf("read freelist count: %w", err)
+	}
+	return pages, freePages, nil
+}
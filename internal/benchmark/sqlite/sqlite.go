@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+// Options holds benchmark-wide settings applied to every benchmarker
+type Options struct {
+	Seed int64 // Seed of the generator picking rows to read and update, 0 = unseeded
+
+	PayloadBytes int // Width data values are padded to with 'x', 0 = unpadded
+}
+
+// SQLiteBenchmarker runs the workloads in-process against an on-disk SQLite file, no
+// container needed. Like PostgreSQL's heap and primary key index, the rows live in a
+// rowid table and the primary key is a separate B-tree, so only the index sees the
+// key order.
+type SQLiteBenchmarker struct {
+	opts      Options
+	dir       string // Temporary directory holding the database file, removed on Close
+	db        *sql.DB
+	keyType   string
+	tableName string
+	indexName string
+
+	keys *keyGenerator
+	ids  []any // Every inserted id, which reads and updates sample from
+}
+
+func New(opts Options) *SQLiteBenchmarker {
+	return &SQLiteBenchmarker{opts: opts}
+}
+
+// Connect creates a fresh database file in a temporary directory
+func (s *SQLiteBenchmarker) Connect() error {
+	dir, err := os.MkdirTemp("", "uuid-benchmark-sqlite-")
+	if err != nil {
+		return fmt.Errorf("create database directory: %w", err)
+	}
+	s.dir = dir
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "benchmark.db"))
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	// One connection: SQLite has a single writer, and the pragmas below are per connection
+	db.SetMaxOpenConns(1)
+	s.db = db
+
+	// WAL with the default synchronous=FULL syncs the log on every commit, like PostgreSQL
+	if _, err := s.db.Exec("PRAGMA journal_mode = WAL"); err != nil {
+		return fmt.Errorf("enable WAL: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLiteBenchmarker) CreateTable(keyType string) error {
+	s.keyType = keyType
+	s.tableName = fmt.Sprintf("bench_%s", keyType)
+	// The index SQLite creates for a PRIMARY KEY that is not the rowid
+	s.indexName = fmt.Sprintf("sqlite_autoindex_%s_1", s.tableName)
+	s.keys = newKeyGenerator(keyType)
+	s.ids = nil
+
+	if _, err := s.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", s.tableName)); err != nil {
+		return fmt.Errorf("drop table: %w", err)
+	}
+
+	// BIGINT, unlike INTEGER, does not make the key an alias of the rowid, so bigserial
+	// gets a separate index like the uuid key types
+	var idType string
+	switch keyType {
+	case "bigserial":
+		idType = "BIGINT"
+	case "uuidv4", "uuidv7", "uuidv1":
+		idType = "BLOB"
+	default:
+		return fmt.Errorf("key type %s is not supported on SQLite (supported: %v)", keyType, KeyTypes)
+	}
+
+	createSQL := fmt.Sprintf(`
+		CREATE TABLE %s (
+			id %s NOT NULL PRIMARY KEY,
+			data TEXT,
+			created_at TEXT DEFAULT CURRENT_TIMESTAMP
+		)
+	`, s.tableName, idType)
+
+	if _, err := s.db.Exec(createSQL); err != nil {
+		return fmt.Errorf("create table: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the database and removes its file
+func (s *SQLiteBenchmarker) Close() error {
+	var err error
+	if s.db != nil {
+		err = s.db.Close()
+	}
+	if s.dir != "" {
+		if rmErr := os.RemoveAll(s.dir); rmErr != nil {
+			fmt.Printf("Warning: Failed to remove %s: %v\n", s.dir, rmErr)
+		}
+	}
+	return err
+}
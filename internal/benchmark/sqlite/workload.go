@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+)
+
+// InsertRecords inserts numRecords rows as transactions of batchSize single-row
+// INSERTs, like the pgbench insert scripts, with data padded to PayloadBytes
+func (s *SQLiteBenchmarker) InsertRecords(numRecords, batchSize int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	batchSize = max(batchSize, 1)
+	query := fmt.Sprintf("INSERT INTO %s (id, data) VALUES (?, ?)", s.tableName)
+
+	latencies := make([]time.Duration, 0, (numRecords+batchSize-1)/batchSize)
+
+	start := time.Now()
+	for first := 0; first < numRecords; first += batchSize {
+		txStart := time.Now()
+
+		tx, err := s.db.Begin()
+		if err != nil {
+			return nil, fmt.Errorf("begin transaction: %w", err)
+		}
+		for n := first + 1; n <= min(first+batchSize, numRecords); n++ {
+			id := s.keys.next()
+			if _, err := tx.Exec(query, id, s.pad(fmt.Sprintf("test_data_%d", n))); err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("insert row: %w", err)
+			}
+			s.ids = append(s.ids, id)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("commit: %w", err)
+		}
+
+		latencies = append(latencies, time.Since(txStart))
+	}
+
+	return result(time.Since(start), numRecords, latencies), nil
+}
+
+// ReadRecords runs numReads primary key point lookups of random inserted rows
+func (s *SQLiteBenchmarker) ReadRecords(numReads int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	query := fmt.Sprintf("SELECT id, data FROM %s WHERE id = ?", s.tableName)
+	return s.runPointOps(numReads, func(id any, _ int) error {
+		var data string
+		if err := s.db.QueryRow(query, id).Scan(new(any), &data); err != nil {
+			return fmt.Errorf("read row: %w", err)
+		}
+		return nil
+	})
+}
+
+// UpdateRecords runs numUpdates single-row updates of random inserted rows' data, one
+// transaction each
+func (s *SQLiteBenchmarker) UpdateRecords(numUpdates int) (*benchmark.ConcurrentBenchmarkResult, error) {
+	query := fmt.Sprintf("UPDATE %s SET data = ? WHERE id = ?", s.tableName)
+	return s.runPointOps(numUpdates, func(id any, n int) error {
+		if _, err := s.db.Exec(query, s.pad(fmt.Sprintf("updated_%d", n)), id); err != nil {
+			return fmt.Errorf("update row: %w", err)
+		}
+		return nil
+	})
+}
+
+// runPointOps runs op numOps times, each on the id of a random inserted row, timing
+// every call as one transaction
+func (s *SQLiteBenchmarker) runPointOps(numOps int, op func(id any, n int) error) (*benchmark.ConcurrentBenchmarkResult, error) {
+	if len(s.ids) == 0 {
+		return nil, fmt.Errorf("table %s is empty", s.tableName)
+	}
+
+	seed := s.opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	latencies := make([]time.Duration, 0, numOps)
+
+	start := time.Now()
+	for n := 1; n <= numOps; n++ {
+		id := s.ids[rng.Intn(len(s.ids))]
+		opStart := time.Now()
+		if err := op(id, n); err != nil {
+			return nil, err
+		}
+		latencies = append(latencies, time.Since(opStart))
+	}
+
+	return result(time.Since(start), numOps, latencies), nil
+}
+
+// pad widens a data value to PayloadBytes, if set; SQLite stores it uncompressed
+func (s *SQLiteBenchmarker) pad(value string) string {
+	if len(value) >= s.opts.PayloadBytes {
+		return value
+	}
+	return value + strings.Repeat("x", s.opts.PayloadBytes-len(value))
+}
+
+// result summarizes a run from its wall time and per-transaction latencies
+func result(duration time.Duration, totalOps int, latencies []time.Duration) *benchmark.ConcurrentBenchmarkResult {
+	return &benchmark.ConcurrentBenchmarkResult{
+		Duration:     duration,
+		TotalOps:     totalOps,
+		Throughput:   float64(totalOps) / duration.Seconds(),
+		Latency:      benchmark.CalculatePercentiles(latencies, benchmark.Percentiles),
+		SuccessCount: len(latencies),
+	}
+}
@@ -0,0 +1,44 @@
+package statistics
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// BootstrapMedianDiffCI estimates the 95% confidence interval of median(b) - median(a)
+// by resampling both groups with replacement iterations times and taking the 2.5th and
+// 97.5th percentiles of the resampled differences. The same seed gives the same
+// interval. Returns 0, 0 if either group is empty or iterations is not positive.
+func BootstrapMedianDiffCI(a, b []float64, iterations int, seed int64) (low, high float64) {
+	if len(a) == 0 || len(b) == 0 || iterations <= 0 {
+		return 0, 0
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	sampleA := make([]float64, len(a))
+	sampleB := make([]float64, len(b))
+	diffs := make([]float64, iterations)
+
+	for i := range diffs {
+		for j := range sampleA {
+			sampleA[j] = a[rng.Intn(len(a))]
+		}
+		for j := range sampleB {
+			sampleB[j] = b[rng.Intn(len(b))]
+		}
+		diffs[i] = Median(sampleB) - Median(sampleA)
+	}
+
+	sort.Float64s(diffs)
+	return quantile(diffs, 0.025), quantile(diffs, 0.975)
+}
+
+// quantile returns the q-quantile of sorted values, interpolating linearly between
+// the closest ranks
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
@@ -0,0 +1,39 @@
+package statistics
+
+import "testing"
+
+func TestBootstrapMedianDiffCI(t *testing.T) {
+	a := []float64{10, 11, 12, 13, 14}
+	b := []float64{20, 21, 22, 23, 24}
+
+	t.Run("covers the median difference", func(t *testing.T) {
+		low, high := BootstrapMedianDiffCI(a, b, 2000, 1)
+		if low > 10 || high < 10 {
+			t.Errorf("CI [%v, %v] does not contain 10", low, high)
+		}
+		if low < 6 || high > 14 {
+			t.Errorf("CI [%v, %v] wider than the groups' spread allows", low, high)
+		}
+	})
+
+	t.Run("reproducible with the same seed", func(t *testing.T) {
+		low1, high1 := BootstrapMedianDiffCI(a, b, 500, 42)
+		low2, high2 := BootstrapMedianDiffCI(a, b, 500, 42)
+		if low1 != low2 || high1 != high2 {
+			t.Errorf("same seed gave [%v, %v] and [%v, %v]", low1, high1, low2, high2)
+		}
+	})
+
+	t.Run("constant groups", func(t *testing.T) {
+		low, high := BootstrapMedianDiffCI([]float64{3, 3, 3}, []float64{5, 5}, 100, 1)
+		if low != 2 || high != 2 {
+			t.Errorf("CI = [%v, %v], want [2, 2]", low, high)
+		}
+	})
+
+	t.Run("empty group", func(t *testing.T) {
+		if low, high := BootstrapMedianDiffCI(nil, b, 100, 1); low != 0 || high != 0 {
+			t.Errorf("CI = [%v, %v], want [0, 0]", low, high)
+		}
+	})
+}
@@ -91,10 +91,29 @@ func normalCDF(z float64) float64 {
 type Comparison struct {
 	MedianDiffPct float64 // Percentage difference in medians
 	PValue        float64 // Mann-Whitney U p-value
+	EffectSize    float64 // Cohen's d (B relative to A)
 	HasOverlap    bool    // Whether ranges overlap
 	Significant   bool    // Whether p < 0.05
 }
 
+// CohensD computes Cohen's d effect size: the difference in means divided by the
+// pooled standard deviation. Returns 0 if the pooled standard deviation is zero.
+func CohensD(statsA, statsB Stats) float64 {
+	n1 := float64(len(statsA.Values))
+	n2 := float64(len(statsB.Values))
+	if n1+n2 <= 2 {
+		return 0
+	}
+
+	pooledVar := ((n1-1)*statsA.StdDev*statsA.StdDev + (n2-1)*statsB.StdDev*statsB.StdDev) / (n1 + n2 - 2)
+	pooledStdDev := math.Sqrt(pooledVar)
+	if pooledStdDev == 0 {
+		return 0
+	}
+
+	return (statsB.Mean - statsA.Mean) / pooledStdDev
+}
+
 // Compare performs statistical comparison between two groups
 func Compare(statsA, statsB Stats) Comparison {
 	medianDiff := 0.0
@@ -108,6 +127,7 @@ func Compare(statsA, statsB Stats) Comparison {
 	return Comparison{
 		MedianDiffPct: medianDiff,
 		PValue:        pValue,
+		EffectSize:    CohensD(statsA, statsB),
 		HasOverlap:    hasOverlap,
 		Significant:   pValue < 0.05,
 	}
@@ -0,0 +1,124 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name   string
+		groupA []float64
+		groupB []float64
+		want   float64
+	}{
+		{"both empty", nil, nil, 1.0},
+		{"A empty", nil, []float64{1, 2, 3}, 1.0},
+		{"B empty", []float64{1, 2, 3}, nil, 1.0},
+		{"all values equal", []float64{5, 5, 5}, []float64{5, 5, 5}, 1.0},
+		{"identical groups", []float64{1, 2, 3}, []float64{1, 2, 3}, 1.0},
+		{"fully separated", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 0.00902343881808032},
+		{"tie across groups gets average rank", []float64{1, 2, 3}, []float64{3, 4, 5}, 0.0808555983700523},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MannWhitneyU(tt.groupA, tt.groupB)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("MannWhitneyU(%v, %v) = %v, want %v", tt.groupA, tt.groupB, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMannWhitneyUSymmetric(t *testing.T) {
+	a := []float64{1, 4, 2, 8}
+	b := []float64{3, 9, 7, 6, 5}
+
+	if ab, ba := MannWhitneyU(a, b), MannWhitneyU(b, a); math.Abs(ab-ba) > 1e-12 {
+		t.Errorf("MannWhitneyU not symmetric: %v vs %v", ab, ba)
+	}
+}
+
+func TestCohensD(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+	}{
+		{"single value each", []float64{1}, []float64{2}, 0},
+		{"zero pooled stddev", []float64{2, 2, 2}, []float64{3, 3, 3}, 0},
+		{"B larger", []float64{1, 2, 3}, []float64{4, 5, 6}, 3},
+		{"B smaller", []float64{4, 5, 6}, []float64{1, 2, 3}, -3},
+		{"same distribution", []float64{1, 2, 3}, []float64{1, 2, 3}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CohensD(Calculate(tt.a), Calculate(tt.b))
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CohensD = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name            string
+		a, b            []float64
+		wantMedianDiff  float64
+		wantOverlap     bool
+		wantSignificant bool
+	}{
+		{
+			name:            "separated groups",
+			a:               []float64{100, 101, 102, 103, 104},
+			b:               []float64{200, 201, 202, 203, 204},
+			wantMedianDiff:  (202.0 - 102.0) / 102.0 * 100,
+			wantOverlap:     false,
+			wantSignificant: true,
+		},
+		{
+			name:            "identical groups",
+			a:               []float64{10, 11, 12},
+			b:               []float64{10, 11, 12},
+			wantMedianDiff:  0,
+			wantOverlap:     true,
+			wantSignificant: false,
+		},
+		{
+			name:            "zero baseline median",
+			a:               []float64{0, 0, 0},
+			b:               []float64{1, 2, 3},
+			wantMedianDiff:  0,
+			wantOverlap:     false,
+			wantSignificant: true,
+		},
+		{
+			// An empty Stats has Min = Max = 0, so it only "overlaps" ranges containing 0
+			name:            "empty baseline",
+			a:               nil,
+			b:               []float64{1, 2, 3},
+			wantMedianDiff:  0,
+			wantOverlap:     false,
+			wantSignificant: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compare(Calculate(tt.a), Calculate(tt.b))
+
+			if math.Abs(got.MedianDiffPct-tt.wantMedianDiff) > 1e-9 {
+				t.Errorf("MedianDiffPct = %v, want %v", got.MedianDiffPct, tt.wantMedianDiff)
+			}
+			if got.HasOverlap != tt.wantOverlap {
+				t.Errorf("HasOverlap = %v, want %v", got.HasOverlap, tt.wantOverlap)
+			}
+			if got.Significant != tt.wantSignificant {
+				t.Errorf("Significant = %v (p=%v), want %v", got.Significant, got.PValue, tt.wantSignificant)
+			}
+		})
+	}
+}
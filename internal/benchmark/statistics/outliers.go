@@ -0,0 +1,55 @@
+package statistics
+
+import "sort"
+
+// minOutlierSamples is the smallest sample whose quartiles are meaningful enough to
+// flag outliers
+const minOutlierSamples = 4
+
+// DetectOutliers returns the indexes of values outside [Q1 - 1.5*IQR, Q3 + 1.5*IQR],
+// in ascending order. Samples smaller than four never have outliers.
+func DetectOutliers(values []float64) []int {
+	if len(values) < minOutlierSamples {
+		return nil
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+	fence := 1.5 * (q3 - q1)
+
+	var outliers []int
+	for i, v := range values {
+		if v < q1-fence || v > q3+fence {
+			outliers = append(outliers, i)
+		}
+	}
+	return outliers
+}
+
+// CalculateTrimmed computes the statistical measures of values without the points
+// DetectOutliers flags, recording how many were removed in OutliersTrimmed. Values
+// keeps every raw value, so exports and rank tests still see all runs.
+func CalculateTrimmed(values []float64) Stats {
+	outliers := DetectOutliers(values)
+	if len(outliers) == 0 {
+		return Calculate(values)
+	}
+
+	kept := make([]float64, 0, len(values)-len(outliers))
+	next := 0
+	for i, v := range values {
+		if next < len(outliers) && outliers[next] == i {
+			next++
+			continue
+		}
+		kept = append(kept, v)
+	}
+
+	stats := Calculate(kept)
+	stats.Values = append([]float64(nil), values...)
+	stats.OutliersTrimmed = len(outliers)
+	return stats
+}
@@ -0,0 +1,47 @@
+package statistics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectOutliers(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   []int
+	}{
+		{"too few values", []float64{1, 2, 100}, nil},
+		{"no outliers", []float64{10, 11, 12, 13, 14}, nil},
+		{"high outlier", []float64{10, 11, 12, 13, 100}, []int{4}},
+		{"low and high outliers", []float64{-50, 10, 11, 12, 13, 14, 100}, []int{0, 6}},
+		{"all equal", []float64{5, 5, 5, 5}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectOutliers(tt.values); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectOutliers(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateTrimmed(t *testing.T) {
+	values := []float64{10, 11, 12, 13, 100}
+	got := CalculateTrimmed(values)
+
+	if got.OutliersTrimmed != 1 {
+		t.Errorf("OutliersTrimmed = %d, want 1", got.OutliersTrimmed)
+	}
+	if !almostEqual(got.Mean, 11.5) || got.Max != 13 {
+		t.Errorf("Mean/Max = %v/%v, want 11.5/13", got.Mean, got.Max)
+	}
+	if !reflect.DeepEqual(got.Values, values) {
+		t.Errorf("Values = %v, want the raw %v", got.Values, values)
+	}
+
+	if clean := CalculateTrimmed([]float64{1, 2, 3, 4}); clean.OutliersTrimmed != 0 || !almostEqual(clean.Mean, 2.5) {
+		t.Errorf("CalculateTrimmed without outliers = %+v, want plain Calculate", clean)
+	}
+}
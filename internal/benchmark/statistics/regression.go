@@ -0,0 +1,38 @@
+package statistics
+
+// LinearFit fits y = intercept + slope*x by ordinary least squares and returns the
+// coefficients with the coefficient of determination R². Returns zeros if fewer than
+// two points are given or all x values are equal.
+func LinearFit(xs, ys []float64) (slope, intercept, rSquared float64) {
+	n := len(xs)
+	if n < 2 || n != len(ys) {
+		return 0, 0, 0
+	}
+
+	meanX := Mean(xs)
+	meanY := Mean(ys)
+
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx := xs[i] - meanX
+		dy := ys[i] - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+
+	if sxx == 0 {
+		return 0, 0, 0
+	}
+
+	slope = sxy / sxx
+	intercept = meanY - slope*meanX
+
+	if syy == 0 {
+		// All y equal: the horizontal line fits perfectly
+		return slope, intercept, 1
+	}
+	rSquared = (sxy * sxy) / (sxx * syy)
+
+	return slope, intercept, rSquared
+}
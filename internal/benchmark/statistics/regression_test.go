@@ -0,0 +1,34 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLinearFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		xs, ys        []float64
+		wantSlope     float64
+		wantIntercept float64
+		wantR2        float64
+	}{
+		{"empty", nil, nil, 0, 0, 0},
+		{"single point", []float64{1}, []float64{2}, 0, 0, 0},
+		{"mismatched lengths", []float64{1, 2}, []float64{1}, 0, 0, 0},
+		{"all x equal", []float64{3, 3, 3}, []float64{1, 2, 3}, 0, 0, 0},
+		{"exact line", []float64{0, 1, 2, 3}, []float64{1, 3, 5, 7}, 2, 1, 1},
+		{"horizontal line", []float64{1, 2, 3}, []float64{4, 4, 4}, 0, 4, 1},
+		{"noisy", []float64{1, 2, 3, 4}, []float64{2, 3, 5, 6}, 1.4, 0.5, 0.98},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slope, intercept, r2 := LinearFit(tt.xs, tt.ys)
+			if math.Abs(slope-tt.wantSlope) > 1e-9 || math.Abs(intercept-tt.wantIntercept) > 1e-9 || math.Abs(r2-tt.wantR2) > 1e-9 {
+				t.Errorf("LinearFit = (%v, %v, %v), want (%v, %v, %v)",
+					slope, intercept, r2, tt.wantSlope, tt.wantIntercept, tt.wantR2)
+			}
+		})
+	}
+}
@@ -7,13 +7,18 @@ import (
 
 // Stats holds statistical measures for a metric
 type Stats struct {
-	Median float64
-	Mean   float64
-	StdDev float64
-	Min    float64
-	Max    float64
-	CV     float64 // Coefficient of Variation (%)
-	Values []float64
+	Median float64   `json:"median"`
+	Mean   float64   `json:"mean"`
+	StdDev float64   `json:"stddev"`
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max"`
+	CV     float64   `json:"cv_percent"` // Coefficient of Variation (%)
+	Values []float64 `json:"values"`
+
+	CI95Low  float64 `json:"ci95_low"` // 95% confidence interval of the mean, t-distribution
+	CI95High float64 `json:"ci95_high"`
+
+	OutliersTrimmed int `json:"outliers_trimmed,omitempty"` // Values excluded from the measures above by CalculateTrimmed
 }
 
 // Median calculates the median of a slice of float64 values
@@ -70,6 +75,39 @@ func CV(values []float64) float64 {
 	return (StdDev(values) / math.Abs(mean)) * 100
 }
 
+// tCritical95 holds the two-sided 95% critical values of Student's t-distribution for
+// 1..30 degrees of freedom
+var tCritical95 = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// TCritical95 returns the two-sided 95% critical value of the t-distribution for df
+// degrees of freedom, using the normal approximation (1.96) beyond 30
+func TCritical95(df int) float64 {
+	if df < 1 {
+		return math.Inf(1)
+	}
+	if df <= len(tCritical95) {
+		return tCritical95[df-1]
+	}
+	return 1.96
+}
+
+// CI95 returns the 95% confidence interval of the mean of values. With fewer than two
+// values the interval collapses to the mean.
+func CI95(values []float64) (low, high float64) {
+	mean := Mean(values)
+	n := len(values)
+	if n < 2 {
+		return mean, mean
+	}
+
+	margin := TCritical95(n-1) * StdDev(values) / math.Sqrt(float64(n))
+	return mean - margin, mean + margin
+}
+
 // Calculate computes all statistical measures for a slice of values
 func Calculate(values []float64) Stats {
 	if len(values) == 0 {
@@ -84,6 +122,8 @@ func Calculate(values []float64) Stats {
 	valuesCopy := make([]float64, len(values))
 	copy(valuesCopy, values)
 
+	ciLow, ciHigh := CI95(values)
+
 	return Stats{
 		Median: Median(values),
 		Mean:   Mean(values),
@@ -92,6 +132,9 @@ func Calculate(values []float64) Stats {
 		Max:    sorted[len(sorted)-1],
 		CV:     CV(values),
 		Values: valuesCopy,
+
+		CI95Low:  ciLow,
+		CI95High: ciHigh,
 	}
 }
 
@@ -0,0 +1,193 @@
+package statistics
+
+import (
+	"math"
+	"testing"
+)
+
+const epsilon = 1e-9
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single", []float64{7}, 7},
+		{"odd count unsorted", []float64{5, 1, 3}, 3},
+		{"even count averages middle pair", []float64{4, 1, 3, 2}, 2.5},
+		{"all equal", []float64{2, 2, 2, 2}, 2},
+		{"negative values", []float64{-3, -1, -2}, -2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Median(tt.values); !almostEqual(got, tt.want) {
+				t.Errorf("Median(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMedianDoesNotReorderInput(t *testing.T) {
+	values := []float64{3, 1, 2}
+	Median(values)
+
+	if values[0] != 3 || values[1] != 1 || values[2] != 2 {
+		t.Errorf("Median modified its input: %v", values)
+	}
+}
+
+func TestMean(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single", []float64{4}, 4},
+		{"several", []float64{1, 2, 3, 4}, 2.5},
+		{"mixed signs", []float64{-2, 2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Mean(tt.values); !almostEqual(got, tt.want) {
+				t.Errorf("Mean(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStdDev(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"single value has no spread", []float64{42}, 0},
+		{"all equal", []float64{3, 3, 3}, 0},
+		{"two values", []float64{1, 3}, math.Sqrt2},
+		{"sample (n-1) denominator", []float64{2, 4, 4, 4, 5, 5, 7, 9}, 2.138089935299395},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StdDev(tt.values); !almostEqual(got, tt.want) {
+				t.Errorf("StdDev(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCV(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"zero mean", []float64{-1, 1}, 0},
+		{"no spread", []float64{5, 5, 5}, 0},
+		{"two values", []float64{1, 3}, math.Sqrt2 / 2 * 100},
+		{"negative mean uses absolute value", []float64{-1, -3}, math.Sqrt2 / 2 * 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CV(tt.values); !almostEqual(got, tt.want) {
+				t.Errorf("CV(%v) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCI95(t *testing.T) {
+	t.Run("t-distribution", func(t *testing.T) {
+		low, high := CI95([]float64{1, 2, 3, 4})
+		margin := 3.182 * math.Sqrt(5.0/3.0) / 2
+		if !almostEqual(low, 2.5-margin) || !almostEqual(high, 2.5+margin) {
+			t.Errorf("CI95 = [%v, %v], want [%v, %v]", low, high, 2.5-margin, 2.5+margin)
+		}
+	})
+
+	t.Run("single value collapses to mean", func(t *testing.T) {
+		low, high := CI95([]float64{7})
+		if low != 7 || high != 7 {
+			t.Errorf("CI95 = [%v, %v], want [7, 7]", low, high)
+		}
+	})
+
+	t.Run("normal approximation beyond 30 degrees of freedom", func(t *testing.T) {
+		if got := TCritical95(100); got != 1.96 {
+			t.Errorf("TCritical95(100) = %v, want 1.96", got)
+		}
+		if got := TCritical95(30); got != 2.042 {
+			t.Errorf("TCritical95(30) = %v, want 2.042", got)
+		}
+	})
+}
+
+func TestCalculate(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		got := Calculate(nil)
+		if got.Median != 0 || got.Mean != 0 || got.Min != 0 || got.Max != 0 || got.Values != nil {
+			t.Errorf("Calculate(nil) = %+v, want zero Stats", got)
+		}
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		values := []float64{4, 1, 3, 2}
+		got := Calculate(values)
+
+		if !almostEqual(got.Median, 2.5) {
+			t.Errorf("Median = %v, want 2.5", got.Median)
+		}
+		if !almostEqual(got.Mean, 2.5) {
+			t.Errorf("Mean = %v, want 2.5", got.Mean)
+		}
+		if got.Min != 1 || got.Max != 4 {
+			t.Errorf("Min/Max = %v/%v, want 1/4", got.Min, got.Max)
+		}
+		if len(got.Values) != len(values) {
+			t.Fatalf("Values has %d entries, want %d", len(got.Values), len(values))
+		}
+
+		values[0] = 100
+		if got.Values[0] != 4 {
+			t.Errorf("Values aliases the input slice")
+		}
+	})
+}
+
+func TestHasOverlap(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Stats
+		want bool
+	}{
+		{"disjoint, A below B", Stats{Min: 1, Max: 2}, Stats{Min: 3, Max: 4}, false},
+		{"disjoint, A above B", Stats{Min: 5, Max: 6}, Stats{Min: 3, Max: 4}, false},
+		{"touching endpoints", Stats{Min: 1, Max: 3}, Stats{Min: 3, Max: 4}, true},
+		{"partial overlap", Stats{Min: 1, Max: 3.5}, Stats{Min: 3, Max: 4}, true},
+		{"B contained in A", Stats{Min: 0, Max: 10}, Stats{Min: 3, Max: 4}, true},
+		{"identical single points", Stats{Min: 2, Max: 2}, Stats{Min: 2, Max: 2}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasOverlap(tt.a, tt.b); got != tt.want {
+				t.Errorf("HasOverlap = %v, want %v", got, tt.want)
+			}
+			if got := HasOverlap(tt.b, tt.a); got != tt.want {
+				t.Errorf("HasOverlap is not symmetric: reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,300 @@
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+// Validator checks collected values against metric.Bounds before they reach the tables,
+// statistics and exports, where a single NaN or Inf (e.g. a rate over a zero duration)
+// would turn every mean and standard deviation into NaN. Impossible values are clamped
+// into bounds (non-finite ones to zero) with a warning; under Strict the first one is
+// reported by Err instead and the value is left as collected.
+type Validator struct {
+	Label  string // Prefix of warnings and errors, e.g. the key type
+	Strict bool
+
+	err error
+}
+
+// Err returns the first violation found under Strict, or nil
+func (v *Validator) Err() error {
+	return v.err
+}
+
+// check returns value, or its clamped replacement if it is out of name's bounds
+func (v *Validator) check(name string, value float64) float64 {
+	lo, hi := metric.Bounds(name)
+
+	clamped := value
+	switch {
+	case math.IsNaN(value), math.IsInf(value, 0):
+		clamped = 0
+	case value < lo:
+		clamped = lo
+	case value > hi:
+		clamped = hi
+	default:
+		return value
+	}
+
+	if v.Strict {
+		if v.err == nil {
+			v.err = fmt.Errorf("%s: impossible %s value %g (valid: %g..%g)", v.Label, name, value, lo, hi)
+		}
+		return value
+	}
+	fmt.Printf("Warning: %s: impossible %s value %g, clamped to %g\n", v.Label, name, value, clamped)
+	return clamped
+}
+
+// Float checks a value stored in the metric's unit
+func (v *Validator) Float(name string, value *float64) {
+	*value = v.check(name, *value)
+}
+
+// Fraction checks a 0..1 ratio of a metric reported in percent
+func (v *Validator) Fraction(name string, value *float64) {
+	*value = v.check(name, *value*100) / 100
+}
+
+// Int checks a count
+func (v *Validator) Int(name string, value *int) {
+	*value = int(v.check(name, float64(*value)))
+}
+
+// Int64 checks a count or byte size
+func (v *Validator) Int64(name string, value *int64) {
+	*value = int64(v.check(name, float64(*value)))
+}
+
+// Duration checks a time span
+func (v *Validator) Duration(name string, value *time.Duration) {
+	*value = time.Duration(v.check(name, float64(*value)))
+}
+
+// Latency checks every percentile of a latency map
+func (v *Validator) Latency(latency map[float64]time.Duration) {
+	for percentile, d := range latency {
+		v.Duration(metric.LatencyName(percentile), &d)
+		latency[percentile] = d
+	}
+}
+
+// Fragmentation checks pgstatindex's leaf statistics
+func (v *Validator) Fragmentation(stats *IndexFragmentationStats) {
+	v.Float("fragmentation", &stats.FragmentationPercent)
+	v.Float("avg_leaf_density", &stats.AvgLeafDensity)
+	v.Int64("leaf_pages", &stats.LeafPages)
+	v.Int64("empty_pages", &stats.EmptyPages)
+}
+
+// Tuples checks pgstattuple's heap breakdown, if it was measured
+func (v *Validator) Tuples(stats *TupleStats) {
+	if stats == nil {
+		return
+	}
+	v.Int64("tuple_count", &stats.TupleCount)
+	v.Float("dead_tuple_percent", &stats.DeadTuplePercent)
+	v.Float("free_percent", &stats.FreePercent)
+}
+
+// ioUsage checks the I/O, temp file and resource fields every workload result carries
+func (v *Validator) ioUsage(readIOPS, writeIOPS, readMB, writeMB *float64, tempFiles, tempBytes *int64, avgCPU, peakCPU, avgRSS, peakRSS *float64) {
+	v.Float("read_iops", readIOPS)
+	v.Float("write_iops", writeIOPS)
+	v.Float("read_throughput_mb", readMB)
+	v.Float("write_throughput_mb", writeMB)
+	v.Int64("temp_files", tempFiles)
+	v.Int64("temp_bytes", tempBytes)
+	v.Float("avg_cpu_percent", avgCPU)
+	v.Float("peak_cpu_percent", peakCPU)
+	v.Float("avg_rss_mb", avgRSS)
+	v.Float("peak_rss_mb", peakRSS)
+}
+
+func (r *InsertPerformanceResult) Validate(v *Validator) {
+	v.Duration("duration", &r.Duration)
+	v.Float("throughput", &r.Throughput)
+	v.Float("tps", &r.TPS)
+	v.Float("tps_including_setup", &r.TPSIncludingSetup)
+	v.Duration("connection_time", &r.ConnectionTime)
+	v.Duration("schedule_lag_avg_us", &r.ScheduleLagAvg)
+	v.Duration("schedule_lag_max_us", &r.ScheduleLagMax)
+	v.Int("skipped_transactions", &r.Skipped)
+	v.Int("page_splits", &r.PageSplits)
+	v.Int64("table_size_mb", &r.TableSize)
+	v.Int64("index_size_mb", &r.IndexSize)
+	v.Int64("index_size_mb", &r.PKIndexSize)
+	v.Float("index_table_ratio", &r.IndexTableRatio)
+	v.Fragmentation(&r.Fragmentation)
+	v.Tuples(r.Tuples)
+	v.Latency(r.Latency)
+	v.Float("write_amplification", &r.WriteAmplification)
+	v.Int64("wal_mb", &r.WALBytes)
+	v.Int64("fpi_mb", &r.FPIBytes)
+	v.Int64("index_pages_dirtied", &r.IndexPagesDirtied)
+	v.Int64("file_pages", &r.FilePages)
+	v.Int64("freelist_pages", &r.FreelistPages)
+	v.ioUsage(&r.ReadIOPS, &r.WriteIOPS, &r.ReadThroughputMB, &r.WriteThroughputMB, &r.TempFiles, &r.TempBytes,
+		&r.AvgCPUPercent, &r.PeakCPUPercent, &r.AvgRSSMB, &r.PeakRSSMB)
+}
+
+func (r *ReadAfterFragmentationResult) Validate(v *Validator) {
+	v.Duration("insert_duration", &r.InsertDuration)
+	v.Duration("duration", &r.ReadDuration)
+	v.Float("throughput", &r.ReadThroughput)
+	for mode, throughput := range r.ModeThroughput {
+		v.Float("read_throughput_"+mode, &throughput)
+		r.ModeThroughput[mode] = throughput
+	}
+	v.Fragmentation(&r.Fragmentation)
+	v.Fraction("buffer_hit_ratio", &r.BufferHitRatio)
+	v.Fraction("index_hit_ratio", &r.IndexBufferHitRatio)
+	v.Float("read_amplification", &r.ReadAmplification)
+	v.Latency(r.Latency)
+	v.ioUsage(&r.ReadIOPS, &r.WriteIOPS, &r.ReadThroughputMB, &r.WriteThroughputMB, &r.TempFiles, &r.TempBytes,
+		&r.AvgCPUPercent, &r.PeakCPUPercent, &r.AvgRSSMB, &r.PeakRSSMB)
+}
+
+func (r *UpdatePerformanceResult) Validate(v *Validator) {
+	v.Duration("duration", &r.UpdateDuration)
+	v.Float("throughput", &r.UpdateThroughput)
+	v.Fragmentation(&r.Fragmentation)
+	v.Float("correlation_before", &r.CorrelationBefore)
+	v.Float("correlation_after", &r.CorrelationAfter)
+	v.Float("correlation_delta", &r.CorrelationDelta)
+	v.Tuples(r.Tuples)
+	v.Tuples(r.VacuumTuples)
+	v.Duration("vacuum_time", &r.VacuumDuration)
+	v.Int64("index_before_vacuum_mb", &r.IndexSizeBefore)
+	v.Int64("index_after_vacuum_mb", &r.IndexSizeAfter)
+	v.Int64("file_pages", &r.FilePages)
+	v.Int64("freelist_pages", &r.FreelistPages)
+	v.Latency(r.Latency)
+	v.ioUsage(&r.ReadIOPS, &r.WriteIOPS, &r.ReadThroughputMB, &r.WriteThroughputMB, &r.TempFiles, &r.TempBytes,
+		&r.AvgCPUPercent, &r.PeakCPUPercent, &r.AvgRSSMB, &r.PeakRSSMB)
+}
+
+func (r *MixedWorkloadResult) Validate(v *Validator) {
+	v.Duration("duration", &r.Duration)
+	v.Int("observed_insert_ops", &r.ObservedInsertOps)
+	v.Int("observed_read_ops", &r.ObservedReadOps)
+	v.Int("observed_update_ops", &r.ObservedUpdateOps)
+	v.Float("throughput", &r.OverallThroughput)
+	v.Float("tps_including_setup", &r.TPSIncludingSetup)
+	v.Duration("connection_time", &r.ConnectionTime)
+	v.Float("insert_throughput", &r.InsertThroughput)
+	v.Float("read_throughput", &r.ReadThroughput)
+	v.Float("update_throughput", &r.UpdateThroughput)
+	v.Latency(r.InsertLatency)
+	v.Latency(r.ReadLatency)
+	v.Latency(r.UpdateLatency)
+	v.Fraction("buffer_hit_ratio", &r.BufferHitRatio)
+	v.Fraction("index_hit_ratio", &r.IndexBufferHitRatio)
+	v.Fragmentation(&r.Fragmentation)
+	v.Int64("table_size_mb", &r.TableSize)
+	v.Int64("index_size_mb", &r.IndexSize)
+	v.ioUsage(&r.ReadIOPS, &r.WriteIOPS, &r.ReadThroughputMB, &r.WriteThroughputMB, &r.TempFiles, &r.TempBytes,
+		&r.AvgCPUPercent, &r.PeakCPUPercent, &r.AvgRSSMB, &r.PeakRSSMB)
+}
+
+func (r *JSONBGinResult) Validate(v *Validator) {
+	v.Duration("duration", &r.Duration)
+	v.Float("throughput", &r.Throughput)
+	v.Int("page_splits", &r.PageSplits)
+	v.Int64("table_size_mb", &r.TableSize)
+	v.Int64("index_size_mb", &r.PKIndexSize)
+	v.Int64("gin_index_size_mb", &r.GinIndexSize)
+	v.Duration("gin_build_time", &r.GinBuildDuration)
+	v.Fragmentation(&r.Fragmentation)
+	v.Int64("temp_bytes", &r.GinBuildTempBytes)
+}
+
+func (r *CacheCompetitionResult) Validate(v *Validator) {
+	v.Float("throughput", &r.ReadThroughput)
+	v.Int64("heap_buffers", &r.HeapBuffers)
+	v.Int64("index_buffers", &r.IndexBuffers)
+	v.Int64("table_size_mb", &r.TableSize)
+	v.Int64("index_size_mb", &r.IndexSize)
+	v.Fraction("buffer_hit_ratio", &r.HeapHitRatio)
+	v.Fraction("index_hit_ratio", &r.IndexHitRatio)
+}
+
+func (r *WorkingSetSweepResult) Validate(v *Validator) {
+	for i := range r.Points {
+		point := &r.Points[i]
+		v.Float("throughput", &point.ReadThroughput)
+		v.Int64("table_size_mb", &point.TableSize)
+		v.Int64("index_size_mb", &point.IndexSize)
+		v.Fraction("buffer_hit_ratio", &point.HeapHitRatio)
+		v.Fraction("index_hit_ratio", &point.IndexHitRatio)
+	}
+}
+
+func (r *ReindexMaintenanceResult) Validate(v *Validator) {
+	for i := range r.Cycles {
+		cycle := &r.Cycles[i]
+		v.Float("fragmentation", &cycle.FragmentationAfter)
+		v.Float("fragmentation", &cycle.FragmentationReset)
+		v.Duration("reindex_time", &cycle.ReindexDuration)
+	}
+	v.Duration("reindex_time", &r.TotalReindexTime)
+	v.Float("fragmentation_regrowth", &r.RegrowthPer100k)
+	v.Int64("index_size_mb", &r.FinalIndexSize)
+	v.Float("fragmentation", &r.FinalFragmentation)
+}
+
+func (r *UpdateChurnResult) Validate(v *Validator) {
+	v.Float("hot_update_ratio", &r.HOTUpdateRatio)
+	for i := range r.Samples {
+		sample := &r.Samples[i]
+		v.Int64("dead_tuples", &sample.DeadTuples)
+		v.Float("table_bloat", &sample.TableBloat)
+		v.Float("index_bloat", &sample.IndexBloat)
+		v.Int64("table_size_mb", &sample.TableSize)
+		v.Int64("index_size_mb", &sample.IndexSize)
+	}
+}
+
+func (r *CommitOverheadResult) Validate(v *Validator) {
+	for i := range r.Throughputs {
+		v.Float("throughput", &r.Throughputs[i])
+	}
+	// The fitted overhead and row cost are regression coefficients that noise can push
+	// below zero; FitRSquared already reports how trustworthy they are
+	v.Float("fit_r2", &r.FitRSquared)
+}
+
+func (r *InsertReturningResult) Validate(v *Validator) {
+	v.Float("throughput", &r.PlainThroughput)
+	v.Float("throughput", &r.ReturningThroughput)
+	v.Latency(r.PlainLatency)
+	v.Latency(r.ReturningLatency)
+}
+
+func (r *UpsertPerformanceResult) Validate(v *Validator) {
+	v.Duration("duration", &r.Duration)
+	v.Float("throughput", &r.Throughput)
+	v.Int64("upserts_inserted", &r.Inserted)
+	v.Int64("upserts_updated", &r.Updated)
+	v.Int("page_splits", &r.PageSplits)
+	v.Int64("table_size_mb", &r.TableSize)
+	v.Int64("index_size_mb", &r.IndexSize)
+	v.Fragmentation(&r.Fragmentation)
+	v.Latency(r.Latency)
+}
+
+func (r *RangeScanResult) Validate(v *Validator) {
+	v.Duration("insert_duration", &r.InsertDuration)
+	v.Duration("duration", &r.ScanDuration)
+	v.Float("throughput", &r.ScanThroughput)
+	v.Float("rows_per_scan", &r.RowsPerScan)
+	v.Float("pages_per_scan", &r.PagesPerScan)
+	v.Fraction("buffer_hit_ratio", &r.BufferHitRatio)
+	v.Fragmentation(&r.Fragmentation)
+	v.Latency(r.Latency)
+}
@@ -5,6 +5,7 @@ import (
 	"log"
 	"os/exec"
 
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/mysql"
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres"
 )
 
@@ -12,32 +13,137 @@ type Config struct {
 	Name         string
 	ComposeFile  string
 	WaitForReady func() error
+	AfterStart   func() error // Optional hook run once the database is ready (e.g. applying tuning)
+	Reset        func() error // Returns a kept container to a fresh state, required for SetKeep
 }
 
 var PostgresConfig = Config{
 	Name:         "PostgreSQL",
 	ComposeFile:  "docker/docker-compose.postgres.yml",
 	WaitForReady: postgres.WaitForReady,
+	Reset:        postgres.ResetForReuse,
 }
 
+var MySQLConfig = Config{
+	Name:         "MySQL",
+	ComposeFile:  "docker/docker-compose.mysql.yml",
+	WaitForReady: mysql.WaitForReady,
+	Reset:        mysql.ResetForReuse,
+}
+
+// SQLiteConfig has no compose file: SQLite runs in-process, so Start and Stop do nothing
+var SQLiteConfig = Config{
+	Name: "SQLite",
+}
+
+// keep makes Start reuse a running container and Stop leave it up, set via SetKeep
+var keep bool
+
+// running is the compose file of the container kept up in keep mode, empty if none
+var running string
+
+// SetKeep makes the container outlive Stop: Start creates it once and afterwards only
+// calls the config's Reset hook, until Release removes it
+func SetKeep(enabled bool) {
+	keep = enabled
+}
+
+// Release removes the container kept up in keep mode, if any
+func Release() {
+	if running == "" {
+		return
+	}
+	composeFile := running
+	running = ""
+	down(composeFile)
+}
+
+// Start starts a fresh container and waits until it is ready, exiting on failure
 func Start(cfg Config) {
+	if err := TryStart(cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// TryStart starts a fresh container and waits until it is ready, returning an error
+// instead of exiting so long-running callers (e.g. the HTTP server) can recover
+func TryStart(cfg Config) error {
+	if cfg.ComposeFile == "" {
+		return nil
+	}
+	if keep && running == cfg.ComposeFile {
+		return reuse(cfg)
+	}
+	// Another compose file (e.g. PgBouncer) cannot reuse the kept container
+	Release()
+	if keep {
+		// A container left up by an earlier, failed run must not leak its tables in
+		exec.Command("docker", "compose", "-f", cfg.ComposeFile, "down", "-v").Run()
+	}
+
 	fmt.Printf("Starting fresh %s container...\n", cfg.Name)
 
 	cmd := exec.Command("docker", "compose", "-f", cfg.ComposeFile, "up", "-d")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		log.Fatalf("Failed to start container: %v\nOutput: %s", err, string(output))
+		return fmt.Errorf("failed to start container: %w\nOutput: %s", err, string(output))
 	}
 
 	fmt.Printf("Waiting for %s to initialize...\n", cfg.Name)
 	if err := cfg.WaitForReady(); err != nil {
-		log.Fatalf("%s failed to start: %v", cfg.Name, err)
+		Stop(cfg.ComposeFile)
+		return fmt.Errorf("%s failed to start: %w", cfg.Name, err)
+	}
+
+	if cfg.AfterStart != nil {
+		if err := cfg.AfterStart(); err != nil {
+			Stop(cfg.ComposeFile)
+			return fmt.Errorf("%s setup failed: %w", cfg.Name, err)
+		}
+	}
+
+	if keep {
+		running = cfg.ComposeFile
+	}
+
+	fmt.Println("Container ready")
+	fmt.Println()
+	return nil
+}
+
+// reuse resets the kept container and reapplies the config's AfterStart hook
+func reuse(cfg Config) error {
+	fmt.Printf("Resetting kept %s container...\n", cfg.Name)
+
+	if err := cfg.Reset(); err != nil {
+		Release()
+		return fmt.Errorf("%s reset failed: %w", cfg.Name, err)
+	}
+
+	if cfg.AfterStart != nil {
+		if err := cfg.AfterStart(); err != nil {
+			Release()
+			return fmt.Errorf("%s setup failed: %w", cfg.Name, err)
+		}
 	}
 
-	fmt.Println("Container ready\n")
+	fmt.Println("Container ready")
+	fmt.Println()
+	return nil
 }
 
+// Stop removes the container, unless it is kept up for reuse
 func Stop(composeFile string) {
+	if composeFile == "" {
+		return
+	}
+	if keep && running == composeFile {
+		return
+	}
+	down(composeFile)
+}
+
+func down(composeFile string) {
 	fmt.Println("\nCleaning up container...")
 
 	cmd := exec.Command("docker", "compose", "-f", composeFile, "down", "-v")
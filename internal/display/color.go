@@ -0,0 +1,121 @@
+package display
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ansiReset = "\033[0m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+)
+
+var colorEnabled = colorSupported()
+
+// colorSupported reports whether stdout is a terminal and NO_COLOR is unset
+func colorSupported() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// SetColor enables or disables ANSI color output. Colors stay disabled when the
+// NO_COLOR environment variable is set or stdout is not a terminal.
+func SetColor(enabled bool) {
+	colorEnabled = enabled && colorSupported()
+}
+
+// Green wraps s in ANSI green when color output is enabled
+func Green(s string) string {
+	return colorize(ansiGreen, s)
+}
+
+// Red wraps s in ANSI red when color output is enabled
+func Red(s string) string {
+	return colorize(ansiRed, s)
+}
+
+func colorize(code, s string) string {
+	if !colorEnabled {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// cellNumber matches the leading number of a table cell and its unit, e.g. "1.2 MB"
+var cellNumber = regexp.MustCompile(`^([+-]?[0-9]+(?:\.[0-9]+)?)\s*([A-Za-z]*)`)
+
+// byteUnits scales the units benchmark.FormatBytes prints
+var byteUnits = map[string]float64{"B": 1, "KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30, "TB": 1 << 40}
+
+// cellValue parses the value a comparison table cell shows, e.g. "1.234s", "950 rec/s",
+// "12.5%" or "1.2 MB", so cells in one row compare even when their units differ
+func cellValue(cell string) (float64, bool) {
+	cell = strings.TrimSpace(cell)
+	if d, err := time.ParseDuration(strings.Fields(cell + " ")[0]); err == nil {
+		return float64(d), true
+	}
+
+	matches := cellNumber.FindStringSubmatch(cell)
+	if matches == nil {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	if scale, ok := byteUnits[matches[2]]; ok {
+		value *= scale
+	}
+	return value, true
+}
+
+// rankColors returns the cells with the best value colored green and the worst red,
+// according to the metric's direction. Cells are returned unchanged unless every one
+// parses and they are not all equal.
+func rankColors(cells []string, higherIsBetter bool) []string {
+	if !colorEnabled || len(cells) < 2 {
+		return cells
+	}
+
+	values := make([]float64, len(cells))
+	for i, cell := range cells {
+		value, ok := cellValue(cell)
+		if !ok {
+			return cells
+		}
+		values[i] = value
+	}
+
+	best, worst := 0, 0
+	for i, value := range values {
+		if (value > values[best]) == higherIsBetter && value != values[best] {
+			best = i
+		}
+		if (value < values[worst]) == higherIsBetter && value != values[worst] {
+			worst = i
+		}
+	}
+	if values[best] == values[worst] {
+		return cells
+	}
+
+	colored := make([]string, len(cells))
+	for i, cell := range cells {
+		switch values[i] {
+		case values[best]:
+			colored[i] = Green(cell)
+		case values[worst]:
+			colored[i] = Red(cell)
+		default:
+			colored[i] = cell
+		}
+	}
+	return colored
+}
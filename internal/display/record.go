@@ -0,0 +1,90 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Table is a comparison table as printed, with each cell's text but without the
+// console's padding and colors, recorded for -markdown-output
+type Table struct {
+	Title   string
+	Notes   []string   // Parameter lines printed between the title and the table
+	Columns []string   // Label column heading, then one heading per key type
+	Rows    [][]string // Row label, then one cell per key type
+}
+
+// recording makes the print helpers also keep every table in tables
+var recording bool
+
+var tables []Table
+
+// RecordTables makes every comparison table printed from now on also be kept, in
+// print order, for Tables
+func RecordTables() {
+	recording = true
+}
+
+// Tables returns the comparison tables printed since RecordTables
+func Tables() []Table {
+	return tables
+}
+
+// current returns the table being printed, or nil when not recording
+func current() *Table {
+	if !recording || len(tables) == 0 {
+		return nil
+	}
+	return &tables[len(tables)-1]
+}
+
+// printTitle prints a table's title line and starts recording a new table
+func printTitle(title string) {
+	fmt.Println(title)
+	recordTitle(title)
+}
+
+// recordTitle starts recording a new table without printing anything, for tables whose
+// console heading does not name them on its own
+func recordTitle(title string) {
+	if recording {
+		tables = append(tables, Table{Title: title})
+	}
+}
+
+// printNote prints a parameter line of the current table
+func printNote(format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+	fmt.Println(line)
+	if t := current(); t != nil {
+		t.Notes = append(t.Notes, line)
+	}
+}
+
+// printHeader prints the column headings, "Metric" and one per key type, and the rule
+// under them
+func printHeader(labelWidth int, keyTypes []string) {
+	fmt.Printf("%-*s", labelWidth, "Metric")
+	columns := []string{"Metric"}
+	for _, keyType := range keyTypes {
+		fmt.Printf("%-20s", strings.ToUpper(keyType))
+		columns = append(columns, strings.ToUpper(keyType))
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 70))
+	recordColumns(columns...)
+}
+
+// recordColumns sets the current table's column headings
+func recordColumns(columns ...string) {
+	if t := current(); t != nil {
+		t.Columns = columns
+	}
+}
+
+// recordRow adds a row of cells to the current table
+func recordRow(cells ...string) {
+	if t := current(); t != nil {
+		t.Rows = append(t.Rows, cells)
+	}
+}
@@ -0,0 +1,111 @@
+package display
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+// DefaultScoreWeights is the metric basket of the efficiency score when -score-weights
+// is not given
+var DefaultScoreWeights = map[string]float64{
+	"throughput":     2,
+	"page_splits":    1,
+	"fragmentation":  1,
+	"index_size_mb":  1,
+	"p99_latency_us": 1,
+}
+
+// maxComponentScore caps a single metric's score, so a baseline median near zero (e.g.
+// BIGSERIAL's fragmentation) cannot let one metric dominate the composite
+const maxComponentScore = 200
+
+// componentScore scores a key type's median against the baseline's as baseline = 100,
+// inverting lower-is-better metrics so that higher is always better
+func componentScore(m metric.Metric, baseline, value float64) float64 {
+	var score float64
+	switch {
+	case baseline == value:
+		score = 100
+	case m.HigherIsBetter && baseline == 0, !m.HigherIsBetter && value == 0:
+		score = maxComponentScore
+	case m.HigherIsBetter:
+		score = 100 * value / baseline
+	default:
+		score = 100 * baseline / value
+	}
+	return math.Max(0, math.Min(score, maxComponentScore))
+}
+
+// EfficiencyScore prints one composite score per key type: the weighted mean of each
+// basket metric's median normalized to the baseline (100 = as good as the baseline,
+// higher is better, each metric capped at 200). Metrics missing from the results are
+// left out and the remaining weights renormalized.
+func EfficiencyScore(results map[string]map[string]statistics.Stats, keyTypes []string, baseline string, weights map[string]float64) {
+	var names []string
+	for name, weight := range weights {
+		if _, ok := results[baseline][name]; ok && weight > 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Println("\nEfficiency Score: no weighted metric was collected, skipped")
+		return
+	}
+	// Registry order, so the columns match the rest of the report
+	order := make(map[string]int)
+	for i, m := range metric.Registry {
+		order[m.Name] = i
+	}
+	sort.Slice(names, func(i, j int) bool { return order[names[i]] < order[names[j]] })
+
+	weightLabels := make([]string, len(names))
+	for i, name := range names {
+		weightLabels[i] = fmt.Sprintf("%s x%g", name, weights[name])
+	}
+
+	fmt.Println()
+	printTitle(fmt.Sprintf("Efficiency Score (%s = 100, medians, higher is better)", strings.ToUpper(baseline)))
+	printNote("Weights: %s", strings.Join(weightLabels, ", "))
+	recordColumns(append([]string{"Key Type", "Score"}, names...)...)
+
+	fmt.Printf("%-16s %7s", "Key Type", "Score")
+	for _, name := range names {
+		fmt.Printf(" %15s", name)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", 24+16*len(names)))
+
+	for _, keyType := range keyTypes {
+		var total, weightSum float64
+		components := make([]string, len(names))
+		for i, name := range names {
+			stats, ok := results[keyType][name]
+			if !ok {
+				components[i] = "-"
+				continue
+			}
+			m, _ := metric.Lookup(name)
+			score := componentScore(m, results[baseline][name].Median, stats.Median)
+			components[i] = fmt.Sprintf("%.1f", score)
+			total += weights[name] * score
+			weightSum += weights[name]
+		}
+
+		composite := "-"
+		if weightSum > 0 {
+			composite = fmt.Sprintf("%.1f", total/weightSum)
+		}
+
+		recordRow(append([]string{strings.ToUpper(keyType), composite}, components...)...)
+		fmt.Printf("%-16s %7s", strings.ToUpper(keyType), composite)
+		for _, component := range components {
+			fmt.Printf(" %15s", component)
+		}
+		fmt.Println()
+	}
+}
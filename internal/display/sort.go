@@ -0,0 +1,55 @@
+package display
+
+import (
+	"slices"
+
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+var sortBy string
+
+// SetSortBy orders the columns of the comparison tables by the named metric, best
+// first. An empty name keeps the order the key types were given in.
+func SetSortBy(name string) {
+	sortBy = name
+}
+
+// metricValuer is implemented by every scenario result
+type metricValuer interface {
+	MetricValue(name string) (float64, bool)
+}
+
+// orderKeyTypes returns keyTypes ordered by the -sort-by metric, best first according to
+// its direction. Ties keep their given order; the order is left unchanged when any key
+// type does not report the metric.
+func orderKeyTypes[R metricValuer](results map[string]R, keyTypes []string) []string {
+	if sortBy == "" {
+		return keyTypes
+	}
+	m, ok := metric.Lookup(sortBy)
+	if !ok {
+		return keyTypes
+	}
+
+	values := make(map[string]float64, len(keyTypes))
+	for _, keyType := range keyTypes {
+		value, ok := results[keyType].MetricValue(sortBy)
+		if !ok {
+			return keyTypes
+		}
+		values[keyType] = value
+	}
+
+	ordered := slices.Clone(keyTypes)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		switch {
+		case values[a] == values[b]:
+			return 0
+		case (values[a] > values[b]) == m.HigherIsBetter:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return ordered
+}
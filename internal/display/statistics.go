@@ -2,54 +2,70 @@ package display
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
 )
 
-func InsertPerformanceStatistics(results map[string]map[string]statistics.Stats, keyTypes []string, numRecords, connections, batchSize, numRuns int) {
+func InsertPerformanceStatistics(results map[string]map[string]statistics.Stats, keyTypes []string, baseline string, numRecords, connections, batchSize, numRuns int) {
 	fmt.Println("\n" + strings.Repeat("=", 100))
-	fmt.Printf("Insert Performance - Statistical Summary (%d runs per UUID type)\n", numRuns)
+	printTitle(fmt.Sprintf("Insert Performance - Statistical Summary (%d runs per UUID type)", numRuns))
 	fmt.Println(strings.Repeat("=", 100))
 
-	fmt.Println("\nThroughput (records/sec)")
-	displayMetricTable(results, keyTypes, "throughput", "%.0f")
-	displayComparisons(results, keyTypes, "throughput")
-
-	fmt.Println("\nPage Splits")
-	displayMetricTable(results, keyTypes, "page_splits", "%.0f")
-	displayComparisons(results, keyTypes, "page_splits")
-
-	fmt.Println("\nIndex Fragmentation (%)")
-	displayMetricTable(results, keyTypes, "fragmentation", "%.2f")
-	displayComparisons(results, keyTypes, "fragmentation")
-
-	fmt.Println("\nTable Size (MB)")
-	displayMetricTable(results, keyTypes, "table_size_mb", "%.1f")
-	displayComparisons(results, keyTypes, "table_size_mb")
-
-	fmt.Println("\nIndex Size (MB)")
-	displayMetricTable(results, keyTypes, "index_size_mb", "%.1f")
-	displayComparisons(results, keyTypes, "index_size_mb")
+	metricSection(results, keyTypes, baseline, "throughput", "%.0f")
+	metricSection(results, keyTypes, baseline, "page_splits", "%.0f")
+	metricSection(results, keyTypes, baseline, "index_pages_dirtied_per_1k", "%.1f")
+	metricSection(results, keyTypes, baseline, "fragmentation", "%.2f")
+	metricSection(results, keyTypes, baseline, "table_size_mb", "%.1f")
+	metricSection(results, keyTypes, baseline, "index_size_mb", "%.1f")
+	for _, percentile := range benchmark.Percentiles {
+		// Latencies are only collected for concurrent runs
+		if _, ok := results[baseline][metric.LatencyName(percentile)]; ok {
+			metricSection(results, keyTypes, baseline, metric.LatencyName(percentile), "%.0f")
+		}
+	}
+	metricSection(results, keyTypes, baseline, "write_iops", "%.0f")
+	metricSection(results, keyTypes, baseline, "wal_mb", "%.1f")
+}
 
-	fmt.Println("\nLatency P99 (µs)")
-	displayMetricTable(results, keyTypes, "p99_latency_us", "%.0f")
-	displayComparisons(results, keyTypes, "p99_latency_us")
+// metricSection prints the summary and comparison tables for one metric, unless it
+// is excluded by the -metrics focus
+func metricSection(results map[string]map[string]statistics.Stats, keyTypes []string, baseline, name, format string) {
+	if !metric.InFocus(name) {
+		return
+	}
 
-	fmt.Println("\nWrite IOPS")
-	displayMetricTable(results, keyTypes, "write_iops", "%.0f")
-	displayComparisons(results, keyTypes, "write_iops")
+	m, _ := metric.Lookup(name)
+	fmt.Println()
+	printTitle(m.Label)
+	displayMetricTable(results, keyTypes, name, format)
+	displayComparisons(results, keyTypes, baseline, name)
 }
 
 func displayMetricTable(results map[string]map[string]statistics.Stats, keyTypes []string, metric, format string) {
-	fmt.Println("┌─────────────┬──────────┬──────────┬──────────┬──────────┬──────────┬───────┐")
-	fmt.Println("│ Key Type    │ Median   │ Mean     │ StdDev   │ Min      │ Max      │ CV %  │")
-	fmt.Println("├─────────────┼──────────┼──────────┼──────────┼──────────┼──────────┼───────┤")
+	fmt.Println("┌─────────────┬──────────┬──────────┬──────────┬──────────┬──────────┬───────┬─────────────────────┐")
+	fmt.Println("│ Key Type    │ Median   │ Mean     │ StdDev   │ Min      │ Max      │ CV %  │ 95% CI (mean)       │")
+	fmt.Println("├─────────────┼──────────┼──────────┼──────────┼──────────┼──────────┼───────┼─────────────────────┤")
+	recordColumns("Key Type", "Median", "Mean", "StdDev", "Min", "Max", "CV %", "95% CI (mean)")
 
 	for _, keyType := range keyTypes {
 		stats := results[keyType][metric]
 
-		fmt.Printf("│ %-11s │ "+format+" │ "+format+" │ "+format+" │ "+format+" │ "+format+" │ %5.1f │\n",
+		ci := fmt.Sprintf(format+" – "+format, stats.CI95Low, stats.CI95High)
+		recordRow(strings.ToUpper(keyType),
+			fmt.Sprintf(format, stats.Median),
+			fmt.Sprintf(format, stats.Mean),
+			fmt.Sprintf(format, stats.StdDev),
+			fmt.Sprintf(format, stats.Min),
+			fmt.Sprintf(format, stats.Max),
+			fmt.Sprintf("%.1f", stats.CV),
+			ci,
+		)
+
+		fmt.Printf("│ %-11s │ "+format+" │ "+format+" │ "+format+" │ "+format+" │ "+format+" │ %5.1f │ %-19s │\n",
 			strings.ToUpper(keyType),
 			stats.Median,
 			stats.Mean,
@@ -57,27 +73,61 @@ func displayMetricTable(results map[string]map[string]statistics.Stats, keyTypes
 			stats.Min,
 			stats.Max,
 			stats.CV,
+			ci,
 		)
 	}
 
-	fmt.Println("└─────────────┴──────────┴──────────┴──────────┴──────────┴──────────┴───────┘")
+	fmt.Println("└─────────────┴──────────┴──────────┴──────────┴──────────┴──────────┴───────┴─────────────────────┘")
+
+	var trimmed []string
+	for _, keyType := range keyTypes {
+		switch n := results[keyType][metric].OutliersTrimmed; {
+		case n == 1:
+			trimmed = append(trimmed, strings.ToUpper(keyType)+" (1 outlier trimmed)")
+		case n > 1:
+			trimmed = append(trimmed, fmt.Sprintf("%s (%d outliers trimmed)", strings.ToUpper(keyType), n))
+		}
+	}
+	if len(trimmed) > 0 {
+		fmt.Printf("Outliers excluded: %s\n", strings.Join(trimmed, ", "))
+	}
+}
+
+// bootstrapIterations is the number of resamples behind the median difference CI, set
+// via SetBootstrapIterations
+var bootstrapIterations = 10000
+
+// bootstrapSeed fixes the resampling, so the same runs always print the same interval
+const bootstrapSeed = 1
+
+// SetBootstrapIterations sets the number of bootstrap resamples of the comparison
+// tables' median difference confidence interval
+func SetBootstrapIterations(iterations int) {
+	bootstrapIterations = iterations
 }
 
-func displayComparisons(results map[string]map[string]statistics.Stats, keyTypes []string, metric string) {
-	fmt.Println("\nStatistical Comparisons (vs BIGSERIAL):")
-	fmt.Println("┌─────────────────────────┬─────────────┬──────────┬───────────┬──────────────┐")
-	fmt.Println("│ Comparison              │ Median Diff │ p-value  │ Overlap?  │ Significant? │")
-	fmt.Println("├─────────────────────────┼─────────────┼──────────┼───────────┼──────────────┤")
+// displayComparisons compares every key type against results[baseline], which may be
+// a reference loaded from a previous run rather than one of keyTypes
+func displayComparisons(results map[string]map[string]statistics.Stats, keyTypes []string, baseline, name string) {
+	fmt.Printf("\nStatistical Comparisons (vs %s):\n", strings.ToUpper(baseline))
+	fmt.Println("┌─────────────────────────┬─────────────┬────────────────────┬──────────┬────────────────────┬───────────┬──────────────┐")
+	fmt.Println("│ Comparison              │ Median Diff │ 95% CI (bootstrap) │ p-value  │ Cohen's d          │ Overlap?  │ Significant? │")
+	fmt.Println("├─────────────────────────┼─────────────┼────────────────────┼──────────┼────────────────────┼───────────┼──────────────┤")
 
-	bigserialStats := results["bigserial"][metric]
+	// The console heading names no metric; the recorded one follows its summary table
+	m, _ := metric.Lookup(name)
+	recordTitle(fmt.Sprintf("%s - Statistical Comparisons (vs %s)", m.Label, strings.ToUpper(baseline)))
+	recordColumns("Comparison", "Median Diff", "95% CI (bootstrap)", "p-value", "Cohen's d", "Overlap?", "Significant?")
+
+	baselineStats := results[baseline][name]
 
 	for _, keyType := range keyTypes {
-		if keyType == "bigserial" {
+		if keyType == baseline {
 			continue
 		}
 
-		stats := results[keyType][metric]
-		comp := statistics.Compare(bigserialStats, stats)
+		stats := results[keyType][name]
+		comp := statistics.Compare(baselineStats, stats)
 
 		significance := ""
 		if !comp.HasOverlap {
@@ -97,14 +147,71 @@ func displayComparisons(results map[string]map[string]statistics.Stats, keyTypes
 			overlap = "Yes"
 		}
 
-		fmt.Printf("│ BIGSERIAL vs %-10s │ %+10.1f%% │ %8.4f │ %-9s │ %-12s │\n",
-			strings.ToUpper(keyType),
+		ci := "-"
+		if baselineStats.Median != 0 {
+			low, high := statistics.BootstrapMedianDiffCI(baselineStats.Values, stats.Values, bootstrapIterations, bootstrapSeed)
+			ci = fmt.Sprintf("%+.1f%% – %+.1f%%", low/baselineStats.Median*100, high/baselineStats.Median*100)
+		}
+
+		recordRow(strings.ToUpper(baseline)+" vs "+strings.ToUpper(keyType),
+			fmt.Sprintf("%+.1f%%", comp.MedianDiffPct),
+			ci,
+			fmt.Sprintf("%.4f", comp.PValue),
+			fmt.Sprintf("%+.2f %s", comp.EffectSize, effectSizeLabel(comp.EffectSize)),
+			overlap,
+			significance,
+		)
+
+		fmt.Printf("│ %-23s │ %+10.1f%% │ %18s │ %8.4f │ %+7.2f %-10s │ %-9s │ %-12s │\n",
+			strings.ToUpper(baseline)+" vs "+strings.ToUpper(keyType),
 			comp.MedianDiffPct,
+			ci,
 			comp.PValue,
+			comp.EffectSize,
+			effectSizeLabel(comp.EffectSize),
 			overlap,
 			significance,
 		)
 	}
 
-	fmt.Println("└─────────────────────────┴─────────────┴──────────┴───────────┴──────────────┘")
+	fmt.Println("└─────────────────────────┴─────────────┴────────────────────┴──────────┴────────────────────┴───────────┴──────────────┘")
+}
+
+// effectSizeLabel names the magnitude of a Cohen's d by the conventional 0.2/0.5/0.8
+// thresholds
+func effectSizeLabel(d float64) string {
+	switch d = math.Abs(d); {
+	case d >= 0.8:
+		return "large"
+	case d >= 0.5:
+		return "medium"
+	case d >= 0.2:
+		return "small"
+	}
+	return "negligible"
+}
+
+// MeasurementStability prints, per metric and key type, the CV of re-measuring the same
+// loaded table (-remeasure, averaged over runs) next to the CV across runs. A metric
+// whose measurement CV approaches its run CV is noisy to collect, not to benchmark.
+// runStats may be nil in single-run mode, leaving the run column empty.
+func MeasurementStability(measurementCV map[string]map[string]float64, runStats map[string]map[string]statistics.Stats, keyTypes []string) {
+	fmt.Println()
+	printTitle("Measurement Stability (CV %: re-measuring the same table / across runs)")
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(22, keyTypes)
+
+	for _, m := range metric.Registry {
+		if _, ok := measurementCV[keyTypes[0]][m.Name]; !ok {
+			continue
+		}
+		printPlainRow(22, m.Name, m.Name, keyTypes, func(keyType string) string {
+			cell := fmt.Sprintf("%.2f%% / ", measurementCV[keyType][m.Name])
+			if stats, ok := runStats[keyType][m.Name]; ok {
+				return cell + fmt.Sprintf("%.2f%%", stats.CV)
+			}
+			return cell + "-"
+		})
+	}
 }
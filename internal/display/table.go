@@ -2,379 +2,1145 @@ package display
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
 )
 
+// printRow prints one metric row of a comparison table, skipping metrics excluded
+// by the -metrics focus. With color output, the row's best value is green and its
+// worst red, by the metric's direction in the registry.
+func printRow(labelWidth int, label, metricName string, keyTypes []string, value func(keyType string) string) {
+	if !metric.InFocus(metricName) {
+		return
+	}
+
+	values := rowValues(keyTypes, value)
+	recordRow(append([]string{label}, values...)...)
+
+	cells := padCells(values)
+	if m, ok := metric.Lookup(metricName); ok {
+		cells = rankColors(cells, m.HigherIsBetter)
+	}
+	fmt.Printf("%-*s%s\n", labelWidth, label, strings.Join(cells, ""))
+}
+
+// printPlainRow is printRow without coloring, for rows whose cells do not follow the
+// metric's direction
+func printPlainRow(labelWidth int, label, metricName string, keyTypes []string, value func(keyType string) string) {
+	if !metric.InFocus(metricName) {
+		return
+	}
+
+	values := rowValues(keyTypes, value)
+	recordRow(append([]string{label}, values...)...)
+
+	fmt.Printf("%-*s%s\n", labelWidth, label, strings.Join(padCells(values), ""))
+}
+
+// rowValues returns each key type's cell text
+func rowValues(keyTypes []string, value func(keyType string) string) []string {
+	values := make([]string, len(keyTypes))
+	for i, keyType := range keyTypes {
+		values[i] = value(keyType)
+	}
+	return values
+}
+
+// padCells pads each value to its column, before any color codes are added
+func padCells(values []string) []string {
+	cells := make([]string, len(values))
+	for i, value := range values {
+		cells[i] = fmt.Sprintf("%-20s", value)
+	}
+	return cells
+}
+
+// printSetupRows prints the DDL/setup breakdown rows when -include-ddl-timing recorded any
+func printSetupRows(labelWidth int, keyTypes []string, setup func(keyType string) benchmark.SetupTiming) {
+	recorded := false
+	for _, keyType := range keyTypes {
+		if setup(keyType).Total() > 0 {
+			recorded = true
+		}
+	}
+	if !recorded {
+		return
+	}
+
+	printRow(labelWidth, "Setup Ext.", "setup_time", keyTypes, func(keyType string) string {
+		return setup(keyType).Extensions.Round(time.Microsecond).String()
+	})
+
+	printRow(labelWidth, "Setup Drop", "setup_time", keyTypes, func(keyType string) string {
+		return setup(keyType).DropTable.Round(time.Microsecond).String()
+	})
+
+	printRow(labelWidth, "Setup Create", "setup_time", keyTypes, func(keyType string) string {
+		return setup(keyType).CreateTable.Round(time.Microsecond).String()
+	})
+}
+
+// printLatencyRows prints one row per -percentiles percentile, e.g. "Latency p99.9",
+// unless no key type collected latencies
+func printLatencyRows(labelWidth int, prefix string, keyTypes []string, latency func(keyType string) map[float64]time.Duration) {
+	recorded := false
+	for _, keyType := range keyTypes {
+		if len(latency(keyType)) > 0 {
+			recorded = true
+		}
+	}
+	if !recorded {
+		return
+	}
+
+	for _, percentile := range benchmark.Percentiles {
+		label := fmt.Sprintf("%s p%s", prefix, strconv.FormatFloat(percentile, 'f', -1, 64))
+		printRow(labelWidth, label, metric.LatencyName(percentile), keyTypes, func(keyType string) string {
+			value, ok := latency(keyType)[percentile]
+			if !ok {
+				return "-"
+			}
+			return value.Round(time.Microsecond).String()
+		})
+	}
+}
+
 // InsertPerformance displays a comparison table for insert performance results
 func InsertPerformance(results map[string]*benchmark.InsertPerformanceResult, keyTypes []string, connections, batchSize int) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
 	fmt.Println()
 	fmt.Println()
-	fmt.Println("COMPARISON - Insert Performance")
+	printTitle("COMPARISON - Insert Performance")
+	timeBound := results[keyTypes[0]].TimeBound
+	if timeBound > 0 {
+		printNote("Duration: %ds, Connections: %d, Batch Size: %d (records inserted per key type below)", timeBound, connections, batchSize)
+	} else {
+		printNote("Records: %d, Connections: %d, Batch Size: %d", results[keyTypes[0]].NumRecords, connections, batchSize)
+	}
+	switch results[keyTypes[0]].InsertMode {
+	case "batch":
+		printNote("Insert Mode: batch (one multi-row INSERT per transaction)")
+	case "copy":
+		printNote("Insert Mode: copy (all rows in one COPY FROM STDIN, connections and batch size unused)")
+	case "sqlite":
+		printNote("Insert Mode: sqlite (single-row INSERTs in-process, batch size per transaction)")
+	}
+	if extra := results[keyTypes[0]].ExtraIndexes; extra > 0 {
+		printNote("Indexes: primary key + %d secondary (index size and page splits summed over all)", extra)
+	}
+	if preloaded := results[keyTypes[0]].Preloaded; preloaded > 0 {
+		printNote("Preloaded: %d rows before the measured inserts (splits, WAL and throughput cover the measured inserts only)", preloaded)
+	}
 	fmt.Println(strings.Repeat("=", 70))
 
-	// Header
-	fmt.Printf("%-15s", "Metric")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", strings.ToUpper(keyType))
+	printHeader(15, keyTypes)
+
+	// Rows each key type got through in the fixed window
+	if timeBound > 0 {
+		printPlainRow(15, "Records", "throughput", keyTypes, func(keyType string) string {
+			return fmt.Sprint(results[keyType].NumRecords)
+		})
 	}
-	fmt.Println()
-	fmt.Println(strings.Repeat("-", 70))
 
 	// Duration
-	fmt.Printf("%-15s", "Duration")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].Duration.Round(time.Millisecond))
-	}
-	fmt.Println()
+	printRow(15, "Duration", "duration", keyTypes, func(keyType string) string {
+		return results[keyType].Duration.Round(time.Millisecond).String()
+	})
 
 	// Throughput
-	fmt.Printf("%-15s", "Throughput")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.0f rec/s", results[keyType].Throughput))
+	printRow(15, "Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f rec/s", results[keyType].Throughput)
+	})
+
+	// pgbench TPS with and without connection setup
+	printRow(15, "TPS", "tps", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f", results[keyType].TPS)
+	})
+
+	printRow(15, "TPS incl. Conn", "tps_including_setup", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f", results[keyType].TPSIncludingSetup)
+	})
+
+	printRow(15, "Conn Time", "connection_time", keyTypes, func(keyType string) string {
+		return results[keyType].ConnectionTime.Round(time.Microsecond).String()
+	})
+
+	// How far pgbench fell behind the -rate schedule
+	if results[keyTypes[0]].Rate > 0 {
+		printRow(15, "Sched Lag Avg", "schedule_lag_avg_us", keyTypes, func(keyType string) string {
+			return results[keyType].ScheduleLagAvg.Round(time.Microsecond).String()
+		})
+
+		printRow(15, "Sched Lag Max", "schedule_lag_max_us", keyTypes, func(keyType string) string {
+			return results[keyType].ScheduleLagMax.Round(time.Microsecond).String()
+		})
+
+		printRow(15, "Skipped", "skipped_transactions", keyTypes, func(keyType string) string {
+			return fmt.Sprint(results[keyType].Skipped)
+		})
 	}
-	fmt.Println()
+
+	// Insert latency percentiles, collected for concurrent runs
+	printLatencyRows(15, "Latency", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].Latency
+	})
 
 	// Page splits
-	fmt.Printf("%-15s", "Page Splits")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20d", results[keyType].PageSplits)
-	}
-	fmt.Println()
+	printRow(15, "Page Splits", "page_splits", keyTypes, func(keyType string) string {
+		return fmt.Sprint(results[keyType].PageSplits)
+	})
+
+	// Write locality: distinct index pages modified per 1000 inserts
+	printRow(15, "Pages/1k Ins.", "index_pages_dirtied_per_1k", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].IndexPagesDirtiedPer1k())
+	})
 
 	// Index size
-	fmt.Printf("%-15s", "Index Size")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", benchmark.FormatBytes(results[keyType].IndexSize))
+	printRow(15, "Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].IndexSize)
+	})
+
+	if results[keyTypes[0]].ExtraIndexes > 0 {
+		printRow(15, "PK Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+			return benchmark.FormatBytes(results[keyType].PKIndexSize)
+		})
 	}
-	fmt.Println()
+
+	// Index bytes per heap byte
+	printRow(15, "Index/Table", "index_table_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].IndexTableRatio)
+	})
 
 	// Fragmentation
-	fmt.Printf("%-15s", "Fragmentation")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent))
-	}
-	fmt.Println()
+	printRow(15, "Fragmentation", "fragmentation", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent)
+	})
 
 	// Leaf density
-	fmt.Printf("%-15s", "Leaf Density")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.AvgLeafDensity))
+	printRow(15, "Leaf Density", "avg_leaf_density", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.AvgLeafDensity)
+	})
+
+	// Heap dead and free space, with -measure-bloat
+	if results[keyTypes[0]].Tuples != nil {
+		printRow(15, "Dead Tuples", "dead_tuple_percent", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.2f%%", results[keyType].Tuples.DeadTuplePercent)
+		})
+
+		printRow(15, "Free Space", "free_percent", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.2f%%", results[keyType].Tuples.FreePercent)
+		})
 	}
-	fmt.Println()
 
 	// Read IOPS
-	fmt.Printf("%-15s", "Read IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].ReadIOPS))
-	}
-	fmt.Println()
+	printRow(15, "Read IOPS", "read_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].ReadIOPS)
+	})
 
 	// Write IOPS
-	fmt.Printf("%-15s", "Write IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].WriteIOPS))
-	}
-	fmt.Println()
+	printRow(15, "Write IOPS", "write_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].WriteIOPS)
+	})
 
 	// Read throughput
-	fmt.Printf("%-15s", "Read MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB))
-	}
-	fmt.Println()
+	printRow(15, "Read MB/s", "read_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB)
+	})
 
 	// Write throughput
-	fmt.Printf("%-15s", "Write MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB))
-	}
-	fmt.Println()
+	printRow(15, "Write MB/s", "write_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB)
+	})
+
+	// Write amplification
+	printRow(15, "Write Amp", "write_amplification", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2fx", results[keyType].WriteAmplification)
+	})
+
+	// WAL volume and its full-page image share
+	printRow(15, "WAL Volume", "wal_mb", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].WALBytes)
+	})
+
+	printRow(15, "WAL FPI", "fpi_mb", keyTypes, func(keyType string) string {
+		r := results[keyType]
+		if r.WALBytes == 0 {
+			return benchmark.FormatBytes(r.FPIBytes)
+		}
+		return fmt.Sprintf("%s (%.0f%%)", benchmark.FormatBytes(r.FPIBytes), float64(r.FPIBytes)/float64(r.WALBytes)*100)
+	})
+
+	// Temp file usage
+	printRow(15, "Temp Bytes", "temp_bytes", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].TempBytes)
+	})
+
+	// CPU usage
+	printRow(15, "CPU Avg", "avg_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].AvgCPUPercent)
+	})
+
+	printRow(15, "CPU Peak", "peak_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].PeakCPUPercent)
+	})
+
+	// Peak memory
+	printRow(15, "Peak RSS", "peak_rss_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f MB", results[keyType].PeakRSSMB)
+	})
+
+	// DDL/setup phase
+	printSetupRows(15, keyTypes, func(keyType string) benchmark.SetupTiming {
+		return results[keyType].Setup
+	})
 }
 
 // ReadAfterFragmentation displays a comparison table for read-after-fragmentation results
 func ReadAfterFragmentation(results map[string]*benchmark.ReadAfterFragmentationResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
 	fmt.Println()
 	fmt.Println()
-	fmt.Println("COMPARISON - Read After Fragmentation")
+	printTitle("COMPARISON - Read After Fragmentation")
 	fmt.Println(strings.Repeat("=", 70))
 
-	// Header
-	fmt.Printf("%-20s", "Metric")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", strings.ToUpper(keyType))
-	}
-	fmt.Println()
-	fmt.Println(strings.Repeat("-", 70))
+	printHeader(20, keyTypes)
 
 	// Duration
-	fmt.Printf("%-20s", "Duration")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].ReadDuration.Round(time.Millisecond))
-	}
-	fmt.Println()
+	printRow(20, "Duration", "duration", keyTypes, func(keyType string) string {
+		return results[keyType].ReadDuration.Round(time.Millisecond).String()
+	})
 
 	// Read throughput
-	fmt.Printf("%-20s", "Read Throughput")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.0f ops/s", results[keyType].ReadThroughput))
+	printRow(20, "Read Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f ops/s", results[keyType].ReadThroughput)
+	})
+
+	// Read throughput per query protocol
+	if results[keyTypes[0]].ModeThroughput != nil {
+		printRow(20, "Reads (simple)", "read_throughput_simple", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.0f ops/s", results[keyType].ModeThroughput["simple"])
+		})
+
+		printRow(20, "Reads (prepared)", "read_throughput_prepared", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.0f ops/s", results[keyType].ModeThroughput["prepared"])
+		})
+
+		printRow(20, "Parse/Plan Share", "parse_plan_share", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.1f%%", results[keyType].ParsePlanShare()*100)
+		})
 	}
-	fmt.Println()
+
+	// Read query plan
+	printRow(20, "Read Plan", "read_plan", keyTypes, func(keyType string) string {
+		if results[keyType].ReadPlan == "" {
+			return "unknown"
+		}
+		if !results[keyType].IndexScan {
+			return "NO INDEX"
+		}
+		return results[keyType].ReadPlan
+	})
 
 	// Buffer hit ratio
-	fmt.Printf("%-20s", "Buffer Hit Ratio")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].BufferHitRatio*100))
-	}
-	fmt.Println()
+	printRow(20, "Buffer Hit Ratio", "buffer_hit_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].BufferHitRatio*100)
+	})
 
 	// Index buffer hit ratio
-	fmt.Printf("%-20s", "Index Hit Ratio")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].IndexBufferHitRatio*100))
-	}
-	fmt.Println()
+	printRow(20, "Index Hit Ratio", "index_hit_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].IndexBufferHitRatio*100)
+	})
 
-	// Fragmentation
-	fmt.Printf("%-20s", "Fragmentation")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent))
-	}
-	fmt.Println()
+	// Read amplification
+	printRow(20, "Read Amp", "read_amplification", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f blk/row", results[keyType].ReadAmplification)
+	})
 
-	// Read latency p50
-	fmt.Printf("%-20s", "Latency p50")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].LatencyP50.Round(time.Microsecond))
-	}
-	fmt.Println()
+	// Fragmentation
+	printRow(20, "Fragmentation", "fragmentation", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent)
+	})
 
-	// Read latency p95
-	fmt.Printf("%-20s", "Latency p95")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].LatencyP95.Round(time.Microsecond))
-	}
-	fmt.Println()
+	// Read latency percentiles
+	printLatencyRows(20, "Latency", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].Latency
+	})
 
 	// Read IOPS
-	fmt.Printf("%-20s", "Read IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].ReadIOPS))
-	}
-	fmt.Println()
+	printRow(20, "Read IOPS", "read_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].ReadIOPS)
+	})
 
 	// Write IOPS
-	fmt.Printf("%-20s", "Write IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].WriteIOPS))
-	}
-	fmt.Println()
+	printRow(20, "Write IOPS", "write_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].WriteIOPS)
+	})
 
 	// Read throughput MB/s
-	fmt.Printf("%-20s", "Read MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB))
-	}
-	fmt.Println()
+	printRow(20, "Read MB/s", "read_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB)
+	})
 
 	// Write throughput MB/s
-	fmt.Printf("%-20s", "Write MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB))
-	}
-	fmt.Println()
+	printRow(20, "Write MB/s", "write_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB)
+	})
+
+	// Temp file usage
+	printRow(20, "Temp Bytes", "temp_bytes", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].TempBytes)
+	})
+
+	// CPU usage
+	printRow(20, "CPU Avg", "avg_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].AvgCPUPercent)
+	})
+
+	printRow(20, "CPU Peak", "peak_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].PeakCPUPercent)
+	})
+
+	// Peak memory
+	printRow(20, "Peak RSS", "peak_rss_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f MB", results[keyType].PeakRSSMB)
+	})
+
+	// DDL/setup phase
+	printSetupRows(20, keyTypes, func(keyType string) benchmark.SetupTiming {
+		return results[keyType].Setup
+	})
 }
 
 // UpdatePerformance displays a comparison table for update performance results
 func UpdatePerformance(results map[string]*benchmark.UpdatePerformanceResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
 	fmt.Println()
 	fmt.Println()
-	fmt.Println("COMPARISON - Update Performance")
+	printTitle("COMPARISON - Update Performance")
+	printNote("Records: %d, Updates: %d, Batch Size: %d", results[keyTypes[0]].NumRecords, results[keyTypes[0]].NumUpdates, results[keyTypes[0]].BatchSize)
 	fmt.Println(strings.Repeat("=", 70))
 
-	// Header
-	fmt.Printf("%-20s", "Metric")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", strings.ToUpper(keyType))
-	}
-	fmt.Println()
-	fmt.Println(strings.Repeat("-", 70))
+	printHeader(20, keyTypes)
 
 	// Duration
-	fmt.Printf("%-20s", "Duration")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].UpdateDuration.Round(time.Millisecond))
-	}
-	fmt.Println()
+	printRow(20, "Duration", "duration", keyTypes, func(keyType string) string {
+		return results[keyType].UpdateDuration.Round(time.Millisecond).String()
+	})
 
 	// Update throughput
-	fmt.Printf("%-20s", "Update Throughput")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.0f ops/s", results[keyType].UpdateThroughput))
-	}
-	fmt.Println()
-
-	// Update latency p50
-	fmt.Printf("%-20s", "Latency p50")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].LatencyP50.Round(time.Microsecond))
-	}
-	fmt.Println()
+	printRow(20, "Update Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f ops/s", results[keyType].UpdateThroughput)
+	})
 
-	// Update latency p95
-	fmt.Printf("%-20s", "Latency p95")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].LatencyP95.Round(time.Microsecond))
-	}
-	fmt.Println()
+	// Update latency percentiles
+	printLatencyRows(20, "Latency", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].Latency
+	})
 
 	// Fragmentation after updates
-	fmt.Printf("%-20s", "Fragmentation")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent))
+	printRow(20, "Fragmentation", "fragmentation", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent)
+	})
+
+	// Heap/index correlation before and after updates
+	printRow(20, "Correlation Before", "correlation_before", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.4f", results[keyType].CorrelationBefore)
+	})
+
+	printRow(20, "Correlation After", "correlation_after", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.4f", results[keyType].CorrelationAfter)
+	})
+
+	printRow(20, "Correlation Delta", "correlation_delta", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%+.4f", results[keyType].CorrelationDelta)
+	})
+
+	// Heap bloat left by the updates and what VACUUM reclaims, with -measure-bloat
+	if results[keyTypes[0]].VacuumTuples != nil {
+		printRow(20, "Dead Tuples", "dead_tuple_percent", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.2f%%", results[keyType].Tuples.DeadTuplePercent)
+		})
+
+		printRow(20, "Free After VACUUM", "free_percent_after_vacuum", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.2f%%", results[keyType].VacuumTuples.FreePercent)
+		})
+
+		printRow(20, "Index Before VACUUM", "index_before_vacuum_mb", keyTypes, func(keyType string) string {
+			return benchmark.FormatBytes(results[keyType].IndexSizeBefore)
+		})
+
+		printRow(20, "Index After VACUUM", "index_after_vacuum_mb", keyTypes, func(keyType string) string {
+			return benchmark.FormatBytes(results[keyType].IndexSizeAfter)
+		})
+
+		printRow(20, "VACUUM Time", "vacuum_time", keyTypes, func(keyType string) string {
+			return results[keyType].VacuumDuration.Round(time.Millisecond).String()
+		})
 	}
-	fmt.Println()
 
 	// Read IOPS
-	fmt.Printf("%-20s", "Read IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].ReadIOPS))
-	}
-	fmt.Println()
+	printRow(20, "Read IOPS", "read_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].ReadIOPS)
+	})
 
 	// Write IOPS
-	fmt.Printf("%-20s", "Write IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].WriteIOPS))
-	}
-	fmt.Println()
+	printRow(20, "Write IOPS", "write_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].WriteIOPS)
+	})
 
 	// Read throughput MB/s
-	fmt.Printf("%-20s", "Read MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB))
-	}
-	fmt.Println()
+	printRow(20, "Read MB/s", "read_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB)
+	})
 
 	// Write throughput MB/s
-	fmt.Printf("%-20s", "Write MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB))
-	}
-	fmt.Println()
+	printRow(20, "Write MB/s", "write_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB)
+	})
+
+	// Temp file usage
+	printRow(20, "Temp Bytes", "temp_bytes", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].TempBytes)
+	})
+
+	// CPU usage
+	printRow(20, "CPU Avg", "avg_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].AvgCPUPercent)
+	})
+
+	printRow(20, "CPU Peak", "peak_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].PeakCPUPercent)
+	})
+
+	// Peak memory
+	printRow(20, "Peak RSS", "peak_rss_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f MB", results[keyType].PeakRSSMB)
+	})
+
+	// DDL/setup phase
+	printSetupRows(20, keyTypes, func(keyType string) benchmark.SetupTiming {
+		return results[keyType].Setup
+	})
 }
 
 // MixedWorkload displays a comparison table for mixed workload results
 func MixedWorkload(results map[string]*benchmark.MixedWorkloadResult, keyTypes []string, workloadName string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
 	fmt.Println()
 	fmt.Println()
-	fmt.Printf("COMPARISON - Mixed Workload: %s\n", workloadName)
+	printTitle("COMPARISON - Mixed Workload: " + workloadName)
+	params := results[keyTypes[0]]
+	printNote("Initial Dataset: %d, Operations: %d", params.NumRecords, params.TotalOps)
 	fmt.Println(strings.Repeat("=", 70))
 
-	// Header
-	fmt.Printf("%-20s", "Metric")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", strings.ToUpper(keyType))
-	}
-	fmt.Println()
-	fmt.Println(strings.Repeat("-", 70))
+	printHeader(20, keyTypes)
 
 	// Duration
-	fmt.Printf("%-20s", "Duration")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", results[keyType].Duration.Round(time.Millisecond))
-	}
-	fmt.Println()
+	printRow(20, "Duration", "duration", keyTypes, func(keyType string) string {
+		return results[keyType].Duration.Round(time.Millisecond).String()
+	})
 
 	// Overall throughput
-	fmt.Printf("%-20s", "Overall Throughput")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.0f ops/s", results[keyType].OverallThroughput))
-	}
-	fmt.Println()
+	printRow(20, "Overall Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f ops/s", results[keyType].OverallThroughput)
+	})
+
+	// TPS including connection setup
+	printRow(20, "TPS incl. Conn", "tps_including_setup", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f ops/s", results[keyType].TPSIncludingSetup)
+	})
+
+	printRow(20, "Conn Time", "connection_time", keyTypes, func(keyType string) string {
+		return results[keyType].ConnectionTime.Round(time.Microsecond).String()
+	})
 
 	// Insert throughput
 	if results[keyTypes[0]].InsertOps > 0 {
-		fmt.Printf("%-20s", "Insert Throughput")
-		for _, keyType := range keyTypes {
-			fmt.Printf("%-20s", fmt.Sprintf("%.0f rec/s", results[keyType].InsertThroughput))
-		}
-		fmt.Println()
+		printRow(20, "Insert Throughput", "insert_throughput", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.0f rec/s", results[keyType].InsertThroughput)
+		})
 	}
 
 	// Read throughput
 	if results[keyTypes[0]].ReadOps > 0 {
-		fmt.Printf("%-20s", "Read Throughput")
-		for _, keyType := range keyTypes {
-			fmt.Printf("%-20s", fmt.Sprintf("%.0f rec/s", results[keyType].ReadThroughput))
-		}
-		fmt.Println()
+		printRow(20, "Read Throughput", "read_throughput", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.0f rec/s", results[keyType].ReadThroughput)
+		})
 	}
 
 	// Update throughput
 	if results[keyTypes[0]].UpdateOps > 0 {
-		fmt.Printf("%-20s", "Update Throughput")
-		for _, keyType := range keyTypes {
-			fmt.Printf("%-20s", fmt.Sprintf("%.0f rec/s", results[keyType].UpdateThroughput))
-		}
-		fmt.Println()
+		printRow(20, "Update Throughput", "update_throughput", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.0f rec/s", results[keyType].UpdateThroughput)
+		})
 	}
 
+	// Per-operation latencies, each from the transactions that ran that operation's script
+	printLatencyRows(20, "Insert", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].InsertLatency
+	})
+
+	printLatencyRows(20, "Read", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].ReadLatency
+	})
+
+	printLatencyRows(20, "Update", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].UpdateLatency
+	})
+
 	// Buffer hit ratio
-	fmt.Printf("%-20s", "Buffer Hit Ratio")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].BufferHitRatio*100))
-	}
-	fmt.Println()
+	printRow(20, "Buffer Hit Ratio", "buffer_hit_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].BufferHitRatio*100)
+	})
 
 	// Index buffer hit ratio
-	fmt.Printf("%-20s", "Index Hit Ratio")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].IndexBufferHitRatio*100))
-	}
-	fmt.Println()
+	printRow(20, "Index Hit Ratio", "index_hit_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].IndexBufferHitRatio*100)
+	})
 
 	// Index size
-	fmt.Printf("%-20s", "Index Size")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", benchmark.FormatBytes(results[keyType].IndexSize))
-	}
+	printRow(20, "Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].IndexSize)
+	})
+
+	// Fragmentation
+	printRow(20, "Fragmentation", "fragmentation", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent)
+	})
+
+	// Read IOPS
+	printRow(20, "Read IOPS", "read_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].ReadIOPS)
+	})
+
+	// Write IOPS
+	printRow(20, "Write IOPS", "write_iops", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].WriteIOPS)
+	})
+
+	// Read throughput MB/s
+	printRow(20, "Read MB/s", "read_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB)
+	})
+
+	// Write throughput MB/s
+	printRow(20, "Write MB/s", "write_throughput_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB)
+	})
+
+	// Temp file usage
+	printRow(20, "Temp Bytes", "temp_bytes", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].TempBytes)
+	})
+
+	// CPU usage
+	printRow(20, "CPU Avg", "avg_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].AvgCPUPercent)
+	})
+
+	printRow(20, "CPU Peak", "peak_cpu_percent", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].PeakCPUPercent)
+	})
+
+	// Peak memory
+	printRow(20, "Peak RSS", "peak_rss_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f MB", results[keyType].PeakRSSMB)
+	})
+
+	// DDL/setup phase
+	printSetupRows(20, keyTypes, func(keyType string) benchmark.SetupTiming {
+		return results[keyType].Setup
+	})
+}
+
+// JSONBGin displays a comparison table for the JSONB payload + GIN index scenario
+func JSONBGin(results map[string]*benchmark.JSONBGinResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
+	fmt.Println()
 	fmt.Println()
+	printTitle("COMPARISON - JSONB Payload with GIN Index")
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(20, keyTypes)
+
+	// Duration
+	printRow(20, "Duration", "duration", keyTypes, func(keyType string) string {
+		return results[keyType].Duration.Round(time.Millisecond).String()
+	})
+
+	// Throughput
+	printRow(20, "Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f rec/s", results[keyType].Throughput)
+	})
+
+	// Page splits (B-tree only)
+	printRow(20, "PK Page Splits", "page_splits", keyTypes, func(keyType string) string {
+		return fmt.Sprint(results[keyType].PageSplits)
+	})
+
+	// PK index size
+	printRow(20, "PK Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].PKIndexSize)
+	})
+
+	// GIN index size
+	printRow(20, "GIN Index Size", "gin_index_size_mb", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].GinIndexSize)
+	})
+
+	// GIN build time
+	printRow(20, "GIN Build Time", "gin_build_time", keyTypes, func(keyType string) string {
+		return results[keyType].GinBuildDuration.Round(time.Millisecond).String()
+	})
+
+	printRow(20, "GIN Build Temp", "temp_bytes", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].GinBuildTempBytes)
+	})
+
+	// Table size
+	printRow(20, "Table Size", "table_size_mb", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].TableSize)
+	})
 
 	// Fragmentation
-	fmt.Printf("%-20s", "Fragmentation")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent))
+	printRow(20, "PK Fragmentation", "fragmentation", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent)
+	})
+}
+
+// CacheCompetition displays the heap/index split of a small buffer pool after reads
+func CacheCompetition(results map[string]*benchmark.CacheCompetitionResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
+	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - Heap vs Index Cache Competition")
+	first := results[keyTypes[0]]
+	printNote("Records: %d, Reads: %d, shared_buffers: %d pages (%.0f MB)",
+		first.NumRecords, first.NumReads, first.SharedBuffers, float64(first.SharedBuffers*8)/1024)
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(20, keyTypes)
+
+	printRow(20, "Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f MB", float64(results[keyType].IndexSize)/(1024*1024))
+	})
+
+	printRow(20, "Heap Buffers", "cache_heap_share", keyTypes, func(keyType string) string {
+		r := results[keyType]
+		return fmt.Sprintf("%d (%.1f%%)", r.HeapBuffers, float64(r.HeapBuffers)/float64(r.SharedBuffers)*100)
+	})
+
+	printRow(20, "Index Buffers", "cache_index_share", keyTypes, func(keyType string) string {
+		r := results[keyType]
+		return fmt.Sprintf("%d (%.1f%%)", r.IndexBuffers, float64(r.IndexBuffers)/float64(r.SharedBuffers)*100)
+	})
+
+	printRow(20, "Heap Hit Ratio", "buffer_hit_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].HeapHitRatio*100)
+	})
+
+	printRow(20, "Index Hit Ratio", "index_hit_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].IndexHitRatio*100)
+	})
+
+	printRow(20, "Read Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f ops/s", results[keyType].ReadThroughput)
+	})
+}
+
+// WorkingSetSweep displays read throughput and hit ratios at each working-set size
+func WorkingSetSweep(results map[string]*benchmark.WorkingSetSweepResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
+	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - Read Throughput vs Working Set / shared_buffers")
+	first := results[keyTypes[0]]
+	printNote("Reads per point: %d, shared_buffers: %.0f MB", first.NumReads, float64(first.SharedBuffers)/(1024*1024))
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(20, keyTypes)
+
+	for i, point := range first.Points {
+		prefix := fmt.Sprintf("%.2fx ", point.Fraction)
+
+		printRow(20, prefix+"Reads/s", "throughput", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.0f ops/s", results[keyType].Points[i].ReadThroughput)
+		})
+
+		printRow(20, prefix+"Heap Hit", "buffer_hit_ratio", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.2f%%", results[keyType].Points[i].HeapHitRatio*100)
+		})
+
+		printRow(20, prefix+"Index Hit", "index_hit_ratio", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.2f%%", results[keyType].Points[i].IndexHitRatio*100)
+		})
+
+		printPlainRow(20, prefix+"Rows", "table_size_mb", keyTypes, func(keyType string) string {
+			p := results[keyType].Points[i]
+			return fmt.Sprintf("%d (%.0f MB)", p.Rows, float64(p.TableSize+p.IndexSize)/(1024*1024))
+		})
 	}
+}
+
+// BatchVsSingle contrasts insert-performance with single-row transactions against the
+// same inserts batched batchSize rows per transaction: batching amortizes commits, so a
+// key type's split gap that persists across both comes from key order, not commit cost
+func BatchVsSingle(single, batched map[string]*benchmark.InsertPerformanceResult, keyTypes []string, batchSize int) {
+	keyTypes = orderKeyTypes(batched, keyTypes)
+
+	fmt.Println()
 	fmt.Println()
+	printTitle("COMPARISON - Single-Row vs Batched Inserts")
+	printNote("Records: %d, Batch Sizes: 1 vs %d", batched[keyTypes[0]].NumRecords, batchSize)
+	fmt.Println(strings.Repeat("=", 70))
 
-	// Read IOPS
-	fmt.Printf("%-20s", "Read IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].ReadIOPS))
+	printHeader(20, keyTypes)
+
+	printRow(20, "Single rec/s", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f", single[keyType].Throughput)
+	})
+
+	printRow(20, fmt.Sprintf("Batch %d rec/s", batchSize), "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f", batched[keyType].Throughput)
+	})
+
+	printRow(20, "Batch Speedup", "throughput", keyTypes, func(keyType string) string {
+		if single[keyType].Throughput == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1fx", batched[keyType].Throughput/single[keyType].Throughput)
+	})
+
+	printRow(20, "Single Splits", "page_splits", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%d", single[keyType].PageSplits)
+	})
+
+	printRow(20, fmt.Sprintf("Batch %d Splits", batchSize), "page_splits", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%d", batched[keyType].PageSplits)
+	})
+
+	printRow(20, "Split Difference", "page_splits", keyTypes, func(keyType string) string {
+		s, b := single[keyType].PageSplits, batched[keyType].PageSplits
+		if s == 0 {
+			return fmt.Sprintf("%+d", b-s)
+		}
+		return fmt.Sprintf("%+d (%+.1f%%)", b-s, float64(b-s)/float64(s)*100)
+	})
+}
+
+// InsertOrder displays uuidv7 inserted under each timestamp progression, keyed by
+// order, next to a uuidv4 reference run keyed "uuidv4"
+func InsertOrder(results map[string]*benchmark.InsertPerformanceResult, orders []string, batchSize int) {
+	columns := orderKeyTypes(results, append(append([]string{}, orders...), "uuidv4"))
+
+	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - UUIDv7 Insert Order (timestamp progression of generated ids)")
+	printNote("Records: %d, Batch Size: %d, Reference: UUIDv4 (random)", results["uuidv4"].NumRecords, batchSize)
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(15, columns)
+
+	printRow(15, "Throughput", "throughput", columns, func(column string) string {
+		return fmt.Sprintf("%.0f rec/s", results[column].Throughput)
+	})
+
+	printRow(15, "Page Splits", "page_splits", columns, func(column string) string {
+		return fmt.Sprint(results[column].PageSplits)
+	})
+
+	printRow(15, "Pages/1k Ins.", "index_pages_dirtied_per_1k", columns, func(column string) string {
+		return fmt.Sprintf("%.1f", results[column].IndexPagesDirtiedPer1k())
+	})
+
+	printRow(15, "Index Size", "index_size_mb", columns, func(column string) string {
+		return benchmark.FormatBytes(results[column].IndexSize)
+	})
+
+	printRow(15, "Fragmentation", "fragmentation", columns, func(column string) string {
+		return fmt.Sprintf("%.2f%%", results[column].Fragmentation.FragmentationPercent)
+	})
+
+	printRow(15, "Leaf Density", "avg_leaf_density", columns, func(column string) string {
+		return fmt.Sprintf("%.2f%%", results[column].Fragmentation.AvgLeafDensity)
+	})
+}
+
+// ConnectionScaling displays connection setup cost per client count. Connections/s that
+// stop growing with clients mark the harness's ceiling: a concurrent scenario plateauing
+// near it is limited by connection setup, not by the key type.
+func ConnectionScaling(result *benchmark.ConnectionScalingResult) {
+	fmt.Println()
+	fmt.Println()
+	printTitle("Connection Establishment Scaling (SELECT 1, independent of key type)")
+	printNote("Transactions per Client Count: %d", result.Transactions)
+	fmt.Println(strings.Repeat("=", 70))
+
+	columns := []string{"Clients", "Initial Connect", "Conn/s (-C)", "Avg Connect", "SELECT 1/s"}
+	fmt.Printf("%-10s%-18s%-15s%-15s%-15s\n", columns[0], columns[1], columns[2], columns[3], columns[4])
+	fmt.Println(strings.Repeat("-", 70))
+	recordColumns(columns...)
+
+	for _, point := range result.Points {
+		cells := []string{
+			fmt.Sprint(point.Clients),
+			point.InitialConnectionTime.Round(time.Microsecond).String(),
+			fmt.Sprintf("%.0f", point.ConnectionsPerSec),
+			point.AvgConnectionTime.Round(time.Microsecond).String(),
+			fmt.Sprintf("%.0f", point.QueryTPS),
+		}
+		fmt.Printf("%-10s%-18s%-15s%-15s%-15s\n", cells[0], cells[1], cells[2], cells[3], cells[4])
+		recordRow(cells...)
 	}
+}
+
+// ReindexMaintenance displays fragmentation regrowth and cumulative reindex cost
+func ReindexMaintenance(results map[string]*benchmark.ReindexMaintenanceResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
 	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - Fragmentation Regrowth with Periodic REINDEX CONCURRENTLY")
+	first := results[keyTypes[0]]
+	printNote("Records: %d in %d bursts, Batch Size: %d", first.NumRecords, len(first.Cycles), first.BatchSize)
+	fmt.Println(strings.Repeat("=", 70))
 
-	// Write IOPS
-	fmt.Printf("%-20s", "Write IOPS")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.1f", results[keyType].WriteIOPS))
+	printHeader(20, keyTypes)
+
+	// Fragmentation reached by each burst
+	for i := range first.Cycles {
+		printRow(20, fmt.Sprintf("Frag. burst %d", i+1), "fragmentation", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.2f%%", results[keyType].Cycles[i].FragmentationAfter)
+		})
 	}
+
+	printRow(20, "Regrowth/100k rows", "fragmentation_regrowth", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f pp", results[keyType].RegrowthPer100k)
+	})
+
+	printRow(20, "Reindex Total", "reindex_time", keyTypes, func(keyType string) string {
+		return results[keyType].TotalReindexTime.Round(time.Millisecond).String()
+	})
+
+	printRow(20, "Reindex Avg", "reindex_time", keyTypes, func(keyType string) string {
+		r := results[keyType]
+		return (r.TotalReindexTime / time.Duration(len(r.Cycles))).Round(time.Millisecond).String()
+	})
+
+	printRow(20, "Final Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f MB", float64(results[keyType].FinalIndexSize)/(1024*1024))
+	})
+}
+
+// UpdateChurn displays the bloat-accumulation curve of repeated update rounds
+func UpdateChurn(results map[string]*benchmark.UpdateChurnResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
 	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - MVCC Bloat under Update Churn")
+	first := results[keyTypes[0]]
+	printNote("Records: %d, Updates per Round: %d, Rounds: %d", first.NumRecords, first.NumUpdates, len(first.Samples)-1)
+	fmt.Println(strings.Repeat("=", 70))
 
-	// Read throughput MB/s
-	fmt.Printf("%-20s", "Read MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].ReadThroughputMB))
+	printHeader(20, keyTypes)
+
+	// One row per sample and measure, the first taken right after loading
+	for i, sample := range first.Samples {
+		printRow(20, fmt.Sprintf("Dead tup @%d", sample.Updates), "dead_tuples", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%d", results[keyType].Samples[i].DeadTuples)
+		})
+	}
+
+	for i, sample := range first.Samples {
+		printRow(20, fmt.Sprintf("Tbl bloat @%d", sample.Updates), "table_bloat", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.1f%%", results[keyType].Samples[i].TableBloat)
+		})
+	}
+
+	for i, sample := range first.Samples {
+		printRow(20, fmt.Sprintf("Idx bloat @%d", sample.Updates), "index_bloat", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.1f%%", results[keyType].Samples[i].IndexBloat)
+		})
 	}
+
+	printRow(20, "Index Growth", "index_growth", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%+.1f%%", results[keyType].IndexGrowth())
+	})
+
+	printRow(20, "Final Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f MB", float64(results[keyType].Final().IndexSize)/(1024*1024))
+	})
+
+	printRow(20, "HOT Updates", "hot_update_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].HOTUpdateRatio)
+	})
+}
+
+// CommitOverhead displays insert throughput per batch size and the fitted commit/row cost split
+func CommitOverhead(results map[string]*benchmark.CommitOverheadResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
 	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - Commit Overhead vs Per-Row Cost")
+	fmt.Println(strings.Repeat("=", 70))
 
-	// Write throughput MB/s
-	fmt.Printf("%-20s", "Write MB/s")
-	for _, keyType := range keyTypes {
-		fmt.Printf("%-20s", fmt.Sprintf("%.2f", results[keyType].WriteThroughputMB))
+	printHeader(20, keyTypes)
+
+	// Throughput per batch size
+	for i, batchSize := range results[keyTypes[0]].BatchSizes {
+		printRow(20, fmt.Sprintf("Batch %d", batchSize), "throughput", keyTypes, func(keyType string) string {
+			return fmt.Sprintf("%.0f rec/s", results[keyType].Throughputs[i])
+		})
 	}
+
+	// Fitted costs
+	printRow(20, "Per-Commit", "commit_overhead", keyTypes, func(keyType string) string {
+		return results[keyType].PerCommitOverhead.Round(time.Microsecond).String()
+	})
+
+	printRow(20, "Per-Row", "row_cost", keyTypes, func(keyType string) string {
+		return results[keyType].PerRowCost.Round(10 * time.Nanosecond).String()
+	})
+
+	printRow(20, "Fit R²", "fit_r2", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.3f", results[keyType].FitRSquared)
+	})
+}
+
+// InsertReturning displays plain vs INSERT ... RETURNING id throughput and latency
+func InsertReturning(results map[string]*benchmark.InsertReturningResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
+	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - INSERT ... RETURNING id")
+	printNote("Records: %d, Connections: %d", results[keyTypes[0]].NumRecords, results[keyTypes[0]].Connections)
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(20, keyTypes)
+
+	printRow(20, "Plain Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f rec/s", results[keyType].PlainThroughput)
+	})
+
+	printRow(20, "RETURNING Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f rec/s", results[keyType].ReturningThroughput)
+	})
+
+	printRow(20, "RETURNING Overhead", "returning_overhead", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].ReturningOverhead())
+	})
+
+	printLatencyRows(20, "Plain", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].PlainLatency
+	})
+
+	printLatencyRows(20, "RETURNING", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].ReturningLatency
+	})
+}
+
+// UpsertPerformance displays INSERT ... ON CONFLICT DO UPDATE throughput alongside the
+// index cost of the new rows and the conflict rate actually observed
+func UpsertPerformance(results map[string]*benchmark.UpsertPerformanceResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
+	fmt.Println()
 	fmt.Println()
+	printTitle("COMPARISON - Upsert Performance (INSERT ... ON CONFLICT DO UPDATE)")
+	first := results[keyTypes[0]]
+	printNote("Records: %d, Upserts: %d, Conflict Ratio: %d%%", first.NumRecords, first.NumUpserts, first.ConflictRatio)
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(20, keyTypes)
+
+	printRow(20, "Duration", "duration", keyTypes, func(keyType string) string {
+		return results[keyType].Duration.Round(time.Millisecond).String()
+	})
+
+	printRow(20, "Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f ops/s", results[keyType].Throughput)
+	})
+
+	// Observed share of upserts that took the DO UPDATE path
+	printRow(20, "Conflict Rate", "conflict_rate", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f%%", results[keyType].ConflictRate())
+	})
+
+	printLatencyRows(20, "Latency", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].Latency
+	})
+
+	printRow(20, "Page Splits", "page_splits", keyTypes, func(keyType string) string {
+		return fmt.Sprint(results[keyType].PageSplits)
+	})
+
+	printRow(20, "Index Size", "index_size_mb", keyTypes, func(keyType string) string {
+		return benchmark.FormatBytes(results[keyType].IndexSize)
+	})
+
+	printRow(20, "Fragmentation", "fragmentation", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent)
+	})
+
+	printRow(20, "Leaf Density", "avg_leaf_density", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.AvgLeafDensity)
+	})
+}
+
+// RangeScan displays range scan throughput alongside the pages each scan touched, which
+// grow when a key range is scattered over the heap
+func RangeScan(results map[string]*benchmark.RangeScanResult, keyTypes []string) {
+	keyTypes = orderKeyTypes(results, keyTypes)
+
+	fmt.Println()
+	fmt.Println()
+	printTitle("COMPARISON - Range Scan Performance")
+	first := results[keyTypes[0]]
+	printNote("Records: %d, Scans: %d, Range Size: %d rows", first.NumRecords, first.NumScans, first.RangeSize)
+	fmt.Println(strings.Repeat("=", 70))
+
+	printHeader(20, keyTypes)
+
+	printRow(20, "Duration", "duration", keyTypes, func(keyType string) string {
+		return results[keyType].ScanDuration.Round(time.Millisecond).String()
+	})
+
+	printRow(20, "Throughput", "throughput", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.0f scans/s", results[keyType].ScanThroughput)
+	})
+
+	printRow(20, "Rows per Scan", "rows_per_scan", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].RowsPerScan)
+	})
+
+	// Heap and index blocks accessed per scan, from shared buffers or not
+	printRow(20, "Pages per Scan", "pages_per_scan", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.1f", results[keyType].PagesPerScan)
+	})
+
+	printRow(20, "Buffer Hit Ratio", "buffer_hit_ratio", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].BufferHitRatio*100)
+	})
+
+	printLatencyRows(20, "Latency", keyTypes, func(keyType string) map[float64]time.Duration {
+		return results[keyType].Latency
+	})
+
+	printRow(20, "Fragmentation", "fragmentation", keyTypes, func(keyType string) string {
+		return fmt.Sprintf("%.2f%%", results[keyType].Fragmentation.FragmentationPercent)
+	})
 }
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+// ToBenchstat writes one Go benchmark result line per key type and run, e.g.
+// "BenchmarkInsert/uuidv4-8  100000  1234 ns/op  81000 rec/s", so runs can be compared
+// with benchstat. ns/op is the run's p50 latency, left out when latencies were not
+// collected (single-connection runs); the -N suffix is the number of connections.
+func ToBenchstat(results map[string]map[string]statistics.Stats, keyTypes []string, numRecords, connections int, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create benchstat file: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, keyType := range keyTypes {
+		throughput := results[keyType]["throughput"].Values
+		latency := results[keyType][metric.LatencyName(50)].Values
+		if len(latency) != len(throughput) {
+			// Some runs lack latencies, so they cannot be matched to their throughput
+			latency = nil
+		}
+
+		for i, recPerSec := range throughput {
+			fmt.Fprintf(writer, "BenchmarkInsert/%s-%d\t%d", keyType, connections, numRecords)
+			if latency != nil {
+				// Latencies are aggregated in microseconds
+				fmt.Fprintf(writer, "\t%.0f ns/op", latency[i]*1000)
+			}
+			fmt.Fprintf(writer, "\t%.2f rec/s\n", recPerSec)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write benchstat file: %w", err)
+	}
+	return nil
+}
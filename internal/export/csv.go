@@ -6,9 +6,51 @@ import (
 	"os"
 	"strings"
 
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
 )
 
+// csvMetrics lists the aggregated metrics written to the CSV exports: every metric
+// aggregateInsertPerformanceResults computes, plus one latency per -percentiles value.
+// Metrics the runs did not report, such as latencies of single-connection runs,
+// schedule lag without -rate or file_pages outside SQLite, are left out.
+var csvMetrics = map[string]bool{
+	"throughput":                 true,
+	"schedule_lag_avg_us":        true,
+	"schedule_lag_max_us":        true,
+	"skipped_transactions":       true,
+	"page_splits":                true,
+	"index_pages_dirtied_per_1k": true,
+	"fragmentation":              true,
+	"avg_leaf_density":           true,
+	"table_size_mb":              true,
+	"index_size_mb":              true,
+	"index_table_ratio":          true,
+	"read_iops":                  true,
+	"write_iops":                 true,
+	"read_throughput_mb":         true,
+	"write_throughput_mb":        true,
+	"write_amplification":        true,
+	"wal_mb":                     true,
+	"fpi_mb":                     true,
+	"avg_cpu_percent":            true,
+	"peak_rss_mb":                true,
+	"file_pages":                 true,
+	"freelist_pages":             true,
+}
+
+// exportedMetrics returns the CSV metrics that are in the -metrics focus, in registry order
+func exportedMetrics() []string {
+	var names []string
+	for _, m := range metric.Registry {
+		if (csvMetrics[m.Name] || metric.IsLatency(m.Name)) && metric.InFocus(m.Name) {
+			names = append(names, m.Name)
+		}
+	}
+	return names
+}
+
 // InsertPerformanceStatsToCSV exports statistical results to CSV format for plotting
 func InsertPerformanceStatsToCSV(results map[string]map[string]statistics.Stats, keyTypes []string, outputPath string) error {
 	file, err := os.Create(outputPath)
@@ -26,21 +68,13 @@ func InsertPerformanceStatsToCSV(results map[string]map[string]statistics.Stats,
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
-	// Metrics to export
-	metrics := []string{
-		"throughput",
-		"page_splits",
-		"fragmentation",
-		"table_size_mb",
-		"index_size_mb",
-		"p99_latency_us",
-		"write_iops",
-	}
-
 	// Write data rows
 	for _, keyType := range keyTypes {
-		for _, metric := range metrics {
-			stats := results[keyType][metric]
+		for _, metric := range exportedMetrics() {
+			stats, ok := results[keyType][metric]
+			if !ok {
+				continue
+			}
 			row := []string{
 				strings.ToUpper(keyType),
 				metric,
@@ -90,21 +124,13 @@ func InsertPerformanceRawRunsToCSV(results map[string]map[string]statistics.Stat
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
-	// Metrics to export
-	metrics := []string{
-		"throughput",
-		"page_splits",
-		"fragmentation",
-		"table_size_mb",
-		"index_size_mb",
-		"p99_latency_us",
-		"write_iops",
-	}
-
 	// Write data rows
 	for _, keyType := range keyTypes {
-		for _, metric := range metrics {
-			stats := results[keyType][metric]
+		for _, metric := range exportedMetrics() {
+			stats, ok := results[keyType][metric]
+			if !ok {
+				continue
+			}
 			row := []string{strings.ToUpper(keyType), metric}
 
 			// Add all run values
@@ -125,3 +151,87 @@ func InsertPerformanceRawRunsToCSV(results map[string]map[string]statistics.Stat
 
 	return nil
 }
+
+// ComparisonsToCSV exports the statistical comparison of every key type against the
+// baseline key type, one row per candidate and metric
+func ComparisonsToCSV(results map[string]map[string]statistics.Stats, keyTypes []string, baseline, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{"Baseline", "Candidate", "Metric", "MedianDiffPct", "PValue", "EffectSize", "Significant"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, keyType := range keyTypes {
+		if keyType == baseline {
+			continue
+		}
+
+		for _, metric := range exportedMetrics() {
+			if _, ok := results[baseline][metric]; !ok {
+				continue
+			}
+			comp := statistics.Compare(results[baseline][metric], results[keyType][metric])
+			row := []string{
+				strings.ToUpper(baseline),
+				strings.ToUpper(keyType),
+				metric,
+				fmt.Sprintf("%.2f", comp.MedianDiffPct),
+				fmt.Sprintf("%.4f", comp.PValue),
+				fmt.Sprintf("%.2f", comp.EffectSize),
+				fmt.Sprintf("%t", comp.Significant),
+			}
+			if err := writer.Write(row); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SizeSamplesToCSV exports each key type's table and index size series sampled during
+// the inserts, one row per sample, for plotting size growth over time
+func SizeSamplesToCSV(results map[string]*benchmark.InsertPerformanceResult, keyTypes []string, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{"KeyType", "ElapsedMs", "TableBytes", "IndexBytes"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, keyType := range keyTypes {
+		result := results[keyType]
+		if result == nil {
+			continue
+		}
+
+		for _, sample := range result.SizeSamples {
+			row := []string{
+				strings.ToUpper(keyType),
+				fmt.Sprintf("%.1f", float64(sample.Elapsed.Microseconds())/1000),
+				fmt.Sprintf("%d", sample.TableSize),
+				fmt.Sprintf("%d", sample.IndexSize),
+			}
+			if err := writer.Write(row); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
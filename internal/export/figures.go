@@ -0,0 +1,110 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+// figureHeader is the gnuplot preamble shared by the -export-figures scripts
+const figureHeader = `# Generated by uuid-benchmark: gnuplot %s
+set terminal pngcairo size 1000,700 font ",11"
+set output "%s.png"
+set grid ytics
+set title %q
+`
+
+// metricLabel returns the registry label of a metric, or its name if unregistered
+func metricLabel(name string) string {
+	if m, ok := metric.Lookup(name); ok {
+		return m.Label
+	}
+	return name
+}
+
+// BarFigure writes <dir>/<name>.dat with one row per key type and one column per
+// metric, and <dir>/<name>.gp, a gnuplot script rendering them as clustered bars into
+// <name>.png. Run it from dir with `gnuplot <name>.gp`.
+func BarFigure(dir, name string, keyTypes, metrics []string, value func(keyType, metric string) float64) (scriptPath string, err error) {
+	var data strings.Builder
+	data.WriteString("# key_type " + strings.Join(metrics, " ") + "\n")
+	for _, keyType := range keyTypes {
+		data.WriteString(strings.ToUpper(keyType))
+		for _, m := range metrics {
+			fmt.Fprintf(&data, " %.4f", value(keyType, m))
+		}
+		data.WriteString("\n")
+	}
+
+	labels := make([]string, len(metrics))
+	for i, m := range metrics {
+		labels[i] = metricLabel(m)
+	}
+
+	var script strings.Builder
+	fmt.Fprintf(&script, figureHeader, name+".gp", name, strings.Join(labels, " / "))
+	script.WriteString(`set style data histograms
+set style histogram clustered
+set style fill solid 0.8 border -1
+set boxwidth 0.8
+set xtics rotate by -30
+set yrange [0:*]
+`)
+	if len(metrics) == 1 {
+		script.WriteString("unset key\n")
+	}
+	plots := make([]string, len(metrics))
+	for i, m := range metrics {
+		plots[i] = fmt.Sprintf("%q using %d:xtic(1) title %q linecolor %d", name+".dat", i+2, metricLabel(m), i+1)
+	}
+	script.WriteString("plot " + strings.Join(plots, ", \\\n     ") + "\n")
+
+	return writeFigure(dir, name, data.String(), script.String())
+}
+
+// LineFigure writes <dir>/<name>.dat with one row per x value and one column per key
+// type, and <dir>/<name>.gp, a gnuplot script charting the metric against x with one
+// line per key type into <name>.png
+func LineFigure(dir, name, xLabel, metricName string, xs []float64, keyTypes []string, value func(keyType string, i int) float64) (scriptPath string, err error) {
+	var data strings.Builder
+	data.WriteString("# " + xLabel)
+	for _, keyType := range keyTypes {
+		data.WriteString(" " + keyType)
+	}
+	data.WriteString("\n")
+	for i, x := range xs {
+		fmt.Fprintf(&data, "%g", x)
+		for _, keyType := range keyTypes {
+			fmt.Fprintf(&data, " %.4f", value(keyType, i))
+		}
+		data.WriteString("\n")
+	}
+
+	var script strings.Builder
+	fmt.Fprintf(&script, figureHeader, name+".gp", name, metricLabel(metricName)+" vs "+xLabel)
+	fmt.Fprintf(&script, "set xlabel %q\nset ylabel %q\nset key outside right\n", xLabel, metricLabel(metricName))
+	plots := make([]string, len(keyTypes))
+	for i, keyType := range keyTypes {
+		plots[i] = fmt.Sprintf("%q using 1:%d with linespoints title %q", name+".dat", i+2, strings.ToUpper(keyType))
+	}
+	script.WriteString("plot " + strings.Join(plots, ", \\\n     ") + "\n")
+
+	return writeFigure(dir, name, data.String(), script.String())
+}
+
+func writeFigure(dir, name, data, script string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create figure directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".dat"), []byte(data), 0644); err != nil {
+		return "", fmt.Errorf("write %s data: %w", name, err)
+	}
+	scriptPath := filepath.Join(dir, name+".gp")
+	if err := os.WriteFile(scriptPath, []byte(script), 0644); err != nil {
+		return "", fmt.Errorf("write %s script: %w", name, err)
+	}
+	return scriptPath, nil
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+	"github.com/moguls753/uuid-benchmark/internal/metric"
+)
+
+// gnuplotMetrics are the charted metrics, in .dat column order after key_type
+var gnuplotMetrics = []string{"throughput", "page_splits", "fragmentation", "index_size_mb"}
+
+// ResultsToGnuplot writes <basePath>.dat with the median of each charted metric per key
+// type and <basePath>.gp, a gnuplot script rendering one bar chart per metric into
+// <basePath>.png. Run it from the output directory with `gnuplot <name>.gp`.
+func ResultsToGnuplot(results map[string]map[string]statistics.Stats, keyTypes []string, basePath string) (datPath, scriptPath string, err error) {
+	datPath = basePath + ".dat"
+	scriptPath = basePath + ".gp"
+
+	var data strings.Builder
+	data.WriteString("# key_type " + strings.Join(gnuplotMetrics, " ") + "\n")
+	for _, keyType := range keyTypes {
+		data.WriteString(strings.ToUpper(keyType))
+		for _, name := range gnuplotMetrics {
+			fmt.Fprintf(&data, " %.4f", results[keyType][name].Median)
+		}
+		data.WriteString("\n")
+	}
+	if err := os.WriteFile(datPath, []byte(data.String()), 0644); err != nil {
+		return "", "", fmt.Errorf("write gnuplot data: %w", err)
+	}
+
+	datName := filepath.Base(datPath)
+	var script strings.Builder
+	fmt.Fprintf(&script, `# Generated by uuid-benchmark: gnuplot %s
+set terminal pngcairo size 1400,1000 font ",10"
+set output "%s.png"
+set style data histograms
+set style histogram clustered
+set style fill solid 0.8 border -1
+set boxwidth 0.8
+set grid ytics
+set xtics rotate by -30
+unset key
+set yrange [0:*]
+set multiplot layout 2,2 title "UUID Benchmark - median of runs"
+`, filepath.Base(scriptPath), filepath.Base(basePath))
+	for i, name := range gnuplotMetrics {
+		label := name
+		if m, ok := metric.Lookup(name); ok {
+			label = m.Label
+		}
+		fmt.Fprintf(&script, "set title %q\nplot %q using %d:xtic(1) linecolor %d\n", label, datName, i+2, i+1)
+	}
+	script.WriteString("unset multiplot\n")
+
+	if err := os.WriteFile(scriptPath, []byte(script.String()), 0644); err != nil {
+		return "", "", fmt.Errorf("write gnuplot script: %w", err)
+	}
+
+	return datPath, scriptPath, nil
+}
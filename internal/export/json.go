@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+)
+
+// StatsDocument is the JSON representation of a statistical summary
+type StatsDocument struct {
+	Scenario string                                 `json:"scenario"`
+	KeyTypes []string                               `json:"key_types"`
+	Results  map[string]map[string]statistics.Stats `json:"results"`
+	Settings map[string]string                      `json:"settings,omitempty"` // Run settings affecting results, e.g. autovacuum
+}
+
+// InsertPerformanceStatsToJSON exports statistical results to JSON for later comparison
+func InsertPerformanceStatsToJSON(results map[string]map[string]statistics.Stats, keyTypes []string, settings map[string]string, outputPath string) error {
+	doc := StatsDocument{
+		Scenario: "insert-performance",
+		KeyTypes: keyTypes,
+		Results:  results,
+		Settings: settings,
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadStatsJSON reads a statistical summary previously written by InsertPerformanceStatsToJSON
+func LoadStatsJSON(path string) (*StatsDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON file: %w", err)
+	}
+
+	var doc StatsDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	return &doc, nil
+}
+
+// ResultsToJSON writes raw benchmark results, e.g. a map from scenario to a map from key
+// type to *benchmark.InsertPerformanceResult, as one JSON document. Fields keep their Go
+// names and durations are written as nanoseconds.
+func ResultsToJSON(results any, path string) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON file: %w", err)
+	}
+
+	return nil
+}
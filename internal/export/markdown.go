@@ -0,0 +1,48 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/moguls753/uuid-benchmark/internal/display"
+)
+
+// TablesToMarkdown writes comparison tables recorded by the display package as
+// GitHub-flavored Markdown: a heading per table, its parameter lines, then the table with
+// every cell as the console prints it, units included
+func TablesToMarkdown(tables []display.Table, path string) error {
+	var b strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", strings.TrimPrefix(table.Title, "COMPARISON - "))
+		for _, note := range table.Notes {
+			fmt.Fprintf(&b, "%s\n\n", note)
+		}
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		writeMarkdownRow(&b, table.Columns)
+		b.WriteString("|" + strings.Repeat(" --- |", len(table.Columns)) + "\n")
+		for _, row := range table.Rows {
+			writeMarkdownRow(&b, row)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write Markdown file: %w", err)
+	}
+	return nil
+}
+
+// writeMarkdownRow writes one table row, escaping pipes that would split a cell
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	b.WriteString("|")
+	for _, cell := range cells {
+		fmt.Fprintf(b, " %s |", strings.ReplaceAll(cell, "|", `\|`))
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,143 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
+	"github.com/moguls753/uuid-benchmark/internal/sysinfo"
+
+	_ "modernc.org/sqlite"
+)
+
+// RunInfo describes one benchmark invocation stored in the results database
+type RunInfo struct {
+	Scenario    string
+	NumRecords  int
+	Connections int
+	BatchSize   int
+	NumRuns     int
+	Host        sysinfo.Fingerprint
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_date    TEXT NOT NULL,
+	scenario    TEXT NOT NULL,
+	num_records INTEGER,
+	connections INTEGER,
+	batch_size  INTEGER,
+	num_runs    INTEGER,
+	hostname    TEXT,
+	os          TEXT,
+	arch        TEXT,
+	kernel      TEXT,
+	cpus        INTEGER,
+	memory_mb   INTEGER,
+	go_version  TEXT
+);
+
+CREATE TABLE IF NOT EXISTS results (
+	run_id     INTEGER NOT NULL REFERENCES runs(id),
+	key_type   TEXT NOT NULL,
+	metric     TEXT NOT NULL,
+	median     REAL,
+	mean       REAL,
+	stddev     REAL,
+	min        REAL,
+	max        REAL,
+	cv_percent REAL,
+	n          INTEGER,
+	PRIMARY KEY (run_id, key_type, metric)
+);
+
+CREATE TABLE IF NOT EXISTS comparisons (
+	run_id          INTEGER NOT NULL REFERENCES runs(id),
+	baseline        TEXT NOT NULL,
+	key_type        TEXT NOT NULL,
+	metric          TEXT NOT NULL,
+	median_diff_pct REAL,
+	p_value         REAL,
+	effect_size     REAL,
+	has_overlap     INTEGER,
+	significant     INTEGER,
+	PRIMARY KEY (run_id, key_type, metric)
+);
+`
+
+// ResultsToSQLite appends one run's statistics and comparisons vs baseline to a SQLite
+// database, creating the schema if the file is new
+func ResultsToSQLite(results map[string]map[string]statistics.Stats, keyTypes []string, baseline string, run RunInfo, dbPath string) error {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`INSERT INTO runs (run_date, scenario, num_records, connections, batch_size, num_runs,
+		hostname, os, arch, kernel, cpus, memory_mb, go_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC().Format(time.RFC3339), run.Scenario, run.NumRecords, run.Connections, run.BatchSize, run.NumRuns,
+		run.Host.Hostname, run.Host.OS, run.Host.Arch, run.Host.Kernel, run.Host.CPUs, run.Host.MemoryMB, run.Host.GoVersion)
+	if err != nil {
+		return fmt.Errorf("failed to insert run: %w", err)
+	}
+
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to read run id: %w", err)
+	}
+
+	for _, keyType := range keyTypes {
+		for _, metric := range sortedMetrics(results[keyType]) {
+			stats := results[keyType][metric]
+			_, err := tx.Exec(`INSERT INTO results (run_id, key_type, metric, median, mean, stddev, min, max, cv_percent, n)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				runID, keyType, metric, stats.Median, stats.Mean, stats.StdDev, stats.Min, stats.Max, stats.CV, len(stats.Values))
+			if err != nil {
+				return fmt.Errorf("failed to insert result %s/%s: %w", keyType, metric, err)
+			}
+
+			if keyType == baseline {
+				continue
+			}
+
+			comp := statistics.Compare(results[baseline][metric], stats)
+			_, err = tx.Exec(`INSERT INTO comparisons (run_id, baseline, key_type, metric, median_diff_pct, p_value, effect_size, has_overlap, significant)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				runID, baseline, keyType, metric, comp.MedianDiffPct, comp.PValue, comp.EffectSize, comp.HasOverlap, comp.Significant)
+			if err != nil {
+				return fmt.Errorf("failed to insert comparison %s/%s: %w", keyType, metric, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit results: %w", err)
+	}
+
+	return nil
+}
+
+// sortedMetrics returns the metric names of a stats map in a stable order
+func sortedMetrics(stats map[string]statistics.Stats) []string {
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
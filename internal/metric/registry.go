@@ -0,0 +1,208 @@
+package metric
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Metric describes a metric as it appears in the comparison tables, the aggregated
+// stats map, and the exports
+type Metric struct {
+	Name           string // Key used in the stats map, CSV/JSON exports and -metrics
+	Label          string // Human-readable label for tables
+	HigherIsBetter bool
+	Unit           string // Unit of the value in the stats map and exports
+	Description    string // One-line explanation, shown by -list-metrics
+}
+
+// Direction returns "higher" or "lower", whichever is better for the metric
+func (m Metric) Direction() string {
+	if m.HigherIsBetter {
+		return "higher"
+	}
+	return "lower"
+}
+
+// Registry lists every reported metric in display order
+var Registry = []Metric{
+	{Name: "duration", Label: "Duration", Unit: "duration", Description: "Wall-clock time of the measured workload phase"},
+	{Name: "throughput", Label: "Throughput (records/sec)", HigherIsBetter: true, Unit: "records/s", Description: "Rows (or operations) completed per second in the measured phase"},
+	{Name: "tps", Label: "TPS", HigherIsBetter: true, Unit: "tx/s", Description: "pgbench transactions per second, excluding connection setup"},
+	{Name: "tps_including_setup", Label: "TPS incl. Connection Setup", HigherIsBetter: true, Unit: "tx/s", Description: "pgbench transactions per second including initial connection time"},
+	{Name: "connection_time", Label: "Initial Connection Time", Unit: "duration", Description: "Time pgbench spent establishing client connections"},
+	{Name: "schedule_lag_avg_us", Label: "Schedule Lag Avg (µs)", Unit: "µs", Description: "Under -rate, mean delay between a transaction's scheduled and actual start"},
+	{Name: "schedule_lag_max_us", Label: "Schedule Lag Max (µs)", Unit: "µs", Description: "Under -rate, largest delay between a transaction's scheduled and actual start"},
+	{Name: "skipped_transactions", Label: "Skipped Transactions", Unit: "count", Description: "Under -rate with -latency-limit, transactions skipped for starting too late"},
+	{Name: "insert_throughput", Label: "Insert Throughput (rec/sec)", HigherIsBetter: true, Unit: "records/s", Description: "Insert rate within a mixed workload"},
+	{Name: "read_throughput", Label: "Read Throughput (rec/sec)", HigherIsBetter: true, Unit: "records/s", Description: "Read rate within a mixed workload"},
+	{Name: "update_throughput", Label: "Update Throughput (rec/sec)", HigherIsBetter: true, Unit: "records/s", Description: "Update rate within a mixed workload"},
+	{Name: "commit_overhead", Label: "Per-Commit Overhead", Unit: "µs", Description: "Fitted fixed cost per transaction (commit-overhead scenario)"},
+	{Name: "row_cost", Label: "Per-Row Cost", Unit: "µs", Description: "Fitted marginal cost per inserted row (commit-overhead scenario)"},
+	{Name: "fit_r2", Label: "Fit R²", HigherIsBetter: true, Unit: "ratio", Description: "Goodness of fit of time/row = overhead/batch + rowCost"},
+	{Name: "returning_overhead", Label: "RETURNING Overhead (%)", Unit: "%", Description: "Insert throughput lost by returning the generated id (insert-returning scenario)"},
+	{Name: "conflict_rate", Label: "Conflict Rate (%)", Unit: "%", Description: "Share of upserts that hit an existing id and updated it (upsert-performance scenario)"},
+	{Name: "page_splits", Label: "Page Splits", Unit: "count", Description: "B-tree leaf page splits during inserts, counted from WAL records"},
+	{Name: "index_pages_dirtied_per_1k", Label: "Index Pages Dirtied per 1k Inserts", Unit: "pages/1k rows", Description: "Distinct B-tree pages modified by the measured inserts per 1000 rows, from WAL block references (write locality)"},
+	{Name: "fragmentation", Label: "Index Fragmentation (%)", Unit: "%", Description: "pgstatindex leaf_fragmentation: share of leaf pages out of logical order"},
+	{Name: "avg_leaf_density", Label: "Avg Leaf Density (%)", HigherIsBetter: true, Unit: "%", Description: "pgstatindex avg_leaf_density: how full the index leaf pages are"},
+	{Name: "table_size_mb", Label: "Table Size (MB)", Unit: "MB", Description: "Heap size of the benchmark table (pg_table_size)"},
+	{Name: "index_size_mb", Label: "Index Size (MB)", Unit: "MB", Description: "Size of all indexes on the table (pg_indexes_size)"},
+	{Name: "file_pages", Label: "File Pages", Unit: "pages", Description: "Pages of the SQLite database file (PRAGMA page_count)"},
+	{Name: "freelist_pages", Label: "Freelist Pages", Unit: "pages", Description: "Pages of the SQLite database file allocated but unused (PRAGMA freelist_count)"},
+	{Name: "index_table_ratio", Label: "Index/Table Size Ratio", Unit: "ratio", Description: "Index size divided by heap size: the storage the key's indexes cost per byte of rows"},
+	{Name: "gin_index_size_mb", Label: "GIN Index Size (MB)", Unit: "MB", Description: "Size of the JSONB payload GIN index (jsonb-gin scenario)"},
+	{Name: "gin_build_time", Label: "GIN Build Time", Unit: "duration", Description: "Time to rebuild the GIN index (jsonb-gin scenario)"},
+	{Name: "fragmentation_regrowth", Label: "Fragmentation Regrowth (pp/100k rows)", Unit: "pp/100k rows", Description: "Fragmentation re-accumulated after each REINDEX per 100k rows inserted"},
+	{Name: "reindex_time", Label: "REINDEX CONCURRENTLY Time", Unit: "duration", Description: "Time spent in REINDEX INDEX CONCURRENTLY (reindex-maintenance scenario)"},
+	{Name: "read_throughput_simple", Label: "Read Throughput, simple protocol", HigherIsBetter: true, Unit: "ops/s", Description: "Read rate with pgbench -M simple (re-parsed every statement)"},
+	{Name: "read_throughput_prepared", Label: "Read Throughput, prepared protocol", HigherIsBetter: true, Unit: "ops/s", Description: "Read rate with pgbench -M prepared (parsed once per connection)"},
+	{Name: "parse_plan_share", Label: "Parse/Plan Share of Read Time (%)", Unit: "%", Description: "Share of per-read time spent parsing and planning: 1 - simple/prepared"},
+	{Name: "read_plan", Label: "Read Plan", HigherIsBetter: true, Unit: "plan node", Description: "Root EXPLAIN node of the read query; NO INDEX means results are not comparable"},
+	{Name: "cache_heap_share", Label: "Heap Share of shared_buffers (%)", HigherIsBetter: true, Unit: "%", Description: "Buffers holding heap pages after reads under a small buffer pool"},
+	{Name: "cache_index_share", Label: "Index Share of shared_buffers (%)", Unit: "%", Description: "Buffers holding primary key index pages after reads under a small buffer pool"},
+	{Name: "buffer_hit_ratio", Label: "Buffer Hit Ratio (%)", HigherIsBetter: true, Unit: "%", Description: "Database-wide share of block reads served from shared_buffers"},
+	{Name: "index_hit_ratio", Label: "Index Hit Ratio (%)", HigherIsBetter: true, Unit: "%", Description: "Share of the table's index block reads served from shared_buffers"},
+	{Name: "correlation_before", Label: "Correlation Before Updates", HigherIsBetter: true, Unit: "ratio", Description: "pg_stats correlation of id with physical row order before updates (1 = clustered)"},
+	{Name: "correlation_after", Label: "Correlation After Updates", HigherIsBetter: true, Unit: "ratio", Description: "pg_stats correlation of id with physical row order after updates"},
+	{Name: "correlation_delta", Label: "Correlation Delta", HigherIsBetter: true, Unit: "ratio", Description: "Change in id correlation caused by the updates"},
+	{Name: "dead_tuple_percent", Label: "Dead Tuple Space (%)", Unit: "%", Description: "pgstattuple dead_tuple_percent after the workload (-measure-bloat)"},
+	{Name: "free_percent", Label: "Free Heap Space (%)", Unit: "%", Description: "pgstattuple free_percent after the workload (-measure-bloat)"},
+	{Name: "free_percent_after_vacuum", Label: "Free Heap Space After VACUUM (%)", Unit: "%", Description: "pgstattuple free_percent once VACUUM reclaimed the dead tuples (-measure-bloat)"},
+	{Name: "index_before_vacuum_mb", Label: "Index Size Before VACUUM (MB)", Unit: "MB", Description: "Size of all indexes after the update workload, before VACUUM (-measure-bloat)"},
+	{Name: "index_after_vacuum_mb", Label: "Index Size After VACUUM (MB)", Unit: "MB", Description: "Size of all indexes after VACUUM, which frees index pages but does not shrink the file (-measure-bloat)"},
+	{Name: "vacuum_time", Label: "VACUUM Time", Unit: "duration", Description: "Time of the VACUUM run after the update workload (-measure-bloat)"},
+	{Name: "dead_tuples", Label: "Dead Tuples", Unit: "count", Description: "pg_stat_user_tables n_dead_tup after the last update-churn round"},
+	{Name: "table_bloat", Label: "Table Bloat (%)", Unit: "%", Description: "Dead tuples plus free space as a share of the table (pgstattuple), after the last update-churn round"},
+	{Name: "index_bloat", Label: "Index Bloat (%)", Unit: "%", Description: "Free space in primary key leaf pages (100 - avg_leaf_density), after the last update-churn round"},
+	{Name: "index_growth", Label: "Index Growth (%)", Unit: "%", Description: "Index size growth over the update-churn rounds, relative to the freshly loaded index"},
+	{Name: "hot_update_ratio", Label: "HOT Update Ratio (%)", HigherIsBetter: true, Unit: "%", Description: "Share of updates that were heap-only and added no index entry (update-churn scenario)"},
+	{Name: "p50_latency_us", Label: "Latency P50 (µs)", Unit: "µs", Description: "Median transaction latency, from pgbench's per-transaction log"},
+	{Name: "p95_latency_us", Label: "Latency P95 (µs)", Unit: "µs", Description: "95th percentile transaction latency, from pgbench's per-transaction log"},
+	{Name: "p99_latency_us", Label: "Latency P99 (µs)", Unit: "µs", Description: "99th percentile transaction latency, from pgbench's per-transaction log"},
+	{Name: "read_iops", Label: "Read IOPS", Unit: "ops/s", Description: "Container block device read operations per second (cgroup v2)"},
+	{Name: "write_iops", Label: "Write IOPS", Unit: "ops/s", Description: "Container block device write operations per second (cgroup v2)"},
+	{Name: "read_throughput_mb", Label: "Read MB/s", Unit: "MB/s", Description: "Container block device read bandwidth (cgroup v2)"},
+	{Name: "write_throughput_mb", Label: "Write MB/s", Unit: "MB/s", Description: "Container block device write bandwidth (cgroup v2)"},
+	{Name: "write_amplification", Label: "Write Amplification (x)", Unit: "x", Description: "Bytes written to disk per logical byte inserted"},
+	{Name: "wal_mb", Label: "WAL Volume (MB)", Unit: "MB", Description: "WAL written by the measured inserts (pg_wal_lsn_diff over the insert LSN range)"},
+	{Name: "fpi_mb", Label: "WAL Full-Page Images (MB)", Unit: "MB", Description: "Full-page image bytes within the WAL volume, as stored (compressed with -wal-compression)"},
+	{Name: "rows_per_scan", Label: "Rows per Scan", HigherIsBetter: true, Unit: "rows", Description: "Rows fetched through the primary key index per range scan (range-scan scenario)"},
+	{Name: "pages_per_scan", Label: "Pages per Scan", Unit: "blocks/scan", Description: "Heap and index blocks a range scan accessed, from pg_statio_user_tables (range-scan scenario)"},
+	{Name: "read_amplification", Label: "Read Amplification (blocks/row)", Unit: "blocks/row", Description: "Index + heap blocks read from outside shared_buffers per row returned"},
+	{Name: "temp_bytes", Label: "Temp File Bytes", Unit: "bytes", Description: "Bytes spilled to temp files by sorts and index builds"},
+	{Name: "setup_time", Label: "Setup (DDL) Time", Unit: "duration", Description: "Extension creation plus DROP/CREATE TABLE time (-include-ddl-timing)"},
+	{Name: "avg_cpu_percent", Label: "CPU Avg (%)", Unit: "%", Description: "Average container CPU usage during the workload"},
+	{Name: "peak_cpu_percent", Label: "CPU Peak (%)", Unit: "%", Description: "Peak container CPU usage during the workload"},
+	{Name: "peak_rss_mb", Label: "Peak RSS (MB)", Unit: "MB", Description: "Peak container resident memory during the workload"},
+}
+
+// LatencyName returns the metric name of a latency percentile, e.g. p99.9_latency_us
+func LatencyName(percentile float64) string {
+	return "p" + strconv.FormatFloat(percentile, 'f', -1, 64) + "_latency_us"
+}
+
+// IsLatency reports whether name is a latency percentile metric
+func IsLatency(name string) bool {
+	return strings.HasPrefix(name, "p") && strings.HasSuffix(name, "_latency_us")
+}
+
+func isLatency(m Metric) bool {
+	return IsLatency(m.Name)
+}
+
+// SetPercentiles replaces the registry's latency metrics with one per percentile, in
+// place of the default p50/p95/p99
+func SetPercentiles(percentiles []float64) {
+	at := slices.IndexFunc(Registry, isLatency)
+	registry := slices.DeleteFunc(slices.Clone(Registry), isLatency)
+
+	latencies := make([]Metric, len(percentiles))
+	for i, p := range percentiles {
+		value := strconv.FormatFloat(p, 'f', -1, 64)
+		latencies[i] = Metric{
+			Name:        LatencyName(p),
+			Label:       fmt.Sprintf("Latency P%s (µs)", value),
+			Unit:        "µs",
+			Description: fmt.Sprintf("Transaction latency at percentile %s, from pgbench's per-transaction log", value),
+		}
+	}
+
+	Registry = slices.Insert(registry, at, latencies...)
+}
+
+// List prints every registered metric with its unit, better direction and description
+func List() {
+	fmt.Printf("%-26s %-14s %-8s %s\n", "METRIC", "UNIT", "BETTER", "DESCRIPTION")
+	for _, m := range Registry {
+		fmt.Printf("%-26s %-14s %-8s %s\n", m.Name, m.Unit, m.Direction(), m.Description)
+	}
+}
+
+// Lookup returns the registered metric with the given name
+func Lookup(name string) (Metric, bool) {
+	for _, m := range Registry {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Bounds returns the range of values a metric can physically take. Percentages are
+// bounded by 0..100 unless they are shares that can go negative or, like CPU usage
+// across cores, exceed 100; everything else is non-negative unless noted.
+func Bounds(name string) (lo, hi float64) {
+	switch name {
+	case "correlation_before", "correlation_after":
+		return -1, 1
+	case "correlation_delta":
+		return -2, 2
+	case "fit_r2":
+		return math.Inf(-1), 1
+	case "returning_overhead", "parse_plan_share":
+		return math.Inf(-1), 100
+	case "index_growth":
+		return -100, math.Inf(1)
+	case "fragmentation_regrowth":
+		// A burst can leave the index less fragmented than the REINDEX before it did
+		return math.Inf(-1), math.Inf(1)
+	case "avg_cpu_percent", "peak_cpu_percent":
+		return 0, math.Inf(1)
+	}
+	if m, ok := Lookup(name); ok && m.Unit == "%" {
+		return 0, 100
+	}
+	return 0, math.Inf(1)
+}
+
+// focus restricts displays and exports to these metrics; nil means all metrics
+var focus map[string]bool
+
+// SetFocus restricts displays and exports to the given comma-separated metric names.
+// An empty list clears the restriction.
+func SetFocus(names string) error {
+	if strings.TrimSpace(names) == "" {
+		focus = nil
+		return nil
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := Lookup(name); !ok {
+			return fmt.Errorf("unknown metric: %s", name)
+		}
+		selected[name] = true
+	}
+
+	focus = selected
+	return nil
+}
+
+// InFocus reports whether the metric should be displayed and exported
+func InFocus(name string) bool {
+	return focus == nil || focus[name]
+}
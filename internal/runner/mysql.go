@@ -0,0 +1,287 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	iometrics "github.com/moguls753/uuid-benchmark/internal/benchmark/io"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/mysql"
+)
+
+// connectMySQL opens a MySQL benchmarker on a fresh table of the key type
+func connectMySQL(keyType string) (*mysql.MySQLBenchmarker, error) {
+	bench := mysql.New(mysql.Options{Seed: Options.Seed, PayloadBytes: Options.DataColumn.PayloadBytes})
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	if err := bench.CreateTable(keyType); err != nil {
+		bench.Close()
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	return bench, nil
+}
+
+// mysqlInsertPerformance is InsertPerformance against MySQL, inserting from Go
+// connections instead of pgbench
+func mysqlInsertPerformance(keyType string, numRecords, batchSize, connections int) (*benchmark.InsertPerformanceResult, error) {
+	bench, err := connectMySQL(keyType)
+	if err != nil {
+		return nil, err
+	}
+	defer bench.Close()
+
+	result := &benchmark.InsertPerformanceResult{
+		KeyType:     keyType,
+		NumRecords:  numRecords,
+		BatchSize:   batchSize,
+		Connections: connections,
+		InsertMode:  "batch",
+	}
+
+	fmt.Printf("Inserting %d records into MySQL (connections=%d, batch=%d)...\n", numRecords, connections, batchSize)
+
+	ioStatsBefore, err := captureIOStats("before insert")
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := startResourceSampler()
+	defer sampler.Stop()
+
+	insert, err := bench.InsertRecords(numRecords, batchSize, connections)
+	if err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	result.Duration = insert.Duration
+	result.Throughput = insert.Throughput
+	result.TPS = float64(insert.SuccessCount) / insert.Duration.Seconds()
+	result.Latency = insert.Latency
+
+	ioStatsAfter, err := captureIOStats("after insert")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := sampler.Stop()
+	result.AvgCPUPercent = usage.AvgCPUPercent
+	result.PeakCPUPercent = usage.PeakCPUPercent
+	result.AvgRSSMB = usage.AvgRSSMB
+	result.PeakRSSMB = usage.PeakRSSMB
+
+	if ioStatsBefore != nil && ioStatsAfter != nil {
+		ioMetrics := iometrics.CalculateIOMetrics(ioStatsBefore, ioStatsAfter)
+		result.ReadIOPS = ioMetrics.ReadIOPS
+		result.WriteIOPS = ioMetrics.WriteIOPS
+		result.ReadThroughputMB = ioMetrics.ReadThroughputMB
+		result.WriteThroughputMB = ioMetrics.WriteThroughputMB
+		result.WriteAmplification = benchmark.WriteAmplification(ioStatsAfter.WriteBytes-ioStatsBefore.WriteBytes, numRecords, keyType)
+	}
+
+	fmt.Printf("Inserted %d records in %s\n", numRecords, result.Duration)
+	fmt.Printf("Throughput: %.2f records/sec\n", result.Throughput)
+
+	fmt.Println("Measuring metrics...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+
+	result.PageSplits = metrics.PageSplits
+	result.TableSize = metrics.TableSize
+	result.IndexSize = metrics.IndexSize
+	result.PKIndexSize = metrics.IndexSize
+	if metrics.TableSize > 0 {
+		result.IndexTableRatio = float64(metrics.IndexSize) / float64(metrics.TableSize)
+	}
+	result.Fragmentation = metrics.Fragmentation
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mysqlReadAfterFragmentation is ReadAfterFragmentation against MySQL
+func mysqlReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmark.ReadAfterFragmentationResult, error) {
+	bench, err := connectMySQL(keyType)
+	if err != nil {
+		return nil, err
+	}
+	defer bench.Close()
+
+	result := &benchmark.ReadAfterFragmentationResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		NumReads:   numReads,
+		QueryMode:  "extended",
+	}
+
+	fmt.Printf("Inserting %d records to create index...\n", numRecords)
+	insert, err := bench.InsertRecords(numRecords, 100, 1)
+	if err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	result.InsertDuration = insert.Duration
+	fmt.Printf("Inserted %d records in %s\n", numRecords, insert.Duration)
+
+	// The full id scan must not count towards the reads' buffer and I/O counters
+	if err := bench.PrepareIDs(); err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Measuring fragmentation...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.Fragmentation = metrics.Fragmentation
+	fmt.Printf("Free space: %.2f%%\n", metrics.Fragmentation.FragmentationPercent)
+
+	fmt.Printf("Running %d point lookups...\n", numReads)
+
+	requestsBefore, diskReadsBefore, err := bench.BufferPoolCounters()
+	if err != nil {
+		return nil, err
+	}
+
+	ioStatsBefore, err := captureIOStats("before reads")
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := startResourceSampler()
+	defer sampler.Stop()
+
+	read, err := bench.ReadRecords(numReads)
+	if err != nil {
+		return nil, fmt.Errorf("read records: %w", err)
+	}
+	result.ReadDuration = read.Duration
+	result.ReadThroughput = read.Throughput
+	result.Latency = read.Latency
+
+	ioStatsAfter, err := captureIOStats("after reads")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := sampler.Stop()
+	result.AvgCPUPercent = usage.AvgCPUPercent
+	result.PeakCPUPercent = usage.PeakCPUPercent
+	result.AvgRSSMB = usage.AvgRSSMB
+	result.PeakRSSMB = usage.PeakRSSMB
+
+	if ioStatsBefore != nil && ioStatsAfter != nil {
+		ioMetrics := iometrics.CalculateIOMetrics(ioStatsBefore, ioStatsAfter)
+		result.ReadIOPS = ioMetrics.ReadIOPS
+		result.WriteIOPS = ioMetrics.WriteIOPS
+		result.ReadThroughputMB = ioMetrics.ReadThroughputMB
+		result.WriteThroughputMB = ioMetrics.WriteThroughputMB
+	}
+
+	fmt.Printf("Completed %d reads in %s\n", numReads, read.Duration)
+	fmt.Printf("Read throughput: %.2f ops/sec\n", result.ReadThroughput)
+
+	requestsAfter, diskReadsAfter, err := bench.BufferPoolCounters()
+	if err != nil {
+		return nil, err
+	}
+	// The clustered index holds the rows, so the index and overall ratios coincide
+	if requests := requestsAfter - requestsBefore; requests > 0 {
+		result.BufferHitRatio = 1 - float64(diskReadsAfter-diskReadsBefore)/float64(requests)
+		result.IndexBufferHitRatio = result.BufferHitRatio
+	}
+	if numReads > 0 {
+		result.ReadAmplification = float64(diskReadsAfter-diskReadsBefore) / float64(numReads)
+	}
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// mysqlUpdatePerformance is UpdatePerformance against MySQL, one row per update
+func mysqlUpdatePerformance(keyType string, numRecords, numUpdates int) (*benchmark.UpdatePerformanceResult, error) {
+	bench, err := connectMySQL(keyType)
+	if err != nil {
+		return nil, err
+	}
+	defer bench.Close()
+
+	result := &benchmark.UpdatePerformanceResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		NumUpdates: numUpdates,
+		BatchSize:  1,
+	}
+
+	fmt.Printf("Inserting %d records...\n", numRecords)
+	if _, err := bench.InsertRecords(numRecords, 100, 1); err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	fmt.Printf("Inserted %d records\n", numRecords)
+
+	// The full id scan must not count towards the updates' I/O counters
+	if err := bench.PrepareIDs(); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Running %d updates...\n", numUpdates)
+
+	ioStatsBefore, err := captureIOStats("before updates")
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := startResourceSampler()
+	defer sampler.Stop()
+
+	update, err := bench.UpdateRecords(numUpdates)
+	if err != nil {
+		return nil, fmt.Errorf("update records: %w", err)
+	}
+	result.UpdateDuration = update.Duration
+	result.UpdateThroughput = update.Throughput
+	result.Latency = update.Latency
+
+	ioStatsAfter, err := captureIOStats("after updates")
+	if err != nil {
+		return nil, err
+	}
+
+	usage := sampler.Stop()
+	result.AvgCPUPercent = usage.AvgCPUPercent
+	result.PeakCPUPercent = usage.PeakCPUPercent
+	result.AvgRSSMB = usage.AvgRSSMB
+	result.PeakRSSMB = usage.PeakRSSMB
+
+	if ioStatsBefore != nil && ioStatsAfter != nil {
+		ioMetrics := iometrics.CalculateIOMetrics(ioStatsBefore, ioStatsAfter)
+		result.ReadIOPS = ioMetrics.ReadIOPS
+		result.WriteIOPS = ioMetrics.WriteIOPS
+		result.ReadThroughputMB = ioMetrics.ReadThroughputMB
+		result.WriteThroughputMB = ioMetrics.WriteThroughputMB
+	}
+
+	fmt.Printf("Completed %d updates in %s\n", numUpdates, update.Duration)
+	fmt.Printf("Update throughput: %.2f ops/sec\n", result.UpdateThroughput)
+
+	fmt.Println("Measuring fragmentation...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.Fragmentation = metrics.Fragmentation
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
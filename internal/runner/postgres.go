@@ -1,15 +1,114 @@
 package runner
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/moguls753/uuid-benchmark/internal/benchmark"
 	iometrics "github.com/moguls753/uuid-benchmark/internal/benchmark/io"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/mysql"
 	"github.com/moguls753/uuid-benchmark/internal/benchmark/postgres"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/statistics"
 )
 
+// Options holds benchmark-wide settings passed to every benchmarker; set from the CLI
+var Options postgres.Options
+
+// Database is the database the scenarios run against, postgres, mysql or sqlite; set from -db
+var Database = "postgres"
+
+// passCalls counts the calls per scenario and key type, for reversePasses
+var passCalls = map[string]int{}
+
+// reversePasses reports whether this call of scenario for keyType runs its back-to-back
+// passes in reverse order, which every second call does, so across runs neither pass
+// always gets the cache the other one warmed
+func reversePasses(scenario, keyType string) bool {
+	key := scenario + "/" + keyType
+	n := passCalls[key]
+	passCalls[key]++
+	return n%2 == 1
+}
+
+// dbContainer returns the name of the Database's container
+func dbContainer() string {
+	if Database == "mysql" {
+		return mysql.ContainerName
+	}
+	return postgres.Connection.Container
+}
+
+// containerStats reports whether the Database's container cgroup describes the server,
+// which it does not for an external PostgreSQL server
+func containerStats() bool {
+	return Database != "postgres" || !postgres.Connection.External()
+}
+
+// startResourceSampler starts sampling container CPU and memory usage, returning nil
+// (which is safe to Stop) if the container's cgroup cannot be read
+func startResourceSampler() *iometrics.ResourceSampler {
+	if !containerStats() {
+		return nil
+	}
+	sampler, err := iometrics.StartResourceSampler(dbContainer(), 250*time.Millisecond)
+	if err != nil {
+		fmt.Printf("Warning:Failed to start CPU/memory sampling: %v\n", err)
+		return nil
+	}
+	return sampler
+}
+
+// captureIOStats reads the container's cumulative I/O counters. A failure is a warning
+// that leaves the I/O metrics at zero, or an error under Options.Strict; an external
+// server has none, and leaves them at zero silently.
+func captureIOStats(phase string) (*iometrics.IOStats, error) {
+	if !containerStats() {
+		return nil, nil
+	}
+	stats, err := iometrics.GetContainerIOStats(dbContainer())
+	if err != nil {
+		if Options.Strict {
+			return nil, fmt.Errorf("capture I/O stats %s: %w", phase, err)
+		}
+		fmt.Printf("Warning:Failed to capture I/O stats %s: %v\n", phase, err)
+	}
+	return stats, nil
+}
+
+// captureTempUsage snapshots the database's temp file counters, returning nil (with
+// a warning) if they cannot be read
+func captureTempUsage(bench *postgres.PostgresBenchmarker, phase string) *postgres.TempUsage {
+	usage, err := bench.TempUsage()
+	if err != nil {
+		fmt.Printf("Warning: Failed to capture temp file stats %s: %v\n", phase, err)
+	}
+	return usage
+}
+
+// validate runs the post-collection bounds check over a result: impossible values are
+// clamped with a warning, or fail the scenario under Options.Strict
+func validate(keyType string, result interface{ Validate(*benchmark.Validator) }) error {
+	v := &benchmark.Validator{Label: keyType, Strict: Options.Strict}
+	result.Validate(v)
+	if err := v.Err(); err != nil {
+		return fmt.Errorf("validate results: %w", err)
+	}
+	return nil
+}
+
 func InsertPerformance(keyType string, numRecords, batchSize, connections int) (*benchmark.InsertPerformanceResult, error) {
-	bench := postgres.New()
+	switch Database {
+	case "mysql":
+		return mysqlInsertPerformance(keyType, numRecords, batchSize, connections)
+	case "sqlite":
+		return sqliteInsertPerformance(keyType, numRecords, batchSize)
+	}
+
+	bench := postgres.New(Options)
+	bench.SetScenario("insert-performance")
 
 	if err := bench.Connect(); err != nil {
 		return nil, fmt.Errorf("connect: %w", err)
@@ -20,43 +119,114 @@ func InsertPerformance(keyType string, numRecords, batchSize, connections int) (
 		return nil, fmt.Errorf("create table: %w", err)
 	}
 
-	fmt.Printf("Inserting %d records (connections=%d, batch=%d)...\n", numRecords, connections, batchSize)
-
 	result := &benchmark.InsertPerformanceResult{
 		KeyType:     keyType,
 		NumRecords:  numRecords,
+		Preloaded:   Options.Preload,
 		BatchSize:   batchSize,
 		Connections: connections,
+		InsertMode:  cmp.Or(Options.InsertMode, "pgbench"),
+	}
+	result.Setup = bench.SetupTiming()
+
+	// The measured inserts below re-capture the LSN range, I/O and temp counters, so
+	// only they are charged, landing in an index that no longer starts out empty
+	if Options.Preload > 0 {
+		fmt.Printf("Preloading %d records (not measured)...\n", Options.Preload)
+		if _, err := bench.InsertRecordsPgbench(keyType, Options.Preload, 1000); err != nil {
+			return nil, fmt.Errorf("preload records: %w", err)
+		}
+		if err := bench.Checkpoint(); err != nil {
+			return nil, err
+		}
+		if err := bench.ResetStats(); err != nil {
+			return nil, fmt.Errorf("reset stats: %w", err)
+		}
+	}
+
+	if Options.Duration > 0 && Options.InsertMode != "copy" {
+		bench.SetTimeBound(true)
+		result.TimeBound = Options.Duration
+		fmt.Printf("Inserting for %ds (mode=%s, connections=%d, batch=%d)...\n", Options.Duration, result.InsertMode, connections, batchSize)
+	} else {
+		fmt.Printf("Inserting %d records (mode=%s, connections=%d, batch=%d)...\n", numRecords, result.InsertMode, connections, batchSize)
 	}
 
-	ioStatsBefore, err := iometrics.GetContainerIOStats("uuid-bench-postgres")
+	ioStatsBefore, err := captureIOStats("before insert")
 	if err != nil {
-		fmt.Printf("Warning:Failed to capture I/O stats before insert: %v\n", err)
+		return nil, err
+	}
+	tempBefore := captureTempUsage(bench, "before insert")
+
+	sampler := startResourceSampler()
+	defer sampler.Stop()
+
+	if Options.SizeSampling > 0 {
+		if err := bench.StartSizeSampler(Options.SizeSampling); err != nil {
+			return nil, err
+		}
+		defer bench.StopSizeSampler()
 	}
 
-	if connections == 1 {
+	if Options.InsertMode == "copy" {
+		duration, err := bench.InsertRecordsCopy(keyType, numRecords)
+		if err != nil {
+			return nil, fmt.Errorf("copy records: %w", err)
+		}
+		result.Duration = duration
+		result.Throughput = float64(numRecords) / duration.Seconds()
+	} else if connections == 1 {
 		duration, err := bench.InsertRecordsPgbench(keyType, numRecords, batchSize)
 		if err != nil {
 			return nil, fmt.Errorf("insert records: %w", err)
 		}
+		numRecords = bench.LastOps()
 		result.Duration = duration
 		result.Throughput = float64(numRecords) / duration.Seconds()
+		if parsed := bench.LastPgbenchResult(); parsed != nil {
+			result.TPS = parsed.TPS
+			result.TPSIncludingSetup = parsed.TPSIncludingSetup
+			result.ConnectionTime = parsed.InitialConnectionTime
+		}
 	} else {
 		concResult, err := bench.InsertRecordsPgbenchConcurrent(keyType, numRecords, connections, batchSize)
 		if err != nil {
 			return nil, fmt.Errorf("insert records concurrent: %w", err)
 		}
+		// Wall-clock throughput like the single-connection path, so the rates reconcile
+		// across connection counts; pgbench's own rate is reported as TPS
+		numRecords = concResult.TotalOps
 		result.Duration = concResult.Duration
-		result.Throughput = concResult.Throughput
-		result.LatencyP50 = concResult.LatencyP50
-		result.LatencyP95 = concResult.LatencyP95
-		result.LatencyP99 = concResult.LatencyP99
+		result.Throughput = float64(numRecords) / concResult.Duration.Seconds()
+		result.TPS = concResult.Throughput
+		result.TPSIncludingSetup = concResult.ThroughputIncludingSetup
+		result.ConnectionTime = concResult.InitialConnectionTime
+		result.Latency = concResult.Latency
+	}
+	// The offered load and how far pgbench fell behind it, for latency-vs-load curves
+	if parsed := bench.LastPgbenchResult(); Options.Rate > 0 && Options.InsertMode != "copy" && parsed != nil {
+		result.Rate = Options.Rate
+		result.ScheduleLagAvg = parsed.ScheduleLagAvg
+		result.ScheduleLagMax = parsed.ScheduleLagMax
+		result.Skipped = parsed.Skipped
 	}
+	// A time-bound run inserts however many rows fit in the window
+	result.NumRecords = numRecords
+	result.SizeSamples = bench.StopSizeSampler()
 
-	ioStatsAfter, err := iometrics.GetContainerIOStats("uuid-bench-postgres")
+	ioStatsAfter, err := captureIOStats("after insert")
 	if err != nil {
-		fmt.Printf("Warning:Failed to capture I/O stats after insert: %v\n", err)
+		return nil, err
 	}
+	if tempAfter := captureTempUsage(bench, "after insert"); tempBefore != nil && tempAfter != nil {
+		result.TempFiles, result.TempBytes = tempAfter.Since(tempBefore)
+	}
+
+	usage := sampler.Stop()
+	result.AvgCPUPercent = usage.AvgCPUPercent
+	result.PeakCPUPercent = usage.PeakCPUPercent
+	result.AvgRSSMB = usage.AvgRSSMB
+	result.PeakRSSMB = usage.PeakRSSMB
 
 	if ioStatsBefore != nil && ioStatsAfter != nil {
 		ioMetrics := iometrics.CalculateIOMetrics(ioStatsBefore, ioStatsAfter)
@@ -64,11 +234,18 @@ func InsertPerformance(keyType string, numRecords, batchSize, connections int) (
 		result.WriteIOPS = ioMetrics.WriteIOPS
 		result.ReadThroughputMB = ioMetrics.ReadThroughputMB
 		result.WriteThroughputMB = ioMetrics.WriteThroughputMB
+		result.WriteAmplification = benchmark.WriteAmplification(ioStatsAfter.WriteBytes-ioStatsBefore.WriteBytes, numRecords, keyType)
 	}
 
 	fmt.Printf("Inserted %d records in %s\n", numRecords, result.Duration)
 	fmt.Printf("Throughput: %.2f records/sec\n", result.Throughput)
 
+	// Flush the inserts' dirty pages now, so a background checkpoint cannot fire
+	// during measurement; the I/O counters above were already captured
+	if err := bench.Checkpoint(); err != nil {
+		return nil, err
+	}
+
 	fmt.Println("Measuring metrics...")
 	metrics, err := bench.MeasureMetrics()
 	if err != nil {
@@ -78,13 +255,101 @@ func InsertPerformance(keyType string, numRecords, batchSize, connections int) (
 	result.PageSplits = metrics.PageSplits
 	result.TableSize = metrics.TableSize
 	result.IndexSize = metrics.IndexSize
+	if metrics.TableSize > 0 {
+		result.IndexTableRatio = float64(metrics.IndexSize) / float64(metrics.TableSize)
+	}
 	result.Fragmentation = metrics.Fragmentation
+	result.Tuples = metrics.Tuples
+	result.WALBytes = metrics.WALBytes
+	result.FPIBytes = metrics.FPIBytes
+	result.IndexPagesDirtied = metrics.IndexPagesDirtied
+
+	if Options.Remeasure > 1 {
+		result.MeasurementCV, err = remeasure(bench, metrics, numRecords)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result.ExtraIndexes = Options.ExtraIndexes
+	result.PKIndexSize = metrics.IndexSize
+	if Options.ExtraIndexes > 0 {
+		result.PKIndexSize, err = bench.MeasureIndexSize(bench.PKIndexName())
+		if err != nil {
+			return nil, fmt.Errorf("measure primary key index size: %w", err)
+		}
+	}
+
+	if Options.RecordPath != "" {
+		if err := bench.RecordOps(Options.RecordPath); err != nil {
+			return nil, fmt.Errorf("record operations: %w", err)
+		}
+	}
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
 
+// remeasure repeats MeasureMetrics on the unchanged table until Options.Remeasure
+// measurements exist and returns each metric's CV across them, the variance of the
+// metric collection itself as opposed to that of re-running the benchmark
+func remeasure(bench *postgres.PostgresBenchmarker, first *benchmark.BenchmarkResult, numRecords int) (map[string]float64, error) {
+	samples := make(map[string][]float64)
+	add := func(metrics *benchmark.BenchmarkResult) {
+		for name, value := range measurementValues(metrics, numRecords) {
+			samples[name] = append(samples[name], value)
+		}
+	}
+
+	add(first)
+	for i := 2; i <= Options.Remeasure; i++ {
+		fmt.Printf("Re-measuring metrics (%d/%d)...\n", i, Options.Remeasure)
+		metrics, err := bench.MeasureMetrics()
+		if err != nil {
+			return nil, fmt.Errorf("re-measure metrics: %w", err)
+		}
+		add(metrics)
+	}
+
+	cv := make(map[string]float64, len(samples))
+	for name, values := range samples {
+		cv[name] = statistics.CV(values)
+	}
+	return cv, nil
+}
+
+// measurementValues maps the metrics MeasureMetrics collects to their registry names
+func measurementValues(m *benchmark.BenchmarkResult, numRecords int) map[string]float64 {
+	values := map[string]float64{
+		"page_splits":      float64(m.PageSplits),
+		"fragmentation":    m.Fragmentation.FragmentationPercent,
+		"avg_leaf_density": m.Fragmentation.AvgLeafDensity,
+		"table_size_mb":    float64(m.TableSize) / (1024 * 1024),
+		"index_size_mb":    float64(m.IndexSize) / (1024 * 1024),
+		"wal_mb":           float64(m.WALBytes) / (1024 * 1024),
+		"fpi_mb":           float64(m.FPIBytes) / (1024 * 1024),
+		"buffer_hit_ratio": m.BufferHitRatio * 100,
+		"index_hit_ratio":  m.IndexBufferHitRatio * 100,
+	}
+	if numRecords > 0 {
+		values["index_pages_dirtied_per_1k"] = float64(m.IndexPagesDirtied) * 1000 / float64(numRecords)
+	}
+	return values
+}
+
 func ReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmark.ReadAfterFragmentationResult, error) {
-	bench := postgres.New()
+	switch Database {
+	case "mysql":
+		return mysqlReadAfterFragmentation(keyType, numRecords, numReads)
+	case "sqlite":
+		return sqliteReadAfterFragmentation(keyType, numRecords, numReads)
+	}
+
+	bench := postgres.New(Options)
+	bench.SetScenario("read-after-fragmentation")
 
 	if err := bench.Connect(); err != nil {
 		return nil, fmt.Errorf("connect: %w", err)
@@ -99,7 +364,9 @@ func ReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmar
 		KeyType:    keyType,
 		NumRecords: numRecords,
 		NumReads:   numReads,
+		QueryMode:  cmp.Or(Options.QueryMode, "simple"),
 	}
+	result.Setup = bench.SetupTiming()
 
 	fmt.Printf("Inserting %d records to create index...\n", numRecords)
 	insertDuration, err := bench.InsertRecordsPgbench(keyType, numRecords, 100)
@@ -109,6 +376,12 @@ func ReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmar
 	result.InsertDuration = insertDuration
 	fmt.Printf("Inserted %d records in %s\n", numRecords, insertDuration)
 
+	// Write back the load before the reads, so they neither trigger nor overlap the
+	// checkpoint that would otherwise flush it
+	if err := bench.Checkpoint(); err != nil {
+		return nil, err
+	}
+
 	fmt.Println("Measuring fragmentation...")
 	metrics, err := bench.MeasureMetrics()
 	if err != nil {
@@ -117,6 +390,20 @@ func ReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmar
 	result.Fragmentation = metrics.Fragmentation
 	fmt.Printf("Index fragmentation: %.2f%%\n", metrics.Fragmentation.FragmentationPercent)
 
+	plan, err := bench.ExplainRead(keyType, numRecords)
+	if err != nil {
+		fmt.Printf("Warning: failed to check read query plan: %v\n", err)
+	} else {
+		result.ReadPlan = plan.TopNode
+		result.IndexScan = plan.IndexBased
+	}
+	if plan != nil && !plan.IndexBased {
+		fmt.Println(strings.Repeat("!", 70))
+		fmt.Printf("Warning: read query for %s does not use the primary key index (plan: %s)\n", keyType, plan.TopNode)
+		fmt.Println("Warning: read results are not index point lookups and are not comparable")
+		fmt.Println(strings.Repeat("!", 70))
+	}
+
 	fmt.Println("Resetting PostgreSQL statistics...")
 	if err := bench.ResetStats(); err != nil {
 		return nil, fmt.Errorf("reset stats: %w", err)
@@ -124,22 +411,39 @@ func ReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmar
 
 	fmt.Printf("Running %d point lookups...\n", numReads)
 
-	ioStatsBefore, err := iometrics.GetContainerIOStats("uuid-bench-postgres")
+	ioStatsBefore, err := captureIOStats("before reads")
 	if err != nil {
-		fmt.Printf("Warning:Failed to capture I/O stats before reads: %v\n", err)
+		return nil, err
 	}
+	tempBefore := captureTempUsage(bench, "before reads")
+
+	sampler := startResourceSampler()
+	defer sampler.Stop()
 
-	readDuration, err := bench.ReadRecordsPgbench(keyType, numRecords, numReads)
+	bench.SetTimeBound(true)
+	read, err := bench.ReadRecordsPgbench(keyType, numRecords, numReads)
 	if err != nil {
 		return nil, fmt.Errorf("read records: %w", err)
 	}
-	result.ReadDuration = readDuration
-	result.ReadThroughput = float64(numReads) / readDuration.Seconds()
+	numReads = read.TotalOps
+	result.NumReads = numReads
+	result.ReadDuration = read.Duration
+	result.ReadThroughput = float64(numReads) / read.Duration.Seconds()
+	result.Latency = read.Latency
 
-	ioStatsAfter, err := iometrics.GetContainerIOStats("uuid-bench-postgres")
+	ioStatsAfter, err := captureIOStats("after reads")
 	if err != nil {
-		fmt.Printf("Warning:Failed to capture I/O stats after reads: %v\n", err)
+		return nil, err
 	}
+	if tempAfter := captureTempUsage(bench, "after reads"); tempBefore != nil && tempAfter != nil {
+		result.TempFiles, result.TempBytes = tempAfter.Since(tempBefore)
+	}
+
+	usage := sampler.Stop()
+	result.AvgCPUPercent = usage.AvgCPUPercent
+	result.PeakCPUPercent = usage.PeakCPUPercent
+	result.AvgRSSMB = usage.AvgRSSMB
+	result.PeakRSSMB = usage.PeakRSSMB
 
 	if ioStatsBefore != nil && ioStatsAfter != nil {
 		ioMetrics := iometrics.CalculateIOMetrics(ioStatsBefore, ioStatsAfter)
@@ -149,7 +453,7 @@ func ReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmar
 		result.WriteThroughputMB = ioMetrics.WriteThroughputMB
 	}
 
-	fmt.Printf("Completed %d reads in %s\n", numReads, readDuration)
+	fmt.Printf("Completed %d reads in %s\n", numReads, read.Duration)
 	fmt.Printf("Read throughput: %.2f ops/sec\n", result.ReadThroughput)
 
 	fmt.Println("Measuring buffer pool hit ratios...")
@@ -158,13 +462,68 @@ func ReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmar
 		return nil, fmt.Errorf("measure final metrics: %w", err)
 	}
 	result.BufferHitRatio = finalMetrics.BufferHitRatio
-	result.IndexBufferHitRatio = finalMetrics.IndexBufferHitRatio
+	// The table's counters cover the measured pgbench run only, not its warmup; each
+	// point lookup returns one row
+	if readIO := bench.LastReadIO(); readIO != nil {
+		result.IndexBufferHitRatio = readIO.IndexHitRatio()
+		if numReads > 0 {
+			result.ReadAmplification = float64(readIO.BlocksRead()) / float64(numReads)
+		}
+	}
+
+	if Options.CompareQueryModes {
+		if err := compareQueryModes(bench, keyType, numRecords, numReads, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
 
+// compareQueryModes repeats the read phase under both the simple and prepared protocols,
+// so parse/plan cost can be separated from index access cost. Both passes run on the
+// cache warmed by the measured pass, in an order that alternates from run to run.
+func compareQueryModes(bench *postgres.PostgresBenchmarker, keyType string, numRecords, numReads int, result *benchmark.ReadAfterFragmentationResult) error {
+	result.ModeThroughput = map[string]float64{}
+	defer bench.SetQueryMode(Options.QueryMode)
+
+	modes := []string{"simple", "prepared"}
+	if reversePasses("compare-query-modes", keyType) {
+		slices.Reverse(modes)
+	}
+
+	for _, mode := range modes {
+		fmt.Printf("Repeating %d point lookups with -M %s...\n", numReads, mode)
+		bench.SetQueryMode(mode)
+		read, err := bench.ReadRecordsPgbench(keyType, numRecords, numReads)
+		if err != nil {
+			return fmt.Errorf("read records (-M %s): %w", mode, err)
+		}
+		result.ModeThroughput[mode] = float64(read.TotalOps) / read.Duration.Seconds()
+		fmt.Printf("Read throughput (-M %s): %.2f ops/sec\n", mode, result.ModeThroughput[mode])
+	}
+
+	if share := result.ParsePlanShare(); share != 0 {
+		fmt.Printf("Parse/plan share of per-read time: %.1f%%\n", share*100)
+	}
+
+	return nil
+}
+
 func UpdatePerformance(keyType string, numRecords, numUpdates, batchSize int) (*benchmark.UpdatePerformanceResult, error) {
-	bench := postgres.New()
+	switch Database {
+	case "mysql":
+		return mysqlUpdatePerformance(keyType, numRecords, numUpdates)
+	case "sqlite":
+		return sqliteUpdatePerformance(keyType, numRecords, numUpdates)
+	}
+
+	bench := postgres.New(Options)
+	bench.SetScenario("update-performance")
 
 	if err := bench.Connect(); err != nil {
 		return nil, fmt.Errorf("connect: %w", err)
@@ -179,8 +538,10 @@ func UpdatePerformance(keyType string, numRecords, numUpdates, batchSize int) (*
 		KeyType:    keyType,
 		NumRecords: numRecords,
 		NumUpdates: numUpdates,
-		BatchSize:  batchSize,
+		// The update script runs one UPDATE per transaction, whatever -batch-size says
+		BatchSize: 1,
 	}
+	result.Setup = bench.SetupTiming()
 
 	fmt.Printf("Inserting %d records...\n", numRecords)
 	_, err := bench.InsertRecordsPgbench(keyType, numRecords, 100)
@@ -189,23 +550,51 @@ func UpdatePerformance(keyType string, numRecords, numUpdates, batchSize int) (*
 	}
 	fmt.Printf("Inserted %d records\n", numRecords)
 
-	fmt.Printf("Running %d updates (batch size=%d)...\n", numUpdates, batchSize)
+	// Write back the load before the updates, so they don't pay for flushing it
+	if err := bench.Checkpoint(); err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Measuring id correlation before updates...")
+	correlationBefore, err := bench.MeasureCorrelation()
+	if err != nil {
+		fmt.Printf("Warning: Could not measure correlation before updates: %v\n", err)
+	}
+
+	fmt.Printf("Running %d updates...\n", numUpdates)
 
-	ioStatsBefore, err := iometrics.GetContainerIOStats("uuid-bench-postgres")
+	ioStatsBefore, err := captureIOStats("before updates")
 	if err != nil {
-		fmt.Printf("Warning:Failed to capture I/O stats before updates: %v\n", err)
+		return nil, err
 	}
+	tempBefore := captureTempUsage(bench, "before updates")
 
+	sampler := startResourceSampler()
+	defer sampler.Stop()
+
+	bench.SetTimeBound(true)
 	updateDuration, err := bench.UpdateRecordsPgbench(keyType, numRecords, numUpdates, batchSize)
 	if err != nil {
 		return nil, fmt.Errorf("update records: %w", err)
 	}
+	numUpdates = bench.LastOps()
+	result.NumUpdates = numUpdates
+	result.Latency = bench.LastLatency()
 
-	ioStatsAfter, err := iometrics.GetContainerIOStats("uuid-bench-postgres")
+	ioStatsAfter, err := captureIOStats("after updates")
 	if err != nil {
-		fmt.Printf("Warning:Failed to capture I/O stats after updates: %v\n", err)
+		return nil, err
+	}
+	if tempAfter := captureTempUsage(bench, "after updates"); tempBefore != nil && tempAfter != nil {
+		result.TempFiles, result.TempBytes = tempAfter.Since(tempBefore)
 	}
 
+	usage := sampler.Stop()
+	result.AvgCPUPercent = usage.AvgCPUPercent
+	result.PeakCPUPercent = usage.PeakCPUPercent
+	result.AvgRSSMB = usage.AvgRSSMB
+	result.PeakRSSMB = usage.PeakRSSMB
+
 	if ioStatsBefore != nil && ioStatsAfter != nil {
 		ioMetrics := iometrics.CalculateIOMetrics(ioStatsBefore, ioStatsAfter)
 		result.ReadIOPS = ioMetrics.ReadIOPS
@@ -220,18 +609,64 @@ func UpdatePerformance(keyType string, numRecords, numUpdates, batchSize int) (*
 	fmt.Printf("Completed %d updates in %s\n", numUpdates, updateDuration)
 	fmt.Printf("Update throughput: %.2f ops/sec\n", result.UpdateThroughput)
 
+	fmt.Println("Measuring id correlation after updates...")
+	correlationAfter, err := bench.MeasureCorrelation()
+	if err != nil {
+		fmt.Printf("Warning: Could not measure correlation after updates: %v\n", err)
+	}
+	result.CorrelationBefore = correlationBefore
+	result.CorrelationAfter = correlationAfter
+	result.CorrelationDelta = correlationAfter - correlationBefore
+	fmt.Printf("Correlation: %.4f -> %.4f (%+.4f)\n", correlationBefore, correlationAfter, result.CorrelationDelta)
+
 	fmt.Println("Measuring fragmentation...")
 	metrics, err := bench.MeasureMetrics()
 	if err != nil {
 		return nil, fmt.Errorf("measure metrics: %w", err)
 	}
 	result.Fragmentation = metrics.Fragmentation
+	result.Tuples = metrics.Tuples
+
+	if Options.MeasureBloat {
+		if err := measureVacuum(bench, result, metrics.IndexSize); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
 
-func MixedWorkloadInsertHeavy(keyType string, totalOps, connections, batchSize int) (*benchmark.MixedWorkloadResult, error) {
-	bench := postgres.New()
+// measureVacuum runs VACUUM after the update workload and records what it reclaimed:
+// the heap breakdown and index size before and after
+func measureVacuum(bench *postgres.PostgresBenchmarker, result *benchmark.UpdatePerformanceResult, indexSize int64) error {
+	fmt.Println("Running VACUUM...")
+	duration, err := bench.Vacuum()
+	if err != nil {
+		return err
+	}
+	result.VacuumDuration = duration
+	result.IndexSizeBefore = indexSize
+
+	if result.VacuumTuples, err = bench.MeasureTupleStats(); err != nil {
+		return err
+	}
+	if _, result.IndexSizeAfter, err = bench.DiskUsage(); err != nil {
+		return fmt.Errorf("measure index size after vacuum: %w", err)
+	}
+
+	fmt.Printf("VACUUM took %s: dead tuple space %.2f%% -> %.2f%%, index size %s -> %s\n",
+		duration.Round(time.Millisecond), result.Tuples.DeadTuplePercent, result.VacuumTuples.DeadTuplePercent,
+		benchmark.FormatBytes(result.IndexSizeBefore), benchmark.FormatBytes(result.IndexSizeAfter))
+	return nil
+}
+
+func MixedWorkloadInsertHeavy(keyType string, initialDataset, totalOps, connections, batchSize int) (*benchmark.MixedWorkloadResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("mixed-insert-heavy")
 
 	if err := bench.Connect(); err != nil {
 		return nil, fmt.Errorf("connect: %w", err)
@@ -242,25 +677,29 @@ func MixedWorkloadInsertHeavy(keyType string, totalOps, connections, batchSize i
 		return nil, fmt.Errorf("create table: %w", err)
 	}
 
-	initialDataset := 100000
-
 	fmt.Printf("\n=== Mixed Workload: Insert-Heavy (90%% insert, 10%% read) - %s ===\n", keyType)
 
 	result, err := bench.RunMixedWorkloadPgbench(keyType, initialDataset, totalOps, connections, 90, 10, 0)
 	if err != nil {
 		return nil, fmt.Errorf("run mixed workload: %w", err)
 	}
+	result.Setup = bench.SetupTiming()
 
 	fmt.Printf("Overall throughput: %.2f ops/sec\n", result.OverallThroughput)
 	fmt.Printf("Insert throughput: %.2f rec/sec\n", result.InsertThroughput)
 	fmt.Printf("Read throughput: %.2f rec/sec\n", result.ReadThroughput)
 	fmt.Printf("Buffer hit ratio: %.2f%%\n", result.BufferHitRatio*100)
 
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
-func MixedWorkloadReadHeavy(keyType string, totalOps, connections int) (*benchmark.MixedWorkloadResult, error) {
-	bench := postgres.New()
+func MixedWorkloadReadHeavy(keyType string, initialDataset, totalOps, connections int) (*benchmark.MixedWorkloadResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("mixed-read-heavy")
 
 	if err := bench.Connect(); err != nil {
 		return nil, fmt.Errorf("connect: %w", err)
@@ -271,25 +710,29 @@ func MixedWorkloadReadHeavy(keyType string, totalOps, connections int) (*benchma
 		return nil, fmt.Errorf("create table: %w", err)
 	}
 
-	initialDataset := 1000000
-
 	fmt.Printf("\n=== Mixed Workload: Read-Heavy (10%% insert, 90%% read) - %s ===\n", keyType)
 
 	result, err := bench.RunMixedWorkloadPgbench(keyType, initialDataset, totalOps, connections, 10, 90, 0)
 	if err != nil {
 		return nil, fmt.Errorf("run mixed workload: %w", err)
 	}
+	result.Setup = bench.SetupTiming()
 
 	fmt.Printf("Overall throughput: %.2f ops/sec\n", result.OverallThroughput)
 	fmt.Printf("Insert throughput: %.2f rec/sec\n", result.InsertThroughput)
 	fmt.Printf("Read throughput: %.2f rec/sec\n", result.ReadThroughput)
 	fmt.Printf("Buffer hit ratio: %.2f%%\n", result.BufferHitRatio*100)
 
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
-func MixedWorkloadBalanced(keyType string, totalOps, connections int) (*benchmark.MixedWorkloadResult, error) {
-	bench := postgres.New()
+func MixedWorkloadBalanced(keyType string, initialDataset, totalOps, connections int) (*benchmark.MixedWorkloadResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("mixed-balanced")
 
 	if err := bench.Connect(); err != nil {
 		return nil, fmt.Errorf("connect: %w", err)
@@ -300,14 +743,13 @@ func MixedWorkloadBalanced(keyType string, totalOps, connections int) (*benchmar
 		return nil, fmt.Errorf("create table: %w", err)
 	}
 
-	initialDataset := 500000
-
 	fmt.Printf("\n=== Mixed Workload: Balanced (50%% insert, 30%% read, 20%% update) - %s ===\n", keyType)
 
 	result, err := bench.RunMixedWorkloadPgbench(keyType, initialDataset, totalOps, connections, 50, 30, 20)
 	if err != nil {
 		return nil, fmt.Errorf("run mixed workload: %w", err)
 	}
+	result.Setup = bench.SetupTiming()
 
 	fmt.Printf("Overall throughput: %.2f ops/sec\n", result.OverallThroughput)
 	fmt.Printf("Insert throughput: %.2f rec/sec\n", result.InsertThroughput)
@@ -315,5 +757,709 @@ func MixedWorkloadBalanced(keyType string, totalOps, connections int) (*benchmar
 	fmt.Printf("Update throughput: %.2f rec/sec\n", result.UpdateThroughput)
 	fmt.Printf("Buffer hit ratio: %.2f%%\n", result.BufferHitRatio*100)
 
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func JSONBGinPerformance(keyType string, numRecords, batchSize int) (*benchmark.JSONBGinResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("jsonb-gin")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	bench.EnableJSONBPayload()
+	if err := bench.CreateTable(keyType); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	result := &benchmark.JSONBGinResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		BatchSize:  batchSize,
+	}
+
+	fmt.Printf("Inserting %d records with JSONB payload (batch=%d)...\n", numRecords, batchSize)
+	duration, err := bench.InsertRecordsPgbench(keyType, numRecords, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	result.Duration = duration
+	result.Throughput = float64(numRecords) / duration.Seconds()
+
+	fmt.Printf("Inserted %d records in %s\n", numRecords, duration)
+	fmt.Printf("Throughput: %.2f records/sec\n", result.Throughput)
+
+	fmt.Println("Measuring metrics...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.PageSplits = metrics.PageSplits
+	result.TableSize = metrics.TableSize
+	result.Fragmentation = metrics.Fragmentation
+
+	result.GinIndexSize, err = bench.MeasureIndexSize(bench.GinIndexName())
+	if err != nil {
+		return nil, fmt.Errorf("measure GIN index size: %w", err)
+	}
+	result.PKIndexSize, err = bench.MeasureIndexSize(bench.PKIndexName())
+	if err != nil {
+		return nil, fmt.Errorf("measure primary key index size: %w", err)
+	}
+
+	fmt.Println("Rebuilding GIN index to measure build cost...")
+	tempBefore := captureTempUsage(bench, "before GIN build")
+	result.GinBuildDuration, err = bench.ReindexDuration(bench.GinIndexName())
+	if err != nil {
+		return nil, fmt.Errorf("rebuild GIN index: %w", err)
+	}
+	if tempAfter := captureTempUsage(bench, "after GIN build"); tempBefore != nil && tempAfter != nil {
+		_, result.GinBuildTempBytes = tempAfter.Since(tempBefore)
+	}
+	fmt.Printf("GIN build: %s\n", result.GinBuildDuration)
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CacheCompetition loads numRecords rows, runs numReads primary key lookups of sampled
+// ids and then reports how the (deliberately small) buffer pool is split between heap
+// and index pages. A larger, fragmented index crowds heap pages out of the cache.
+func CacheCompetition(keyType string, numRecords, numReads int) (*benchmark.CacheCompetitionResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("cache-competition")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	if err := bench.CreateTable(keyType); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	result := &benchmark.CacheCompetitionResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		NumReads:   numReads,
+	}
+
+	fmt.Printf("Inserting %d records...\n", numRecords)
+	if _, err := bench.InsertRecordsPgbench(keyType, numRecords, 100); err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.TableSize = metrics.TableSize
+	result.IndexSize = metrics.IndexSize
+
+	// Sampling scans the table, so it comes before the statistics reset
+	if err := bench.PrepareLookups(numReads); err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Resetting PostgreSQL statistics...")
+	if err := bench.ResetStats(); err != nil {
+		return nil, fmt.Errorf("reset stats: %w", err)
+	}
+
+	fmt.Printf("Running %d primary key lookups...\n", numReads)
+	read, err := bench.LookupRecordsPgbench(numReads)
+	if err != nil {
+		return nil, fmt.Errorf("read records: %w", err)
+	}
+	result.ReadThroughput = float64(numReads) / read.Duration.Seconds()
+
+	result.HeapHitRatio, result.IndexHitRatio, err = bench.HeapIndexHitRatios()
+	if err != nil {
+		return nil, err
+	}
+
+	split, err := bench.MeasureBufferSplit()
+	if err != nil {
+		return nil, err
+	}
+	result.SharedBuffers = split.SharedBuffers
+	result.HeapBuffers = split.HeapBuffers
+	result.IndexBuffers = split.IndexBuffers
+
+	fmt.Printf("Buffer pool: %d heap / %d index of %d buffers\n", split.HeapBuffers, split.IndexBuffers, split.SharedBuffers)
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// workingSetCalibrationRows are inserted first to estimate bytes per row, from which
+// the rows needed to reach each working-set size are derived
+const workingSetCalibrationRows = 10000
+
+// WorkingSetSweep grows one table through datasets of fractions (ascending multiples of
+// shared_buffers, counting table and indexes), running numReads primary key lookups of
+// sampled ids at each size to chart read throughput and hit ratios against working set / cache
+func WorkingSetSweep(keyType string, fractions []float64, numReads int) (*benchmark.WorkingSetSweepResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("working-set-sweep")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	if err := bench.CreateTable(keyType); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	sharedBuffers, err := bench.SharedBuffersBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &benchmark.WorkingSetSweepResult{
+		KeyType:       keyType,
+		NumReads:      numReads,
+		SharedBuffers: sharedBuffers,
+	}
+
+	fmt.Printf("Calibrating with %d records...\n", workingSetCalibrationRows)
+	if _, err := bench.InsertRecordsPgbench(keyType, workingSetCalibrationRows, 100); err != nil {
+		return nil, fmt.Errorf("insert calibration records: %w", err)
+	}
+
+	for _, fraction := range fractions {
+		tableSize, indexSize, err := bench.DiskUsage()
+		if err != nil {
+			return nil, err
+		}
+		// Warmup inserts add rows beyond those requested, which the loaded count includes
+		rows := bench.LoadedRows()
+
+		// Re-estimate from the current table, as index density shifts while it grows
+		bytesPerRow := float64(tableSize+indexSize) / float64(rows)
+		target := fraction * float64(sharedBuffers)
+		if missing := int((target - float64(tableSize+indexSize)) / bytesPerRow); missing > 0 {
+			fmt.Printf("Growing to %.2fx shared_buffers: inserting %d records...\n", fraction, missing)
+			if _, err := bench.InsertRecordsPgbench(keyType, missing, 100); err != nil {
+				return nil, fmt.Errorf("insert records for %.2fx: %w", fraction, err)
+			}
+
+			tableSize, indexSize, err = bench.DiskUsage()
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			fmt.Printf("Working set already exceeds %.2fx shared_buffers, measuring as is\n", fraction)
+		}
+
+		point := benchmark.WorkingSetPoint{
+			Fraction:  fraction,
+			TableSize: tableSize,
+			IndexSize: indexSize,
+		}
+		point.Rows = bench.LoadedRows()
+
+		// Resampled at every size, so lookups spread over the grown table; sampling scans
+		// it, so it comes before the statistics reset
+		if err := bench.PrepareLookups(numReads); err != nil {
+			return nil, err
+		}
+		if err := bench.ResetStats(); err != nil {
+			return nil, fmt.Errorf("reset stats: %w", err)
+		}
+
+		fmt.Printf("Running %d primary key lookups at %.2fx shared_buffers...\n", numReads, fraction)
+		read, err := bench.LookupRecordsPgbench(numReads)
+		if err != nil {
+			return nil, fmt.Errorf("read records at %.2fx: %w", fraction, err)
+		}
+		point.ReadThroughput = float64(numReads) / read.Duration.Seconds()
+
+		point.HeapHitRatio, point.IndexHitRatio, err = bench.HeapIndexHitRatios()
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Printf("  %.2fx: %d rows, %.0f reads/s, heap hit %.1f%%, index hit %.1f%%\n",
+			fraction, point.Rows, point.ReadThroughput, point.HeapHitRatio*100, point.IndexHitRatio*100)
+		result.Points = append(result.Points, point)
+	}
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReindexMaintenance inserts numRecords rows in cycles bursts, rebuilding the primary key
+// with REINDEX INDEX CONCURRENTLY after each one, to measure how quickly fragmentation
+// re-accumulates and the cumulative cost of keeping the index compact
+func ReindexMaintenance(keyType string, numRecords, batchSize, cycles int) (*benchmark.ReindexMaintenanceResult, error) {
+	if numRecords < cycles {
+		return nil, fmt.Errorf("%d records cannot fill %d insert bursts", numRecords, cycles)
+	}
+
+	bench := postgres.New(Options)
+	bench.SetScenario("reindex-maintenance")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	if err := bench.CreateTable(keyType); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	result := &benchmark.ReindexMaintenanceResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		BatchSize:  batchSize,
+	}
+
+	var regrowth float64
+	reset := 0.0 // An empty index starts unfragmented
+
+	for i := 1; i <= cycles; i++ {
+		// The last burst takes the remainder, so all numRecords rows are inserted
+		burst := numRecords / cycles
+		if i == cycles {
+			burst += numRecords % cycles
+		}
+
+		fmt.Printf("Cycle %d/%d: inserting %d records (batch=%d)...\n", i, cycles, burst, batchSize)
+		if _, err := bench.InsertRecordsPgbench(keyType, burst, batchSize); err != nil {
+			return nil, fmt.Errorf("insert records (cycle %d): %w", i, err)
+		}
+
+		before, err := bench.IndexFragmentation()
+		if err != nil {
+			return nil, fmt.Errorf("measure fragmentation (cycle %d): %w", i, err)
+		}
+
+		duration, err := bench.ReindexConcurrently(bench.PKIndexName())
+		if err != nil {
+			return nil, fmt.Errorf("cycle %d: %w", i, err)
+		}
+
+		after, err := bench.IndexFragmentation()
+		if err != nil {
+			return nil, fmt.Errorf("measure fragmentation after reindex (cycle %d): %w", i, err)
+		}
+
+		cycle := benchmark.ReindexCycle{
+			RowsInserted:       burst,
+			FragmentationAfter: before.FragmentationPercent,
+			FragmentationReset: after.FragmentationPercent,
+			ReindexDuration:    duration,
+		}
+		result.Cycles = append(result.Cycles, cycle)
+		result.TotalReindexTime += duration
+		regrowth += (cycle.FragmentationAfter - reset) / float64(burst) * 100000
+		reset = cycle.FragmentationReset
+
+		fmt.Printf("Fragmentation %.2f%% -> %.2f%% after reindex (%s)\n", cycle.FragmentationAfter, cycle.FragmentationReset, duration.Round(time.Millisecond))
+	}
+
+	result.RegrowthPer100k = regrowth / float64(cycles)
+
+	fmt.Println("Measuring metrics...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.FinalIndexSize = metrics.IndexSize
+	result.FinalFragmentation = metrics.Fragmentation.FragmentationPercent
+
+	fmt.Printf("Regrowth: %.2f pp per 100k rows, total reindex time: %s\n", result.RegrowthPer100k, result.TotalReindexTime.Round(time.Millisecond))
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CommitOverhead inserts numRecords rows at each batch size into a fresh table and
+// fits time per row = perCommit/batch + perRow to separate commit cost from row cost
+func CommitOverhead(keyType string, numRecords int, batchSizes []int) (*benchmark.CommitOverheadResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("commit-overhead")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	result := &benchmark.CommitOverheadResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		BatchSizes: batchSizes,
+	}
+
+	inverseBatch := make([]float64, len(batchSizes))
+	secondsPerRow := make([]float64, len(batchSizes))
+
+	for i, batchSize := range batchSizes {
+		if err := bench.CreateTable(keyType); err != nil {
+			return nil, fmt.Errorf("create table: %w", err)
+		}
+
+		fmt.Printf("Inserting %d records (batch=%d)...\n", numRecords, batchSize)
+		duration, err := bench.InsertRecordsPgbench(keyType, numRecords, batchSize)
+		if err != nil {
+			return nil, fmt.Errorf("insert records (batch=%d): %w", batchSize, err)
+		}
+
+		throughput := float64(numRecords) / duration.Seconds()
+		result.Throughputs = append(result.Throughputs, throughput)
+		fmt.Printf("Throughput: %.2f records/sec\n", throughput)
+
+		inverseBatch[i] = 1 / float64(batchSize)
+		secondsPerRow[i] = 1 / throughput
+	}
+
+	perCommit, perRow, r2 := statistics.LinearFit(inverseBatch, secondsPerRow)
+	result.PerCommitOverhead = time.Duration(perCommit * float64(time.Second))
+	result.PerRowCost = time.Duration(perRow * float64(time.Second))
+	result.FitRSquared = r2
+
+	fmt.Printf("Per-commit overhead: %s, per-row cost: %s (R²=%.3f)\n", result.PerCommitOverhead, result.PerRowCost, r2)
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// InsertReturning inserts numRecords single-row transactions into a fresh table, and
+// repeats them with RETURNING id into another fresh one, checkpointing before each so
+// both phases start from the same state; which phase goes first alternates from run to run
+func InsertReturning(keyType string, numRecords, connections int) (*benchmark.InsertReturningResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("insert-returning")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	result := &benchmark.InsertReturningResult{
+		KeyType:     keyType,
+		NumRecords:  numRecords,
+		Connections: connections,
+	}
+
+	phases := []bool{false, true}
+	if reversePasses("insert-returning", keyType) {
+		slices.Reverse(phases)
+	}
+
+	for _, returning := range phases {
+		bench.SetReturning(returning)
+		if err := bench.CreateTable(keyType); err != nil {
+			return nil, fmt.Errorf("create table: %w", err)
+		}
+		if err := bench.Checkpoint(); err != nil {
+			return nil, err
+		}
+
+		label := "without RETURNING"
+		if returning {
+			label = "with RETURNING id"
+		}
+		fmt.Printf("Inserting %d records %s (connections=%d)...\n", numRecords, label, connections)
+
+		inserted, err := bench.InsertRecordsPgbenchConcurrent(keyType, numRecords, connections, 1)
+		if err != nil {
+			return nil, fmt.Errorf("insert records %s: %w", label, err)
+		}
+		fmt.Printf("Throughput: %.2f records/sec\n", inserted.Throughput)
+
+		if returning {
+			result.ReturningThroughput = inserted.Throughput
+			result.ReturningLatency = inserted.Latency
+		} else {
+			result.PlainThroughput = inserted.Throughput
+			result.PlainLatency = inserted.Latency
+		}
+	}
+
+	fmt.Printf("RETURNING overhead: %.1f%%\n", result.ReturningOverhead())
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpsertPerformance loads numRecords rows, then runs numUpserts single-row
+// INSERT ... ON CONFLICT (id) DO UPDATE transactions, conflictRatio percent of them on
+// existing ids. Page splits and fragmentation cover the upserts only: new ids land
+// wherever the key type puts them, while conflicts probe the loaded index.
+func UpsertPerformance(keyType string, numRecords, numUpserts, conflictRatio int) (*benchmark.UpsertPerformanceResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("upsert-performance")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	if err := bench.CreateTable(keyType); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	result := &benchmark.UpsertPerformanceResult{
+		KeyType:       keyType,
+		NumRecords:    numRecords,
+		NumUpserts:    numUpserts,
+		ConflictRatio: conflictRatio,
+	}
+
+	fmt.Printf("Inserting %d records...\n", numRecords)
+	if _, err := bench.InsertRecordsPgbench(keyType, numRecords, 100); err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	if err := bench.Checkpoint(); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Running %d upserts (%d%% on existing ids)...\n", numUpserts, conflictRatio)
+	duration, inserted, updated, err := bench.UpsertRecordsPgbench(keyType, numRecords, numUpserts, conflictRatio)
+	if err != nil {
+		return nil, fmt.Errorf("upsert records: %w", err)
+	}
+	result.Duration = duration
+	result.Throughput = float64(numUpserts) / duration.Seconds()
+	result.Inserted = inserted
+	result.Updated = updated
+	result.Latency = bench.LastLatency()
+
+	fmt.Printf("Completed %d upserts in %s (%d inserted, %d updated)\n", numUpserts, duration, inserted, updated)
+	fmt.Printf("Upsert throughput: %.2f ops/sec\n", result.Throughput)
+
+	fmt.Println("Measuring fragmentation...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.PageSplits = metrics.PageSplits
+	result.TableSize = metrics.TableSize
+	result.IndexSize = metrics.IndexSize
+	result.Fragmentation = metrics.Fragmentation
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RangeScanPerformance loads numRecords rows, then runs numScans scans each reading the
+// rangeSize rows that follow a random existing id in key order. Pages per scan and the
+// hit ratio come from the table's block counters over the measured scans, so they show
+// how many heap pages a key range is spread over.
+func RangeScanPerformance(keyType string, numRecords, numScans, rangeSize int) (*benchmark.RangeScanResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("range-scan")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	if err := bench.CreateTable(keyType); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	result := &benchmark.RangeScanResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		NumScans:   numScans,
+		RangeSize:  rangeSize,
+	}
+
+	fmt.Printf("Inserting %d records...\n", numRecords)
+	insertDuration, err := bench.InsertRecordsPgbench(keyType, numRecords, 100)
+	if err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	result.InsertDuration = insertDuration
+	fmt.Printf("Inserted %d records in %s\n", numRecords, insertDuration)
+
+	if err := bench.Checkpoint(); err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Measuring fragmentation...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.Fragmentation = metrics.Fragmentation
+	fmt.Printf("Index fragmentation: %.2f%%\n", metrics.Fragmentation.FragmentationPercent)
+
+	fmt.Printf("Running %d range scans of %d rows...\n", numScans, rangeSize)
+	bench.SetTimeBound(true)
+	scan, stats, err := bench.RangeScanPgbench(numScans, rangeSize)
+	if err != nil {
+		return nil, fmt.Errorf("range scan: %w", err)
+	}
+	numScans = scan.TotalOps
+	result.NumScans = numScans
+	result.ScanDuration = scan.Duration
+	result.ScanThroughput = float64(numScans) / scan.Duration.Seconds()
+	result.Latency = scan.Latency
+
+	blocks := stats.BlocksHit + stats.BlocksRead
+	if numScans > 0 {
+		result.RowsPerScan = float64(stats.RowsFetched) / float64(numScans)
+		result.PagesPerScan = float64(blocks) / float64(numScans)
+	}
+	if blocks > 0 {
+		result.BufferHitRatio = float64(stats.BlocksHit) / float64(blocks)
+	}
+
+	fmt.Printf("Completed %d range scans in %s\n", numScans, scan.Duration)
+	fmt.Printf("Scan throughput: %.2f scans/sec\n", result.ScanThroughput)
+	fmt.Printf("Rows per scan: %.1f, pages per scan: %.1f, buffer hit ratio: %.2f%%\n", result.RowsPerScan, result.PagesPerScan, result.BufferHitRatio*100)
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateChurn loads numRecords rows, then runs rounds of numUpdates random updates over
+// the same rows, sampling dead tuples and table/index bloat after loading and after each
+// round to chart how MVCC bloat accumulates (and is reclaimed, with -autovacuum on)
+func UpdateChurn(keyType string, numRecords, numUpdates, rounds int) (*benchmark.UpdateChurnResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("update-churn")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	if err := bench.CreateTable(keyType); err != nil {
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	result := &benchmark.UpdateChurnResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		NumUpdates: numUpdates,
+	}
+
+	fmt.Printf("Inserting %d records...\n", numRecords)
+	if _, err := bench.InsertRecordsPgbench(keyType, numRecords, 100); err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+
+	var bloat *postgres.Bloat
+	for round := 0; round <= rounds; round++ {
+		if round > 0 {
+			fmt.Printf("Round %d/%d: running %d updates...\n", round, rounds, numUpdates)
+			if _, err := bench.UpdateRecordsPgbench(keyType, numRecords, numUpdates, 1); err != nil {
+				return nil, fmt.Errorf("update records (round %d): %w", round, err)
+			}
+		}
+
+		var err error
+		bloat, err = bench.MeasureBloat()
+		if err != nil {
+			return nil, fmt.Errorf("measure bloat (round %d): %w", round, err)
+		}
+		tableSize, indexSize, err := bench.DiskUsage()
+		if err != nil {
+			return nil, err
+		}
+
+		sample := benchmark.UpdateChurnSample{
+			Updates:    round * numUpdates,
+			DeadTuples: bloat.DeadTuples,
+			TableBloat: bloat.TableBloat,
+			IndexBloat: bloat.IndexBloat,
+			TableSize:  tableSize,
+			IndexSize:  indexSize,
+		}
+		result.Samples = append(result.Samples, sample)
+
+		fmt.Printf("  %d updates: %d dead tuples, table bloat %.1f%%, index bloat %.1f%%, index %s\n",
+			sample.Updates, sample.DeadTuples, sample.TableBloat, sample.IndexBloat, benchmark.FormatBytes(indexSize))
+	}
+
+	if bloat.Updates > 0 {
+		result.HOTUpdateRatio = float64(bloat.HOTUpdates) / float64(bloat.Updates) * 100
+	}
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ConnectionScaling measures connection establishment at each client count with a
+// SELECT 1 script: once on persistent connections, for the initial connection time and
+// the query rate, and once reconnecting per transaction, for the connection rate. The
+// transactions are split evenly across the clients of each count.
+func ConnectionScaling(clientCounts []int, transactions int) (*benchmark.ConnectionScalingResult, error) {
+	bench := postgres.New(Options)
+	bench.SetScenario("connection-scaling")
+
+	if err := bench.Connect(); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer bench.Close()
+
+	result := &benchmark.ConnectionScalingResult{Transactions: transactions}
+
+	for _, clients := range clientCounts {
+		perClient := max(transactions/clients, 1)
+		fmt.Printf("Clients %d: %d transactions each, persistent then reconnecting...\n", clients, perClient)
+
+		persistent, err := bench.ConnectPgbench(clients, perClient, false)
+		if err != nil {
+			return nil, fmt.Errorf("persistent connections (%d clients): %w", clients, err)
+		}
+		reconnecting, err := bench.ConnectPgbench(clients, perClient, true)
+		if err != nil {
+			return nil, fmt.Errorf("reconnecting (%d clients): %w", clients, err)
+		}
+
+		point := benchmark.ConnectionScalingPoint{
+			Clients:               clients,
+			InitialConnectionTime: persistent.InitialConnectionTime,
+			AvgConnectionTime:     reconnecting.AvgConnectionTime,
+			ConnectionsPerSec:     reconnecting.TPS,
+			QueryTPS:              persistent.TPS,
+		}
+		result.Points = append(result.Points, point)
+
+		fmt.Printf("  initial connect %s, %.0f connections/s (avg %s), %.0f queries/s persistent\n",
+			point.InitialConnectionTime.Round(time.Microsecond), point.ConnectionsPerSec,
+			point.AvgConnectionTime.Round(time.Microsecond), point.QueryTPS)
+	}
+
 	return result, nil
 }
@@ -0,0 +1,189 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/moguls753/uuid-benchmark/internal/benchmark"
+	"github.com/moguls753/uuid-benchmark/internal/benchmark/sqlite"
+)
+
+// connectSQLite opens a SQLite benchmarker on a fresh database file and table
+func connectSQLite(keyType string) (*sqlite.SQLiteBenchmarker, error) {
+	bench := sqlite.New(sqlite.Options{Seed: Options.Seed, PayloadBytes: Options.DataColumn.PayloadBytes})
+
+	if err := bench.Connect(); err != nil {
+		bench.Close()
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+
+	if err := bench.CreateTable(keyType); err != nil {
+		bench.Close()
+		return nil, fmt.Errorf("create table: %w", err)
+	}
+
+	return bench, nil
+}
+
+// reportFileStats prints and returns the database file's page count and freelist, the
+// pages allocated but unused; both are zero if they could not be read
+func reportFileStats(bench *sqlite.SQLiteBenchmarker) (pages, freePages int64) {
+	pages, freePages, err := bench.FileStats()
+	if err != nil {
+		fmt.Printf("Warning: Could not read file stats: %v\n", err)
+		return 0, 0
+	}
+	fmt.Printf("Database file: %d pages, %d on the freelist\n", pages, freePages)
+	return pages, freePages
+}
+
+// sqliteInsertPerformance is InsertPerformance against an in-process SQLite file,
+// without container I/O, CPU or WAL metrics
+func sqliteInsertPerformance(keyType string, numRecords, batchSize int) (*benchmark.InsertPerformanceResult, error) {
+	bench, err := connectSQLite(keyType)
+	if err != nil {
+		return nil, err
+	}
+	defer bench.Close()
+
+	result := &benchmark.InsertPerformanceResult{
+		KeyType:     keyType,
+		NumRecords:  numRecords,
+		BatchSize:   batchSize,
+		Connections: 1,
+		InsertMode:  "sqlite",
+	}
+
+	fmt.Printf("Inserting %d records into SQLite (batch=%d)...\n", numRecords, batchSize)
+	insert, err := bench.InsertRecords(numRecords, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	result.Duration = insert.Duration
+	result.Throughput = insert.Throughput
+	result.TPS = float64(insert.SuccessCount) / insert.Duration.Seconds()
+	result.Latency = insert.Latency
+
+	fmt.Printf("Inserted %d records in %s\n", numRecords, result.Duration)
+	fmt.Printf("Throughput: %.2f records/sec\n", result.Throughput)
+
+	fmt.Println("Measuring metrics...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.FilePages, result.FreelistPages = reportFileStats(bench)
+
+	result.TableSize = metrics.TableSize
+	result.IndexSize = metrics.IndexSize
+	result.PKIndexSize = metrics.IndexSize
+	if metrics.TableSize > 0 {
+		result.IndexTableRatio = float64(metrics.IndexSize) / float64(metrics.TableSize)
+	}
+	result.Fragmentation = metrics.Fragmentation
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// sqliteReadAfterFragmentation is ReadAfterFragmentation against an in-process SQLite
+// file; SQLite exposes no buffer hit counters, so those stay zero
+func sqliteReadAfterFragmentation(keyType string, numRecords, numReads int) (*benchmark.ReadAfterFragmentationResult, error) {
+	bench, err := connectSQLite(keyType)
+	if err != nil {
+		return nil, err
+	}
+	defer bench.Close()
+
+	result := &benchmark.ReadAfterFragmentationResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		NumReads:   numReads,
+		QueryMode:  "extended",
+	}
+
+	fmt.Printf("Inserting %d records to create index...\n", numRecords)
+	insert, err := bench.InsertRecords(numRecords, 100)
+	if err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	result.InsertDuration = insert.Duration
+	fmt.Printf("Inserted %d records in %s\n", numRecords, insert.Duration)
+
+	fmt.Println("Measuring fragmentation...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.Fragmentation = metrics.Fragmentation
+	fmt.Printf("Index fragmentation: %.2f%%\n", metrics.Fragmentation.FragmentationPercent)
+
+	fmt.Printf("Running %d point lookups...\n", numReads)
+	read, err := bench.ReadRecords(numReads)
+	if err != nil {
+		return nil, fmt.Errorf("read records: %w", err)
+	}
+	result.ReadDuration = read.Duration
+	result.ReadThroughput = read.Throughput
+	result.Latency = read.Latency
+
+	fmt.Printf("Completed %d reads in %s\n", numReads, read.Duration)
+	fmt.Printf("Read throughput: %.2f ops/sec\n", result.ReadThroughput)
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// sqliteUpdatePerformance is UpdatePerformance against an in-process SQLite file, one
+// row per update
+func sqliteUpdatePerformance(keyType string, numRecords, numUpdates int) (*benchmark.UpdatePerformanceResult, error) {
+	bench, err := connectSQLite(keyType)
+	if err != nil {
+		return nil, err
+	}
+	defer bench.Close()
+
+	result := &benchmark.UpdatePerformanceResult{
+		KeyType:    keyType,
+		NumRecords: numRecords,
+		NumUpdates: numUpdates,
+		BatchSize:  1,
+	}
+
+	fmt.Printf("Inserting %d records...\n", numRecords)
+	if _, err := bench.InsertRecords(numRecords, 100); err != nil {
+		return nil, fmt.Errorf("insert records: %w", err)
+	}
+	fmt.Printf("Inserted %d records\n", numRecords)
+
+	fmt.Printf("Running %d updates...\n", numUpdates)
+	update, err := bench.UpdateRecords(numUpdates)
+	if err != nil {
+		return nil, fmt.Errorf("update records: %w", err)
+	}
+	result.UpdateDuration = update.Duration
+	result.UpdateThroughput = update.Throughput
+	result.Latency = update.Latency
+
+	fmt.Printf("Completed %d updates in %s\n", numUpdates, update.Duration)
+	fmt.Printf("Update throughput: %.2f ops/sec\n", result.UpdateThroughput)
+
+	fmt.Println("Measuring fragmentation...")
+	metrics, err := bench.MeasureMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("measure metrics: %w", err)
+	}
+	result.Fragmentation = metrics.Fragmentation
+	result.FilePages, result.FreelistPages = reportFileStats(bench)
+
+	if err := validate(keyType, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
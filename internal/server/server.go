@@ -0,0 +1,188 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moguls753/uuid-benchmark/internal/container"
+)
+
+// Config is the body of a POST /benchmark request; zero values fall back to the
+// server's defaults
+type Config struct {
+	Scenario    string   `json:"scenario"`
+	KeyTypes    []string `json:"key_types,omitempty"`
+	NumRecords  int      `json:"num_records,omitempty"`
+	NumOps      int      `json:"num_ops,omitempty"`
+	Connections int      `json:"connections,omitempty"`
+	BatchSize   int      `json:"batch_size,omitempty"`
+}
+
+// Response is returned by POST /benchmark with one result per key type
+type Response struct {
+	Config   Config         `json:"config"`
+	Started  time.Time      `json:"started"`
+	Duration string         `json:"duration"`
+	Results  map[string]any `json:"results"`
+}
+
+// ScenarioFunc runs one scenario for a single key type against a freshly started container
+type ScenarioFunc func(cfg Config, keyType string) (any, error)
+
+// Server runs benchmarks on request. All runs share the one benchmark container, so
+// requests are serialized: a request arriving during a run waits for it to finish.
+type Server struct {
+	mu        sync.Mutex
+	keyTypes  []string
+	scenarios map[string]ScenarioFunc
+	defaults  Config
+}
+
+// New creates a server offering the given key types and scenarios
+func New(keyTypes []string, scenarios map[string]ScenarioFunc, defaults Config) *Server {
+	return &Server{
+		keyTypes:  keyTypes,
+		scenarios: scenarios,
+		defaults:  defaults,
+	}
+}
+
+// Handler returns the HTTP routes: POST /benchmark, GET /keytypes, GET /scenarios
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /benchmark", s.handleBenchmark)
+	mux.HandleFunc("GET /keytypes", s.handleKeyTypes)
+	mux.HandleFunc("GET /scenarios", s.handleScenarios)
+	return mux
+}
+
+// ListenAndServe serves the API on addr until the listener fails
+func (s *Server) ListenAndServe(addr string) error {
+	fmt.Printf("Serving benchmark API on %s\n", addr)
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+func (s *Server) handleKeyTypes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.keyTypes)
+}
+
+func (s *Server) handleScenarios(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(s.scenarios))
+	for name := range s.scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeJSON(w, http.StatusOK, names)
+}
+
+func (s *Server) handleBenchmark(w http.ResponseWriter, r *http.Request) {
+	var cfg Config
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decode config: %w", err))
+		return
+	}
+
+	cfg, err := s.resolve(cfg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log.Printf("Running %s for %v", cfg.Scenario, cfg.KeyTypes)
+	started := time.Now()
+	results, err := s.run(cfg)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Response{
+		Config:   cfg,
+		Started:  started,
+		Duration: time.Since(started).Round(time.Millisecond).String(),
+		Results:  results,
+	})
+}
+
+// resolve validates the scenario and key types and fills unset fields from the defaults
+func (s *Server) resolve(cfg Config) (Config, error) {
+	if _, ok := s.scenarios[cfg.Scenario]; !ok {
+		return cfg, fmt.Errorf("unknown scenario %q (see GET /scenarios)", cfg.Scenario)
+	}
+
+	if len(cfg.KeyTypes) == 0 {
+		cfg.KeyTypes = s.keyTypes
+	}
+	for _, keyType := range cfg.KeyTypes {
+		if !slices.Contains(s.keyTypes, keyType) {
+			return cfg, fmt.Errorf("unknown key type %q (see GET /keytypes)", keyType)
+		}
+	}
+
+	if cfg.NumRecords == 0 {
+		cfg.NumRecords = s.defaults.NumRecords
+	}
+	if cfg.NumOps == 0 {
+		cfg.NumOps = s.defaults.NumOps
+	}
+	if cfg.Connections == 0 {
+		cfg.Connections = s.defaults.Connections
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = s.defaults.BatchSize
+	}
+	if cfg.NumRecords < 0 || cfg.NumOps < 0 || cfg.Connections < 0 || cfg.BatchSize < 0 {
+		return cfg, fmt.Errorf("num_records, num_ops, connections and batch_size must not be negative")
+	}
+
+	return cfg, nil
+}
+
+// run executes the scenario for each key type in a fresh container, like the CLI does
+func (s *Server) run(cfg Config) (map[string]any, error) {
+	scenario := s.scenarios[cfg.Scenario]
+	results := make(map[string]any)
+
+	for _, keyType := range cfg.KeyTypes {
+		fmt.Printf("\nTesting %s\n", strings.ToUpper(keyType))
+		fmt.Println(strings.Repeat("-", 70))
+
+		if err := container.TryStart(container.PostgresConfig); err != nil {
+			return nil, err
+		}
+
+		result, err := scenario(cfg, keyType)
+		container.Stop(container.PostgresConfig.ComposeFile)
+		if err != nil {
+			return nil, fmt.Errorf("scenario failed for %s: %w", keyType, err)
+		}
+
+		results[keyType] = result
+	}
+
+	return results, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Warning: Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
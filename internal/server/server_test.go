@@ -0,0 +1,145 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moguls753/uuid-benchmark/internal/container"
+)
+
+// newTestServer serves two fake scenarios; "fail" always errors and "echo" returns the
+// resolved config. No compose file is set, so runs start and stop no container.
+func newTestServer(t *testing.T) http.Handler {
+	t.Helper()
+
+	saved := container.PostgresConfig
+	container.PostgresConfig = container.Config{Name: "PostgreSQL"}
+	t.Cleanup(func() { container.PostgresConfig = saved })
+
+	scenarios := map[string]ScenarioFunc{
+		"echo": func(cfg Config, keyType string) (any, error) {
+			return cfg, nil
+		},
+		"fail": func(cfg Config, keyType string) (any, error) {
+			return nil, errors.New("boom")
+		},
+	}
+	defaults := Config{NumRecords: 1000, NumOps: 100, Connections: 1, BatchSize: 1}
+	return New([]string{"bigserial", "uuidv7"}, scenarios, defaults).Handler()
+}
+
+func serve(handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+func TestListEndpoints(t *testing.T) {
+	handler := newTestServer(t)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/keytypes", []string{"bigserial", "uuidv7"}},
+		{"/scenarios", []string{"echo", "fail"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := serve(handler, http.MethodGet, tt.path, "")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			var got []string
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBenchmarkRejectsInvalidConfig(t *testing.T) {
+	handler := newTestServer(t)
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"malformed json", `{"scenario":`, "decode config"},
+		{"unknown field", `{"scenario": "echo", "records": 10}`, "decode config"},
+		{"unknown scenario", `{"scenario": "nope"}`, `unknown scenario "nope"`},
+		{"unknown key type", `{"scenario": "echo", "key_types": ["uuidv4"]}`, `unknown key type "uuidv4"`},
+		{"negative value", `{"scenario": "echo", "num_ops": -1}`, "must not be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(handler, http.MethodPost, "/benchmark", tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+
+			var got map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !strings.Contains(got["error"], tt.want) {
+				t.Errorf("error = %q, want it to contain %q", got["error"], tt.want)
+			}
+		})
+	}
+}
+
+func TestBenchmarkRunsScenario(t *testing.T) {
+	handler := newTestServer(t)
+
+	rec := serve(handler, http.MethodPost, "/benchmark", `{"scenario": "echo", "key_types": ["uuidv7"], "num_records": 50}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var got struct {
+		Config  Config            `json:"config"`
+		Results map[string]Config `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	// Set fields are kept, unset ones come from the defaults
+	want := Config{Scenario: "echo", KeyTypes: []string{"uuidv7"}, NumRecords: 50, NumOps: 100, Connections: 1, BatchSize: 1}
+	if !reflect.DeepEqual(got.Config, want) {
+		t.Errorf("config = %+v, want %+v", got.Config, want)
+	}
+	if len(got.Results) != 1 || !reflect.DeepEqual(got.Results["uuidv7"], want) {
+		t.Errorf("results = %+v, want only uuidv7 with %+v", got.Results, want)
+	}
+}
+
+func TestBenchmarkReportsScenarioFailure(t *testing.T) {
+	handler := newTestServer(t)
+
+	rec := serve(handler, http.MethodPost, "/benchmark", `{"scenario": "fail"}`)
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if want := "scenario failed for bigserial: boom"; got["error"] != want {
+		t.Errorf("error = %q, want %q", got["error"], want)
+	}
+}
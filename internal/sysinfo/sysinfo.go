@@ -0,0 +1,68 @@
+package sysinfo
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Fingerprint identifies the machine a benchmark ran on, so results from different
+// hosts can be told apart when they are stored together
+type Fingerprint struct {
+	Hostname  string `json:"hostname"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	Kernel    string `json:"kernel"`
+	CPUs      int    `json:"cpus"`
+	MemoryMB  int64  `json:"memory_mb"`
+	GoVersion string `json:"go_version"`
+}
+
+// Collect gathers the fingerprint of the current host; fields that cannot be read
+// are left empty
+func Collect() Fingerprint {
+	hostname, _ := os.Hostname()
+
+	return Fingerprint{
+		Hostname:  hostname,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Kernel:    readKernelRelease(),
+		CPUs:      runtime.NumCPU(),
+		MemoryMB:  readTotalMemoryMB(),
+		GoVersion: runtime.Version(),
+	}
+}
+
+func readKernelRelease() string {
+	data, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readTotalMemoryMB reads MemTotal from /proc/meminfo ("MemTotal:  16318412 kB")
+func readTotalMemoryMB() int64 {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kb / 1024
+		}
+	}
+
+	return 0
+}